@@ -0,0 +1,221 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AgentConfig is the structured form of the common sections of the CloudWatch agent JSON configuration. The operator
+// renders it to JSON and merges it over spec.config, so that its keys can be set with server-side apply or kustomize
+// patches.
+type AgentConfig struct {
+	// Agent is the agent section of the configuration, like region or debug.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Agent *AnyConfig `json:"agent,omitempty"`
+	// Metrics is the metrics section of the configuration.
+	// +optional
+	Metrics *AgentMetrics `json:"metrics,omitempty"`
+	// Logs is the logs section of the configuration.
+	// +optional
+	Logs *AgentLogs `json:"logs,omitempty"`
+	// Traces is the traces section of the configuration.
+	// +optional
+	Traces *AgentTraces `json:"traces,omitempty"`
+	// ApplicationSignals enables Application Signals, rendered to both logs.metrics_collected.application_signals and
+	// traces.traces_collected.application_signals.
+	// +optional
+	ApplicationSignals *AgentApplicationSignals `json:"applicationSignals,omitempty"`
+}
+
+// AgentMetrics is the metrics section of the CloudWatch agent configuration.
+type AgentMetrics struct {
+	// MetricsCollected configures the metric inputs, keyed by input like statsd, collectd or otlp, as in
+	// metrics.metrics_collected.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	MetricsCollected *AnyConfig `json:"metricsCollected,omitempty"`
+}
+
+// AgentLogs is the logs section of the CloudWatch agent configuration.
+type AgentLogs struct {
+	// MetricsCollected configures the inputs of logs.metrics_collected.
+	// +optional
+	MetricsCollected *AgentLogsMetricsCollected `json:"metricsCollected,omitempty"`
+	// LogsCollected configures the log inputs, as in logs.logs_collected.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	LogsCollected *AnyConfig `json:"logsCollected,omitempty"`
+}
+
+// AgentLogsMetricsCollected configures the inputs of logs.metrics_collected.
+type AgentLogsMetricsCollected struct {
+	// Kubernetes configures Container Insights.
+	// +optional
+	Kubernetes *AgentKubernetes `json:"kubernetes,omitempty"`
+	// EMF configures the embedded metric format input.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	EMF *AnyConfig `json:"emf,omitempty"`
+	// OTLP configures the OTLP input of the metrics sent as logs.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	OTLP *AnyConfig `json:"otlp,omitempty"`
+}
+
+// AgentKubernetes configures Container Insights, as in logs.metrics_collected.kubernetes.
+type AgentKubernetes struct {
+	// ClusterName is the name of the cluster the metrics are reported for.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// EnhancedContainerInsights enables Container Insights with enhanced observability.
+	// +optional
+	EnhancedContainerInsights *bool `json:"enhancedContainerInsights,omitempty"`
+	// AcceleratedComputeMetrics enables the metrics of the GPUs and Neuron devices, along with enhanced observability.
+	// +optional
+	AcceleratedComputeMetrics *bool `json:"acceleratedComputeMetrics,omitempty"`
+	// JMXContainerInsights enables the JMX metrics of Container Insights.
+	// +optional
+	JMXContainerInsights *bool `json:"jmxContainerInsights,omitempty"`
+	// MetricsCollectionInterval is how often the metrics are collected, in seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MetricsCollectionInterval *int32 `json:"metricsCollectionInterval,omitempty"`
+}
+
+// AgentTraces is the traces section of the CloudWatch agent configuration.
+type AgentTraces struct {
+	// TracesCollected configures the trace inputs, keyed by input like xray or otlp, as in traces.traces_collected.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	TracesCollected *AnyConfig `json:"tracesCollected,omitempty"`
+}
+
+// AgentApplicationSignals configures Application Signals.
+type AgentApplicationSignals struct {
+	// HostedIn is the environment the services are reported in, the name of the cluster by default.
+	// +optional
+	HostedIn string `json:"hostedIn,omitempty"`
+	// TLS configures the certificate served by the Application Signals endpoints.
+	// +optional
+	TLS *AgentTLS `json:"tls,omitempty"`
+}
+
+// AgentTLS references the certificate and key files of an endpoint of the agent.
+type AgentTLS struct {
+	// CertFile is the path of the certificate.
+	CertFile string `json:"certFile"`
+	// KeyFile is the path of the private key.
+	KeyFile string `json:"keyFile"`
+}
+
+// MergeInto renders the configuration and merges it over the raw JSON configuration, the keys of the structured
+// configuration taking precedence.
+func (a *AgentConfig) MergeInto(config string) (string, error) {
+	merged := map[string]interface{}{}
+	if config != "" {
+		if err := json.Unmarshal([]byte(config), &merged); err != nil {
+			return "", fmt.Errorf("couldn't parse cloudwatch agent json configuration: %w", err)
+		}
+	}
+	if a != nil {
+		mergeMaps(merged, a.render())
+	}
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// render converts the configuration to the keys of the CloudWatch agent JSON configuration.
+func (a *AgentConfig) render() map[string]interface{} {
+	out := map[string]interface{}{}
+	setAny(out, a.Agent, "agent")
+	if a.Metrics != nil {
+		setAny(out, a.Metrics.MetricsCollected, "metrics", "metrics_collected")
+	}
+	if a.Logs != nil {
+		if collected := a.Logs.MetricsCollected; collected != nil {
+			if k8s := collected.Kubernetes; k8s != nil {
+				kubernetes := map[string]interface{}{}
+				if k8s.ClusterName != "" {
+					kubernetes["cluster_name"] = k8s.ClusterName
+				}
+				setBool(kubernetes, "enhanced_container_insights", k8s.EnhancedContainerInsights)
+				setBool(kubernetes, "accelerated_compute_metrics", k8s.AcceleratedComputeMetrics)
+				setBool(kubernetes, "jmx_container_insights", k8s.JMXContainerInsights)
+				if k8s.MetricsCollectionInterval != nil {
+					kubernetes["metrics_collection_interval"] = *k8s.MetricsCollectionInterval
+				}
+				setPath(out, kubernetes, "logs", "metrics_collected", "kubernetes")
+			}
+			setAny(out, collected.EMF, "logs", "metrics_collected", "emf")
+			setAny(out, collected.OTLP, "logs", "metrics_collected", "otlp")
+		}
+		setAny(out, a.Logs.LogsCollected, "logs", "logs_collected")
+	}
+	if a.Traces != nil {
+		setAny(out, a.Traces.TracesCollected, "traces", "traces_collected")
+	}
+	if appSignals := a.ApplicationSignals; appSignals != nil {
+		for _, path := range [][]string{{"logs", "metrics_collected"}, {"traces", "traces_collected"}} {
+			rendered := map[string]interface{}{}
+			if appSignals.HostedIn != "" {
+				rendered["hosted_in"] = appSignals.HostedIn
+			}
+			if appSignals.TLS != nil {
+				rendered["tls"] = map[string]interface{}{"cert_file": appSignals.TLS.CertFile, "key_file": appSignals.TLS.KeyFile}
+			}
+			setPath(out, rendered, append(path, "application_signals")...)
+		}
+	}
+	return out
+}
+
+// setPath sets the value at the path of keys, adding the missing objects along the path.
+func setPath(out map[string]interface{}, value interface{}, path ...string) {
+	parent := out
+	for _, key := range path[:len(path)-1] {
+		child, ok := parent[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			parent[key] = child
+		}
+		parent = child
+	}
+	parent[path[len(path)-1]] = value
+}
+
+func setAny(out map[string]interface{}, value *AnyConfig, path ...string) {
+	if value == nil {
+		return
+	}
+	object := map[string]interface{}{}
+	for k, v := range value.Object {
+		object[k] = v
+	}
+	setPath(out, object, path...)
+}
+
+func setBool(parent map[string]interface{}, key string, value *bool) {
+	if value != nil {
+		parent[key] = *value
+	}
+}
+
+// mergeMaps merges src into dst recursively, the values of src replacing the values of dst which aren't objects.
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
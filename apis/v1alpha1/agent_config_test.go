@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestAgentConfigMergeInto(t *testing.T) {
+	tests := []struct {
+		name     string
+		agent    *AgentConfig
+		config   string
+		expected string
+	}{
+		{
+			name:     "no agent",
+			config:   `{"agent":{"region":"us-west-2"}}`,
+			expected: `{"agent":{"region":"us-west-2"}}`,
+		},
+		{
+			name: "no config",
+			agent: &AgentConfig{
+				Agent: &AnyConfig{Object: map[string]interface{}{"region": "us-west-2"}},
+				Traces: &AgentTraces{TracesCollected: &AnyConfig{Object: map[string]interface{}{
+					"xray": map[string]interface{}{"bind_address": "0.0.0.0:2000"},
+				}}},
+			},
+			expected: `{"agent":{"region":"us-west-2"},"traces":{"traces_collected":{"xray":{"bind_address":"0.0.0.0:2000"}}}}`,
+		},
+		{
+			name: "typed sections",
+			agent: &AgentConfig{
+				Metrics: &AgentMetrics{MetricsCollected: &AnyConfig{Object: map[string]interface{}{"statsd": map[string]interface{}{}}}},
+				Logs: &AgentLogs{MetricsCollected: &AgentLogsMetricsCollected{
+					Kubernetes: &AgentKubernetes{
+						ClusterName:               "cluster",
+						EnhancedContainerInsights: ptr.To(true),
+						AcceleratedComputeMetrics: ptr.To(false),
+						MetricsCollectionInterval: ptr.To[int32](30),
+					},
+					EMF: &AnyConfig{},
+				}},
+				ApplicationSignals: &AgentApplicationSignals{
+					HostedIn: "env",
+					TLS:      &AgentTLS{CertFile: "/etc/tls/tls.crt", KeyFile: "/etc/tls/tls.key"},
+				},
+			},
+			expected: `{
+				"metrics": {"metrics_collected": {"statsd": {}}},
+				"logs": {"metrics_collected": {
+					"kubernetes": {"cluster_name": "cluster", "enhanced_container_insights": true, "accelerated_compute_metrics": false, "metrics_collection_interval": 30},
+					"emf": {},
+					"application_signals": {"hosted_in": "env", "tls": {"cert_file": "/etc/tls/tls.crt", "key_file": "/etc/tls/tls.key"}}
+				}},
+				"traces": {"traces_collected": {
+					"application_signals": {"hosted_in": "env", "tls": {"cert_file": "/etc/tls/tls.crt", "key_file": "/etc/tls/tls.key"}}
+				}}
+			}`,
+		},
+		{
+			name: "agent keys take precedence",
+			agent: &AgentConfig{
+				Agent: &AnyConfig{Object: map[string]interface{}{"debug": true}},
+				Logs: &AgentLogs{MetricsCollected: &AgentLogsMetricsCollected{
+					Kubernetes: &AgentKubernetes{EnhancedContainerInsights: ptr.To(false)},
+				}},
+				ApplicationSignals: &AgentApplicationSignals{},
+			},
+			config: `{"agent":{"region":"us-west-2","debug":false},"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"cluster","enhanced_container_insights":true}},"force_flush_interval":5}}`,
+			expected: `{
+				"agent": {"region": "us-west-2", "debug": true},
+				"logs": {
+					"metrics_collected": {"kubernetes": {"cluster_name": "cluster", "enhanced_container_insights": false}, "application_signals": {}},
+					"force_flush_interval": 5
+				},
+				"traces": {"traces_collected": {"application_signals": {}}}
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := tt.agent.MergeInto(tt.config)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, merged)
+		})
+	}
+}
+
+func TestAgentConfigMergeIntoInvalidConfig(t *testing.T) {
+	_, err := (&AgentConfig{}).MergeInto("{")
+	assert.ErrorContains(t, err, "couldn't parse cloudwatch agent json configuration")
+}
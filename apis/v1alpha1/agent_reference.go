@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+const (
+	// DefaultAgentReferenceName is the name of the AmazonCloudWatchAgent referenced when no agentRef is set.
+	DefaultAgentReferenceName = "cloudwatch-agent"
+	// DefaultAgentReferenceNamespace is the namespace of the AmazonCloudWatchAgent referenced when no agentRef is set.
+	DefaultAgentReferenceNamespace = "amazon-cloudwatch"
+)
+
+// AgentReference points to the AmazonCloudWatchAgent whose configuration enables a component.
+type AgentReference struct {
+	// Name of the AmazonCloudWatchAgent.
+	// +required
+	Name string `json:"name"`
+	// Namespace of the AmazonCloudWatchAgent. Defaults to the namespace of the referencing resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ResolveAgentReference returns the name and namespace of the AmazonCloudWatchAgent referenced by ref from a resource
+// in namespace. A nil ref references the default amazon-cloudwatch/cloudwatch-agent.
+func ResolveAgentReference(ref *AgentReference, namespace string) (name string, ns string) {
+	if ref == nil {
+		return DefaultAgentReferenceName, DefaultAgentReferenceNamespace
+	}
+	if len(ref.Namespace) == 0 {
+		return ref.Name, namespace
+	}
+	return ref.Name, ref.Namespace
+}
@@ -39,7 +39,7 @@ const (
 // SEE: AmazonCloudWatchAgent.spec.ports[index].
 type Ingress struct {
 	// Type default value is: ""
-	// Supported types are: ingress, route
+	// Supported types are: ingress, route, gateway
 	Type IngressType `json:"type,omitempty"`
 
 	// RuleType defines how Ingress exposes collector receivers.
@@ -71,6 +71,11 @@ type Ingress struct {
 	// type "route" is used.
 	// +optional
 	Route OpenShiftRoute `json:"route,omitempty"`
+
+	// Gateway is a Gateway API specific section that is only considered when
+	// type "gateway" is used.
+	// +optional
+	Gateway *GatewayRoute `json:"gateway,omitempty"`
 }
 
 // OpenShiftRoute defines openshift route specific settings.
@@ -79,6 +84,46 @@ type OpenShiftRoute struct {
 	Termination TLSRouteTerminationType `json:"termination,omitempty"`
 }
 
+// GatewayRoute defines the Gateway API specific settings. An HTTPRoute is created for each receiver port with the
+// http app protocol, and a GRPCRoute for each one with the grpc app protocol, like the OTLP ports.
+type GatewayRoute struct {
+	// ParentRef is the Gateway the routes are attached to.
+	// +required
+	ParentRef GatewayParentReference `json:"parentRef"`
+
+	// BackendTLS makes the Gateway connect to the collector over TLS, for receivers serving TLS.
+	// +optional
+	BackendTLS *GatewayBackendTLS `json:"backendTLS,omitempty"`
+}
+
+// GatewayParentReference references the Gateway the routes are attached to.
+type GatewayParentReference struct {
+	// Name of the Gateway.
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the namespace of the AmazonCloudWatchAgent.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the Gateway listener the routes are attached to. The routes are attached to all
+	// the compatible listeners by default. TLS is terminated by the Gateway when the listener uses HTTPS.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// GatewayBackendTLS configures the BackendTLSPolicy validating the certificate served by the collector.
+type GatewayBackendTLS struct {
+	// Hostname is used for SNI and must match the certificate served by the collector.
+	// +required
+	Hostname string `json:"hostname"`
+
+	// CACertificateRefs are the ConfigMaps holding, in their ca.crt key, the CA certificates validating the
+	// certificate served by the collector. The system CA certificates are used when empty.
+	// +optional
+	CACertificateRefs []v1.LocalObjectReference `json:"caCertificateRefs,omitempty"`
+}
+
 // AmazonCloudWatchAgentSpec defines the desired state of AmazonCloudWatchAgent.
 type AmazonCloudWatchAgentSpec struct {
 	// ManagementState defines if the CR should be managed by the operator or not.
@@ -149,6 +194,11 @@ type AmazonCloudWatchAgentSpec struct {
 	// Mode represents how the collector should be deployed (deployment, daemonset, statefulset or sidecar)
 	// +optional
 	Mode Mode `json:"mode,omitempty"`
+	// NativeSidecar injects the agent in sidecar mode as a Kubernetes native sidecar, an init container with
+	// restartPolicy Always which is started before the containers of the pod and doesn't keep Jobs from completing.
+	// The agent is injected as a regular container when the API server doesn't support native sidecars.
+	// +optional
+	NativeSidecar bool `json:"nativeSidecar,omitempty"`
 	// ServiceAccount indicates the name of an existing service account to use with this instance. When set,
 	// the operator will not automatically create a ServiceAccount for the collector.
 	// +optional
@@ -176,6 +226,16 @@ type AmazonCloudWatchAgentSpec struct {
 	// Config is the raw YAML to be used as the collector's configuration. Refer to the OpenTelemetry Collector documentation for details.
 	// +optional
 	OtelConfig string `json:"otelConfig,omitempty"`
+	// Agent is the structured form of the common sections of the agent configuration. It is rendered to JSON and
+	// merged over spec.config, or the configuration read through spec.configFrom, its keys taking precedence.
+	// +optional
+	Agent *AgentConfig `json:"agent,omitempty"`
+	// ConfigFrom reads the raw JSON configuration from a ConfigMap or Secret key instead of spec.config.
+	// +optional
+	ConfigFrom *ConfigSource `json:"configFrom,omitempty"`
+	// OtelConfigFrom reads the raw YAML configuration from a ConfigMap or Secret key instead of spec.otelConfig.
+	// +optional
+	OtelConfigFrom *ConfigSource `json:"otelConfigFrom,omitempty"`
 	// VolumeMounts represents the mount points to use in the underlying collector deployment(s)
 	// +optional
 	// +listType=atomic
@@ -285,13 +345,25 @@ type AmazonCloudWatchAgentSpec struct {
 	// This is only applicable to Deployment mode.
 	// +optional
 	DeploymentUpdateStrategy appsv1.DeploymentStrategy `json:"deploymentUpdateStrategy,omitempty"`
+	// RolloutStrategy stages the rollout of the changes of spec.config and spec.otelConfig, instead of letting the
+	// DaemonSet roll them out to every node at once.
+	// This is only applicable to Daemonset mode.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// StatefulSetRecreatePolicy defines how the StatefulSet is recreated when an immutable field of its spec changes.
+	// DeleteAndRecreate, the default, deletes the StatefulSet along with its pods. OrphanAndRecreate deletes the
+	// StatefulSet only, and the recreated StatefulSet takes over the existing pods and persistent volume claims.
+	// This is only applicable to Statefulset mode.
+	// +optional
+	StatefulSetRecreatePolicy StatefulSetRecreatePolicy `json:"statefulSetRecreatePolicy,omitempty"`
 }
 
 // AmazonCloudWatchAgentTargetAllocator defines the configurations for the Prometheus target allocator.
 type AmazonCloudWatchAgentTargetAllocator struct {
 	// Replicas is the number of pod instances for the underlying TargetAllocator. This should only be set to a value
 	// other than 1 if a strategy that allows for high availability is chosen. Currently, the only allocation strategy
-	// that can be run in a high availability mode is consistent-hashing.
+	// that can be run in a high availability mode is consistent-hashing. With consistent-hashing every replica
+	// computes the same assignment from the same collectors and targets, so any replica can answer a collector.
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 	// NodeSelector to schedule OpenTelemetry TargetAllocator pods.
@@ -305,7 +377,10 @@ type AmazonCloudWatchAgentTargetAllocator struct {
 	// +optional
 	AllocationStrategy AmazonCloudWatchAgentTargetAllocatorAllocationStrategy `json:"allocationStrategy,omitempty"`
 	// FilterStrategy determines how to filter targets before allocating them among the collectors.
-	// The only current option is relabel-config (drops targets based on prom relabel_config).
+	// The options are relabel-config (drops targets based on prom relabel_config), drop-unhealthy (drops targets
+	// whose pods are not Running and Ready) and limit-per-job (drops the targets exceeding the target_limit of
+	// their job). Several options can be chained as a comma separated list, e.g. relabel-config,limit-per-job,
+	// and are applied in that order. limit-per-job should come last so that it counts only the targets kept.
 	// Filtering is disabled by default.
 	// +optional
 	FilterStrategy string `json:"filterStrategy,omitempty"`
@@ -344,6 +419,37 @@ type AmazonCloudWatchAgentTargetAllocator struct {
 	// consumed in the config file for the TargetAllocator.
 	// +optional
 	Env []v1.EnvVar `json:"env,omitempty"`
+	// PodDisruptionBudget specifies the pod disruption budget configuration to use
+	// for the target allocator workload.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// ConsistentHashing tunes the consistent-hashing allocation strategy to limit how many targets move
+	// between collectors when the collectors change.
+	// +optional
+	ConsistentHashing *AmazonCloudWatchAgentTargetAllocatorConsistentHashing `json:"consistentHashing,omitempty"`
+}
+
+type AmazonCloudWatchAgentTargetAllocatorConsistentHashing struct {
+	// PartitionCount is the number of partitions on the hash ring. More partitions spread targets more
+	// evenly at the cost of memory.
+	//
+	// Default: 1061
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PartitionCount *int32 `json:"partitionCount,omitempty"`
+	// LoadFactor bounds the partitions a single collector may own to LoadFactor times the average. Lower
+	// values balance collectors more tightly but move more targets when collectors change. Must be greater than 1.
+	//
+	// Default: "1.1"
+	// +optional
+	LoadFactor string `json:"loadFactor,omitempty"`
+	// DrainTimeout is the longest a target moved to another collector keeps being assigned to its previous
+	// collector, so the new owner can take over scraping without a gap. The drain usually ends earlier, 30s
+	// after the new owner has picked up the target. Every Target Allocator replica tracks its drains on its
+	// own. Draining is disabled when unset.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
 }
 
 type AmazonCloudWatchAgentTargetAllocatorPrometheusCR struct {
@@ -412,6 +518,17 @@ type AmazonCloudWatchAgentStatus struct {
 	// +optional
 	// Deprecated: use "AmazonCloudWatchAgent.Status.Scale.Replicas" instead.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// Rollout is the progress of the rollout of the agent configuration when spec.rolloutStrategy is set.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Conditions describe the actions taken by the operator on the managed objects, like the recreation of the
+	// StatefulSet.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -459,7 +576,7 @@ type AutoscalerSpec struct {
 	// +optional
 	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
 	// Metrics is meant to provide a customizable way to configure HPA metrics.
-	// currently the only supported custom metrics is type=Pod.
+	// currently the supported custom metrics are type=Pods, type=Object and type=External.
 	// Use TargetCPUUtilization or TargetMemoryUtilization instead if scaling on these common resource metrics.
 	// +optional
 	Metrics []MetricSpec `json:"metrics,omitempty"`
@@ -470,6 +587,35 @@ type AutoscalerSpec struct {
 	// +optional
 	// TargetMemoryUtilization sets the target average memory utilization across all replicas
 	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+	// Mode selects what scales the collector. The default, "hpa", creates a HorizontalPodAutoscaler.
+	// "keda" creates a KEDA ScaledObject instead, scaling the collector on the number of targets assigned to
+	// each collector by the target allocator.
+	// +optional
+	Mode AutoscalerMode `json:"mode,omitempty"`
+	// KEDA configures the ScaledObject created in the "keda" mode.
+	// +optional
+	KEDA *KEDAScalerSpec `json:"keda,omitempty"`
+}
+
+// KEDAScalerSpec defines the KEDA ScaledObject scaling the collector on the cloudwatch_agent_allocator_targets_per_collector
+// metric of the target allocator.
+type KEDAScalerSpec struct {
+	// ServerAddress is the address of the Prometheus server scraping the metrics of the target allocator.
+	// +required
+	ServerAddress string `json:"serverAddress"`
+	// TargetsPerCollector is the number of scrape targets each collector should be assigned. The number of collectors
+	// is the number of targets divided by this value, within the bounds of MinReplicas and MaxReplicas.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	TargetsPerCollector int32 `json:"targetsPerCollector"`
+	// Query overrides the PromQL query returning the number of targets to spread over the collectors. Defaults to
+	// the sum of cloudwatch_agent_allocator_targets_per_collector over the pods of the collector, taking the maximum
+	// reported by the target allocator replicas for each pod.
+	// +optional
+	Query string `json:"query,omitempty"`
+	// PollingInterval is the interval, in seconds, at which KEDA checks the metric. Defaults to 30 seconds.
+	// +optional
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
 }
 
 // PodDisruptionBudgetSpec defines the AmazonCloudWatchAgent's pod disruption budget specification.
@@ -552,8 +698,10 @@ type Probe struct {
 // more metric type can be supported as needed.
 // See https://pkg.go.dev/k8s.io/api/autoscaling/v2#MetricSpec for reference.
 type MetricSpec struct {
-	Type autoscalingv2.MetricSourceType  `json:"type"`
-	Pods *autoscalingv2.PodsMetricSource `json:"pods,omitempty"`
+	Type     autoscalingv2.MetricSourceType      `json:"type"`
+	Pods     *autoscalingv2.PodsMetricSource     `json:"pods,omitempty"`
+	Object   *autoscalingv2.ObjectMetricSource   `json:"object,omitempty"`
+	External *autoscalingv2.ExternalMetricSource `json:"external,omitempty"`
 }
 
 type ConfigMapsSpec struct {
@@ -0,0 +1,19 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+type (
+	// AutoscalerMode represents what scales the collector (HorizontalPodAutoscaler vs. KEDA ScaledObject).
+	// +kubebuilder:validation:Enum=hpa;keda
+	AutoscalerMode string
+)
+
+const (
+	// AutoscalerModeHPA specifies that the collector should be scaled by a HorizontalPodAutoscaler.
+	AutoscalerModeHPA AutoscalerMode = "hpa"
+
+	// AutoscalerModeKEDA specifies that the collector should be scaled by a KEDA ScaledObject, on the number of
+	// targets assigned to each collector by the target allocator.
+	AutoscalerModeKEDA AutoscalerMode = "keda"
+)
@@ -6,6 +6,8 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
@@ -94,7 +96,9 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 			}
 		}
 
-		if r.Spec.Autoscaler.TargetMemoryUtilization == nil && r.Spec.Autoscaler.TargetCPUUtilization == nil {
+		// the keda mode scales on the target allocator metrics only
+		if r.Spec.Autoscaler.Mode != AutoscalerModeKEDA &&
+			r.Spec.Autoscaler.TargetMemoryUtilization == nil && r.Spec.Autoscaler.TargetCPUUtilization == nil {
 			defaultCPUTarget := int32(90)
 			r.Spec.Autoscaler.TargetCPUUtilization = &defaultCPUTarget
 		}
@@ -113,6 +117,21 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 		}
 	}
 
+	// same as above, but for the target allocator. Consistent-hashing replicas compute the same owner
+	// for every target from the same collectors, so any replica can serve a collector and a single
+	// disruption at a time keeps the allocator available. Targets being drained after a move are
+	// tracked by each replica on its own, and may be served to their previous collector a little longer
+	// by some replicas than by others.
+	if r.Spec.TargetAllocator.Enabled && r.Spec.TargetAllocator.PodDisruptionBudget == nil &&
+		isConsistentHashing(r.Spec.TargetAllocator.AllocationStrategy) {
+		r.Spec.TargetAllocator.PodDisruptionBudget = &PodDisruptionBudgetSpec{
+			MaxUnavailable: &intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 1,
+			},
+		}
+	}
+
 	if r.Spec.Ingress.Type == IngressTypeRoute && r.Spec.Ingress.Route.Termination == "" {
 		r.Spec.Ingress.Route.Termination = TLSRouteTerminationTypeEdge
 	}
@@ -129,6 +148,52 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 
 func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings, error) {
 	warnings := admission.Warnings{}
+	// validate configFrom and otelConfigFrom
+	if err := validateConfigSource("configFrom", r.Spec.ConfigFrom, r.Spec.Config); err != nil {
+		return warnings, err
+	}
+	if err := validateConfigSource("otelConfigFrom", r.Spec.OtelConfigFrom, r.Spec.OtelConfig); err != nil {
+		return warnings, err
+	}
+	// the sidecar receives its configuration through an environment variable of the pod
+	if r.Spec.Mode == ModeSidecar && (r.Spec.ConfigFrom.IsSecret() || r.Spec.OtelConfigFrom.IsSecret()) {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support reading the configuration from a secret", r.Spec.Mode)
+	}
+
+	// validate the agent config against the agent's JSON schema
+	if r.Spec.Config != "" {
+		configWarnings, err := adapters.ValidateConfigSchema(r.Spec.Config)
+		if err != nil {
+			return warnings, fmt.Errorf("the attribute 'config' is invalid: %w", err)
+		}
+		warnings = append(warnings, configWarnings...)
+	}
+	// validate the structured agent config merged into the raw one, the same way the operator renders it
+	if r.Spec.Agent != nil {
+		merged, err := r.Spec.Agent.MergeInto(r.Spec.Config)
+		if err != nil {
+			return warnings, fmt.Errorf("the attribute 'agent' is invalid: %w", err)
+		}
+		if _, err := adapters.ValidateConfigSchema(merged); err != nil {
+			return warnings, fmt.Errorf("the attribute 'agent' is invalid: %w", err)
+		}
+	}
+
+	// validate the components of the otel config against the ones included in the agent image
+	if r.Spec.OtelConfig != "" {
+		otelConfig, err := adapters.ConfigFromString(r.Spec.OtelConfig)
+		if err != nil {
+			return warnings, fmt.Errorf("the attribute 'otelConfig' is invalid: %w", err)
+		}
+		image := r.Spec.Image
+		if image == "" {
+			image = c.cfg.CollectorImage()
+		}
+		if err = adapters.ValidateComponents(otelConfig, adapters.AgentVersionFromImage(image)); err != nil {
+			return warnings, fmt.Errorf("the attribute 'otelConfig' is invalid: %w", err)
+		}
+	}
+
 	// validate volumeClaimTemplates
 	if r.Spec.Mode != ModeStatefulSet && len(r.Spec.VolumeClaimTemplates) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'volumeClaimTemplates'", r.Spec.Mode)
@@ -153,6 +218,10 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'AdditionalContainers'", r.Spec.Mode)
 	}
 
+	if r.Spec.Mode != ModeSidecar && r.Spec.NativeSidecar {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'nativeSidecar'", r.Spec.Mode)
+	}
+
 	// validate target allocation
 	if r.Spec.TargetAllocator.Enabled && r.Spec.Mode != ModeStatefulSet {
 		warnings = append(warnings, fmt.Sprintf("The Amazon CloudWatch Agent mode is set to %s, we do not recommend enabling Target Allocator when not running as a StatefulSet", r.Spec.Mode))
@@ -179,6 +248,48 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		}
 	}
 
+	// validate target allocator high availability
+	if r.Spec.TargetAllocator.Enabled && !isConsistentHashing(r.Spec.TargetAllocator.AllocationStrategy) {
+		if r.Spec.TargetAllocator.Replicas != nil && *r.Spec.TargetAllocator.Replicas > 1 {
+			return warnings, fmt.Errorf("the Target Allocator allocation strategy %s does not support more than one replica", r.Spec.TargetAllocator.AllocationStrategy)
+		}
+		if r.Spec.TargetAllocator.PodDisruptionBudget != nil {
+			return warnings, fmt.Errorf("the Target Allocator allocation strategy %s does not support the attribute 'podDisruptionBudget'", r.Spec.TargetAllocator.AllocationStrategy)
+		}
+	}
+
+	// validate target allocator filter strategy
+	if r.Spec.TargetAllocator.Enabled && len(r.Spec.TargetAllocator.FilterStrategy) > 0 {
+		seen := map[string]bool{}
+		for _, strategy := range strings.Split(r.Spec.TargetAllocator.FilterStrategy, targetAllocatorFilterStrategySeparator) {
+			name := strings.TrimSpace(strategy)
+			if !targetAllocatorFilterStrategies[name] {
+				return warnings, fmt.Errorf("the Target Allocator filterStrategy '%s' is not supported, it must be a comma separated list of %s, %s or %s",
+					strategy, TargetAllocatorFilterStrategyRelabelConfig, TargetAllocatorFilterStrategyDropUnhealthy, TargetAllocatorFilterStrategyLimitPerJob)
+			}
+			if seen[name] {
+				return warnings, fmt.Errorf("the Target Allocator filterStrategy '%s' is listed more than once", name)
+			}
+			seen[name] = true
+		}
+	}
+
+	// validate consistent hashing tuning
+	if chCfg := r.Spec.TargetAllocator.ConsistentHashing; r.Spec.TargetAllocator.Enabled && chCfg != nil {
+		if !isConsistentHashing(r.Spec.TargetAllocator.AllocationStrategy) {
+			return warnings, fmt.Errorf("the Target Allocator allocation strategy %s does not support the attribute 'consistentHashing'", r.Spec.TargetAllocator.AllocationStrategy)
+		}
+		if len(chCfg.LoadFactor) > 0 {
+			loadFactor, err := strconv.ParseFloat(chCfg.LoadFactor, 64)
+			if err != nil || loadFactor <= 1 {
+				return warnings, fmt.Errorf("the Target Allocator consistentHashing loadFactor must be a number greater than 1, got '%s'", chCfg.LoadFactor)
+			}
+		}
+		if chCfg.DrainTimeout != nil && chCfg.DrainTimeout.Duration < 0 {
+			return warnings, fmt.Errorf("the Target Allocator consistentHashing drainTimeout must not be negative")
+		}
+	}
+
 	// validator port config
 	for _, p := range r.Spec.Ports {
 		nameErrs := validation.IsValidPortName(p.Name)
@@ -240,6 +351,9 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		}
 
 		if r.Spec.Autoscaler != nil {
+			if r.Spec.Autoscaler.Mode == AutoscalerModeKEDA && !r.Spec.TargetAllocator.Enabled {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, the keda mode scales on the target allocator metrics and requires the target allocator to be enabled")
+			}
 			return warnings, checkAutoscalerSpec(r.Spec.Autoscaler)
 		}
 	}
@@ -249,6 +363,19 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 			ModeDeployment, ModeDaemonSet, ModeStatefulSet,
 		)
 	}
+	if r.Spec.Ingress.Type == IngressTypeGateway {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Ingress configuration is incorrect. Gateway routes can only be used in combination with the modes: %s, %s, %s",
+				ModeDeployment, ModeDaemonSet, ModeStatefulSet,
+			)
+		}
+		if r.Spec.Ingress.Gateway == nil || r.Spec.Ingress.Gateway.ParentRef.Name == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Ingress configuration is incorrect. The attribute 'ingress.gateway.parentRef.name' is required for the gateway type")
+		}
+		if r.Spec.Ingress.Gateway.BackendTLS != nil && r.Spec.Ingress.Gateway.BackendTLS.Hostname == "" {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Ingress configuration is incorrect. The attribute 'ingress.gateway.backendTLS.hostname' is required")
+		}
+	}
 	if r.Spec.Ingress.RuleType == IngressRuleTypeSubdomain && (r.Spec.Ingress.Hostname == "" || r.Spec.Ingress.Hostname == "*") {
 		return warnings, fmt.Errorf("a valid Ingress hostname has to be defined for subdomain ruleType")
 	}
@@ -279,6 +406,16 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'updateStrategy'", r.Spec.Mode)
 	}
 
+	// validate rolloutStrategy for DaemonSet
+	if r.Spec.Mode != ModeDaemonSet && r.Spec.RolloutStrategy != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'rolloutStrategy'", r.Spec.Mode)
+	}
+
+	// validate statefulSetRecreatePolicy for StatefulSet
+	if r.Spec.Mode != ModeStatefulSet && r.Spec.StatefulSetRecreatePolicy != "" {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'statefulSetRecreatePolicy'", r.Spec.Mode)
+	}
+
 	// validate updateStrategy for Deployment
 	if r.Spec.Mode != ModeDeployment && len(r.Spec.DeploymentUpdateStrategy.Type) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'deploymentUpdateStrategy'", r.Spec.Mode)
@@ -287,6 +424,19 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 	return warnings, nil
 }
 
+func validateConfigSource(attribute string, source *ConfigSource, inline string) error {
+	if source == nil {
+		return nil
+	}
+	if (source.ConfigMapKeyRef == nil) == (source.SecretKeyRef == nil) {
+		return fmt.Errorf("the attribute '%s' must set exactly one of configMapKeyRef and secretKeyRef", attribute)
+	}
+	if inline != "" {
+		return fmt.Errorf("the attribute '%s' can't be set along with the inline configuration", attribute)
+	}
+	return nil
+}
+
 func checkAutoscalerSpec(autoscaler *AutoscalerSpec) error {
 	if autoscaler.Behavior != nil {
 		if autoscaler.Behavior.ScaleDown != nil && autoscaler.Behavior.ScaleDown.StabilizationWindowSeconds != nil &&
@@ -307,28 +457,72 @@ func checkAutoscalerSpec(autoscaler *AutoscalerSpec) error {
 	}
 
 	for _, metric := range autoscaler.Metrics {
-		if metric.Type != autoscalingv2.PodsMetricSourceType {
-			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, metric type unsupported. Expected metric of source type Pod")
-		}
-
-		// pod metrics target only support value and averageValue.
-		switch metric.Pods.Target.Type {
-		case autoscalingv2.AverageValueMetricType:
-			if val, ok := metric.Pods.Target.AverageValue.AsInt64(); !ok || val < int64(1) {
-				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, average value should be greater than 0")
+		switch metric.Type {
+		case autoscalingv2.PodsMetricSourceType:
+			if metric.Pods == nil {
+				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, the pods metric source is missing")
 			}
-		case autoscalingv2.ValueMetricType:
-			if val, ok := metric.Pods.Target.Value.AsInt64(); !ok || val < int64(1) {
-				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, value should be greater than 0")
+			// pod metrics target only support value and averageValue.
+			if err := checkMetricTarget(metric.Pods.Target, "pods"); err != nil {
+				return err
+			}
+		case autoscalingv2.ObjectMetricSourceType:
+			if metric.Object == nil {
+				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, the object metric source is missing")
+			}
+			if err := checkMetricTarget(metric.Object.Target, "object"); err != nil {
+				return err
+			}
+		case autoscalingv2.ExternalMetricSourceType:
+			if metric.External == nil {
+				return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, the external metric source is missing")
+			}
+			if err := checkMetricTarget(metric.External.Target, "external"); err != nil {
+				return err
 			}
 		default:
-			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, invalid pods target type")
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, metric type unsupported. Expected metric of source type Pods, Object or External")
+		}
+	}
+
+	if autoscaler.Mode == AutoscalerModeKEDA {
+		if autoscaler.KEDA == nil || autoscaler.KEDA.ServerAddress == "" {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, the attribute 'keda.serverAddress' is required for the keda mode")
+		}
+		if autoscaler.KEDA.TargetsPerCollector < int32(1) {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, keda.targetsPerCollector should be one or more")
+		}
+		if len(autoscaler.Metrics) > 0 || autoscaler.TargetCPUUtilization != nil || autoscaler.TargetMemoryUtilization != nil {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, the keda mode scales on the target allocator metrics only and does not support the attributes 'metrics', 'targetCPUUtilization' and 'targetMemoryUtilization'")
 		}
 	}
 
 	return nil
 }
 
+// checkMetricTarget checks that the target of a custom metric is a value or an average value greater than 0.
+func checkMetricTarget(target autoscalingv2.MetricTarget, source string) error {
+	switch target.Type {
+	case autoscalingv2.AverageValueMetricType:
+		if target.AverageValue == nil {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, average value should be greater than 0")
+		}
+		if val, ok := target.AverageValue.AsInt64(); !ok || val < int64(1) {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, average value should be greater than 0")
+		}
+	case autoscalingv2.ValueMetricType:
+		if target.Value == nil {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, value should be greater than 0")
+		}
+		if val, ok := target.Value.AsInt64(); !ok || val < int64(1) {
+			return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, value should be greater than 0")
+		}
+	default:
+		return fmt.Errorf("the OpenTelemetry Spec autoscale configuration is incorrect, invalid %s target type", source)
+	}
+	return nil
+}
+
 func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config) error {
 	cvw := &CollectorWebhook{
 		logger: mgr.GetLogger().WithValues("handler", "CollectorWebhook"),
@@ -340,3 +534,8 @@ func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config) error {
 		WithDefaulter(cvw).
 		Complete()
 }
+
+// isConsistentHashing reports whether the strategy, defaulted by the target allocator when empty, is consistent-hashing.
+func isConsistentHashing(strategy AmazonCloudWatchAgentTargetAllocatorAllocationStrategy) bool {
+	return strategy == "" || strategy == AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing
+}
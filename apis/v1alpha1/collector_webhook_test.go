@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 )
@@ -161,6 +162,42 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Autoscaler in keda mode has no default CPU target",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &five,
+						MinReplicas: &one,
+						Mode:        AutoscalerModeKEDA,
+					},
+				},
+			},
+			expected: AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+					},
+				},
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeDeployment,
+					Replicas:        &one,
+					UpgradeStrategy: UpgradeStrategyAutomatic,
+					ManagementState: ManagementStateManaged,
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &five,
+						MinReplicas: &one,
+						Mode:        AutoscalerModeKEDA,
+					},
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 1,
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "MaxReplicas but no Autoscale",
 			otelcol: AmazonCloudWatchAgent{
@@ -265,6 +302,94 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Undefined PDB for target allocator",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled: true,
+					},
+				},
+			},
+			expected: AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+					},
+				},
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeStatefulSet,
+					Replicas:        &one,
+					UpgradeStrategy: UpgradeStrategyAutomatic,
+					ManagementState: ManagementStateManaged,
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 1,
+						},
+					},
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:  true,
+						Replicas: &one,
+						PodDisruptionBudget: &PodDisruptionBudgetSpec{
+							MaxUnavailable: &intstr.IntOrString{
+								Type:   intstr.Int,
+								IntVal: 1,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Defined PDB for target allocator",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:            true,
+						AllocationStrategy: AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing,
+						PodDisruptionBudget: &PodDisruptionBudgetSpec{
+							MinAvailable: &intstr.IntOrString{
+								Type:   intstr.String,
+								StrVal: "10%",
+							},
+						},
+					},
+				},
+			},
+			expected: AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+					},
+				},
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeStatefulSet,
+					Replicas:        &one,
+					UpgradeStrategy: UpgradeStrategyAutomatic,
+					ManagementState: ManagementStateManaged,
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{
+							Type:   intstr.Int,
+							IntVal: 1,
+						},
+					},
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:            true,
+						Replicas:           &one,
+						AllocationStrategy: AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing,
+						PodDisruptionBudget: &PodDisruptionBudgetSpec{
+							MinAvailable: &intstr.IntOrString{
+								Type:   intstr.String,
+								StrVal: "10%",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -317,6 +442,19 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			name:    "valid empty spec",
 			otelcol: AmazonCloudWatchAgent{},
 		},
+		{
+			name: "valid agent",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Agent: &AgentConfig{
+						Logs: &AgentLogs{MetricsCollected: &AgentLogsMetricsCollected{
+							Kubernetes: &AgentKubernetes{ClusterName: "cluster", EnhancedContainerInsights: ptr.To(true)},
+						}},
+						ApplicationSignals: &AgentApplicationSignals{},
+					},
+				},
+			},
+		},
 		{
 			name: "valid full spec",
 			otelcol: AmazonCloudWatchAgent{
@@ -387,6 +525,80 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "the OpenTelemetry Spec Prometheus configuration is incorrect",
 		},
+		{
+			name: "invalid target allocator consistent hashing load factor",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled: true,
+						ConsistentHashing: &AmazonCloudWatchAgentTargetAllocatorConsistentHashing{
+							LoadFactor: "1",
+						},
+					},
+					Prometheus: promCfg,
+				},
+			},
+			expectedErr: "the Target Allocator consistentHashing loadFactor must be a number greater than 1, got '1'",
+		},
+		{
+			name: "invalid target allocator consistent hashing strategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:            true,
+						AllocationStrategy: "least-weighted",
+						ConsistentHashing: &AmazonCloudWatchAgentTargetAllocatorConsistentHashing{
+							LoadFactor: "1.25",
+						},
+					},
+					Prometheus: promCfg,
+				},
+			},
+			expectedErr: "does not support the attribute 'consistentHashing'",
+		},
+		{
+			name: "valid target allocator chained filter strategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:        true,
+						FilterStrategy: "relabel-config,drop-unhealthy,limit-per-job",
+					},
+					Prometheus: promCfg,
+				},
+			},
+		},
+		{
+			name: "invalid target allocator filter strategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:        true,
+						FilterStrategy: "relabel-config,drop-everything",
+					},
+					Prometheus: promCfg,
+				},
+			},
+			expectedErr: "the Target Allocator filterStrategy 'drop-everything' is not supported",
+		},
+		{
+			name: "duplicate target allocator filter strategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:        true,
+						FilterStrategy: "relabel-config, drop-unhealthy, relabel-config",
+					},
+					Prometheus: promCfg,
+				},
+			},
+			expectedErr: "the Target Allocator filterStrategy 'relabel-config' is listed more than once",
+		},
 		{
 			name: "invalid port name",
 			otelcol: AmazonCloudWatchAgent{
@@ -602,6 +814,103 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, invalid pods target type",
 			expectedWarnings: []string{"MaxReplicas is deprecated"},
 		},
+		{
+			name: "invalid object metric without source",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Metrics: []MetricSpec{
+							{
+								Type: autoscalingv2.ObjectMetricSourceType,
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, the object metric source is missing",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "utilization target is not valid with external metrics",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Metrics: []MetricSpec{
+							{
+								Type: autoscalingv2.ExternalMetricSourceType,
+								External: &autoscalingv2.ExternalMetricSource{
+									Metric: autoscalingv2.MetricIdentifier{
+										Name: "queue_size",
+									},
+									Target: autoscalingv2.MetricTarget{
+										Type:               autoscalingv2.UtilizationMetricType,
+										AverageUtilization: &one,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, invalid external target type",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid keda autoscaler without target allocator",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &three,
+						Mode:        AutoscalerModeKEDA,
+						KEDA: &KEDAScalerSpec{
+							ServerAddress:       "http://prometheus:9090",
+							TargetsPerCollector: 100,
+						},
+					},
+				},
+			},
+			expectedErr: "the keda mode scales on the target allocator metrics and requires the target allocator to be enabled",
+		},
+		{
+			name: "invalid keda autoscaler without server address",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &three,
+						Mode:        AutoscalerModeKEDA,
+					},
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled: true,
+					},
+					Prometheus: promCfg,
+				},
+			},
+			expectedErr: "the attribute 'keda.serverAddress' is required for the keda mode",
+		},
+		{
+			name: "valid keda autoscaler",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &three,
+						Mode:        AutoscalerModeKEDA,
+						KEDA: &KEDAScalerSpec{
+							ServerAddress:       "http://prometheus:9090",
+							TargetsPerCollector: 100,
+						},
+					},
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled: true,
+					},
+					Prometheus: promCfg,
+				},
+			},
+		},
 		{
 			name: "invalid deployment mode incompabible with ingress settings",
 			otelcol: AmazonCloudWatchAgent{
@@ -732,6 +1041,16 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'AdditionalContainers'",
 		},
+		{
+			name: "invalid mode with nativeSidecar",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:          ModeDeployment,
+					NativeSidecar: true,
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'nativeSidecar'",
+		},
 		{
 			name: "missing ingress hostname for subdomain ruleType",
 			otelcol: AmazonCloudWatchAgent{
@@ -775,6 +1094,147 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "the OpenTelemetry Collector mode is set to statefulset, which does not support the attribute 'deploymentUpdateStrategy'",
 		},
+		{
+			name: "invalid rolloutStrategy for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					RolloutStrategy: &RolloutStrategy{
+						Type: RolloutStrategyCanary,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'rolloutStrategy'",
+		},
+		{
+			name: "invalid mode with statefulSetRecreatePolicy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                      ModeDeployment,
+					StatefulSetRecreatePolicy: StatefulSetRecreatePolicyOrphanAndRecreate,
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'statefulSetRecreatePolicy'",
+		},
+		{
+			name: "invalid gateway ingress without parent gateway",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					Ingress: Ingress{
+						Type: IngressTypeGateway,
+					},
+				},
+			},
+			expectedErr: "The attribute 'ingress.gateway.parentRef.name' is required for the gateway type",
+		},
+		{
+			name: "invalid gateway ingress for Sidecar mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					Ingress: Ingress{
+						Type:    IngressTypeGateway,
+						Gateway: &GatewayRoute{ParentRef: GatewayParentReference{Name: "gateway"}},
+					},
+				},
+			},
+			expectedErr: "Gateway routes can only be used in combination with the modes",
+		},
+		{
+			name: "invalid configFrom without reference",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigFrom: &ConfigSource{},
+				},
+			},
+			expectedErr: "the attribute 'configFrom' must set exactly one of configMapKeyRef and secretKeyRef",
+		},
+		{
+			name: "invalid otelConfigFrom along with otelConfig",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: "receivers: {}",
+					OtelConfigFrom: &ConfigSource{
+						ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "otel"}, Key: "config"},
+					},
+				},
+			},
+			expectedErr: "the attribute 'otelConfigFrom' can't be set along with the inline configuration",
+		},
+		{
+			name: "invalid configFrom secret for Sidecar mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					ConfigFrom: &ConfigSource{
+						SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "agent"}, Key: "config"},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support reading the configuration from a secret",
+		},
+		{
+			name: "invalid config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"agent": {"debug": "true"}}`,
+				},
+			},
+			expectedErr: "the attribute 'config' is invalid: agent.debug: expected boolean, but got string",
+		},
+		{
+			name: "invalid agent merged into config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"agent": {"region": "us-west-2"}}`,
+					Agent: &AgentConfig{
+						Agent: &AnyConfig{Object: map[string]interface{}{"debug": "true"}},
+					},
+				},
+			},
+			expectedErr: "the attribute 'agent' is invalid: agent.debug: expected boolean, but got string",
+		},
+		{
+			name: "invalid otelConfig with undefined component",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: `
+receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [debug]
+`,
+				},
+			},
+			expectedErr: "the attribute 'otelConfig' is invalid: the pipeline \"traces\" references the processor \"batch\", which is not defined",
+		},
+		{
+			name: "invalid otelConfig with unsupported component",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Image: "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300045.0b810",
+					OtelConfig: `
+receivers:
+  otlp:
+exporters:
+  otlphttp:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlphttp]
+`,
+				},
+			},
+			expectedErr: "the attribute 'otelConfig' is invalid: the exporter \"otlphttp\" is not supported by the CloudWatch agent 1.300045.0b810, the supported exporters are: awscloudwatch, awscloudwatchlogs, awsemf, awsxray, debug, prometheusremotewrite",
+		},
 	}
 
 	for _, test := range tests {
@@ -803,3 +1263,23 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestOTELColValidatingWebhookConfigWarnings(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg: config.New(
+			config.WithCollectorImage("collector:v0.0.0"),
+			config.WithTargetAllocatorImage("ta:v0.0.0"),
+		),
+	}
+	otelcol := AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Config: `{"logs": {"metrics_collected": {"app_signals": {}}}}`,
+		},
+	}
+
+	warnings, err := cvw.ValidateCreate(context.Background(), &otelcol)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"the config key 'logs.metrics_collected.app_signals' is deprecated: use application_signals instead"}, warnings)
+}
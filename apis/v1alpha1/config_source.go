@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ConfigSource references a key of a ConfigMap or Secret, in the namespace of the AmazonCloudWatchAgent, holding a
+// configuration. Exactly one of its fields must be set.
+type ConfigSource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap.
+	// +optional
+	ConfigMapKeyRef *v1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// SecretKeyRef selects a key of a Secret. The configuration built from it is stored in a Secret instead of a
+	// ConfigMap. The operator doesn't watch the Secrets it doesn't manage, so changes to the Secret are picked up
+	// within 5 minutes.
+	// +optional
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// IsSecret returns whether the configuration is read from a Secret.
+func (s *ConfigSource) IsSecret() bool {
+	return s != nil && s.SecretKeyRef != nil
+}
@@ -0,0 +1,18 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import "sigs.k8s.io/controller-runtime/pkg/conversion"
+
+var (
+	_ conversion.Hub = (*AmazonCloudWatchAgent)(nil)
+	_ conversion.Hub = (*Instrumentation)(nil)
+)
+
+// Hub marks v1alpha1, the storage version, as the version the other versions of AmazonCloudWatchAgent convert to and
+// from.
+func (*AmazonCloudWatchAgent) Hub() {}
+
+// Hub marks v1alpha1, the storage version, as the version the other versions of Instrumentation convert to and from.
+func (*Instrumentation) Hub() {}
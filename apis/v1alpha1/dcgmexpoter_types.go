@@ -10,6 +10,11 @@ import (
 
 // DcgmExporterSpec defines the desired state of DcgmExporter.
 type DcgmExporterSpec struct {
+	// AgentRef references the AmazonCloudWatchAgent whose configuration enables the DCGM Exporter, which is deployed only
+	// when enhanced_container_insights and accelerated_compute_metrics are enabled in it.
+	// Defaults to the cloudwatch-agent AmazonCloudWatchAgent in the amazon-cloudwatch namespace.
+	// +optional
+	AgentRef *AgentReference `json:"agentRef,omitempty"`
 	// Resources to set on the DCGM Exporter pods.
 	// +optional
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
@@ -58,6 +63,9 @@ type DcgmExporterSpec struct {
 	// If specified, indicates the pod's scheduling constraints
 	// +optional
 	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// NodeDiscovery schedules the DCGM Exporter on the nodes with accelerators without hand-written selectors.
+	// +optional
+	NodeDiscovery *NodeDiscovery `json:"nodeDiscovery,omitempty"`
 	// In deployment, daemonset, or statefulset mode, this controls
 	// the security context settings for the primary application
 	// container.
@@ -75,6 +83,10 @@ type DcgmExporterStatus struct {
 	// +optional
 	Scale ScaleSubresourceStatus `json:"scale,omitempty"`
 
+	// NodeDiscovery reports the nodes found when spec.nodeDiscovery is enabled.
+	// +optional
+	NodeDiscovery *NodeDiscoveryStatus `json:"nodeDiscovery,omitempty"`
+
 	// Version of the managed DCGM Exporter (operand)
 	// +optional
 	Version string `json:"version,omitempty"`
@@ -102,6 +114,8 @@ type DcgmExporterStatus struct {
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="DCGM exporter Version"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.scale.statusReplicas"
+// +kubebuilder:printcolumn:name="Eligible",type="integer",JSONPath=".status.nodeDiscovery.eligibleNodes",priority=1
+// +kubebuilder:printcolumn:name="Scheduled",type="integer",JSONPath=".status.nodeDiscovery.scheduledNodes",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.image"
 // +kubebuilder:printcolumn:name="Management",type="string",JSONPath=".spec.managementState",description="Management State"
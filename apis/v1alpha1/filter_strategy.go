@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+const (
+	// TargetAllocatorFilterStrategyRelabelConfig drops targets based on the prom relabel_config of their job.
+	TargetAllocatorFilterStrategyRelabelConfig = "relabel-config"
+	// TargetAllocatorFilterStrategyDropUnhealthy drops targets whose pods are not Running and Ready.
+	TargetAllocatorFilterStrategyDropUnhealthy = "drop-unhealthy"
+	// TargetAllocatorFilterStrategyLimitPerJob drops the targets exceeding the target_limit of their job.
+	TargetAllocatorFilterStrategyLimitPerJob = "limit-per-job"
+
+	// targetAllocatorFilterStrategySeparator separates the filter strategies chained in FilterStrategy.
+	targetAllocatorFilterStrategySeparator = ","
+)
+
+var targetAllocatorFilterStrategies = map[string]bool{
+	TargetAllocatorFilterStrategyRelabelConfig: true,
+	TargetAllocatorFilterStrategyDropUnhealthy: true,
+	TargetAllocatorFilterStrategyLimitPerJob:   true,
+}
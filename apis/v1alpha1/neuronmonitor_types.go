@@ -10,6 +10,11 @@ import (
 
 // NeuronMonitorSpec defines the desired state of NeuronMonitor.
 type NeuronMonitorSpec struct {
+	// AgentRef references the AmazonCloudWatchAgent whose configuration enables the Neuron Monitor, which is deployed only
+	// when enhanced_container_insights and accelerated_compute_metrics are enabled in it.
+	// Defaults to the cloudwatch-agent AmazonCloudWatchAgent in the amazon-cloudwatch namespace.
+	// +optional
+	AgentRef *AgentReference `json:"agentRef,omitempty"`
 	// Resources to set on the Neuron Monitor Exporter pods.
 	// +optional
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
@@ -77,6 +82,9 @@ type NeuronMonitorSpec struct {
 	// If specified, indicates the pod's scheduling constraints
 	// +optional
 	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// NodeDiscovery schedules the Neuron Monitor on the nodes with accelerators without hand-written selectors.
+	// +optional
+	NodeDiscovery *NodeDiscovery `json:"nodeDiscovery,omitempty"`
 }
 
 // NeuronMonitorStatus defines the observed state of NeuronMonitor.
@@ -85,6 +93,10 @@ type NeuronMonitorStatus struct {
 	// +optional
 	Scale ScaleSubresourceStatus `json:"scale,omitempty"`
 
+	// NodeDiscovery reports the nodes found when spec.nodeDiscovery is enabled.
+	// +optional
+	NodeDiscovery *NodeDiscoveryStatus `json:"nodeDiscovery,omitempty"`
+
 	// Version of the managed Neuron Monitor Exporter (operand)
 	// +optional
 	Version string `json:"version,omitempty"`
@@ -112,6 +124,8 @@ type NeuronMonitorStatus struct {
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Neuron Monitor exporter Version"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.scale.statusReplicas"
+// +kubebuilder:printcolumn:name="Eligible",type="integer",JSONPath=".status.nodeDiscovery.eligibleNodes",priority=1
+// +kubebuilder:printcolumn:name="Scheduled",type="integer",JSONPath=".status.nodeDiscovery.scheduledNodes",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.image"
 // +kubebuilder:printcolumn:name="Management",type="string",JSONPath=".spec.managementState",description="Management State"
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// NodeDiscovery configures the automatic targeting of the nodes with accelerators.
+type NodeDiscovery struct {
+	// Enabled makes the operator watch the nodes and schedule the DaemonSet only on the nodes with accelerators.
+	// The discovered node affinity replaces the required node affinity of spec.affinity.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Resources are the allocatable extended resources identifying the nodes to target.
+	// Defaults to nvidia.com/gpu for the DCGM Exporter, and to aws.amazon.com/neuron, aws.amazon.com/neuroncore
+	// and aws.amazon.com/neurondevice for the Neuron Monitor.
+	// +optional
+	// +listType=atomic
+	Resources []v1.ResourceName `json:"resources,omitempty"`
+	// NodeLabels identify the nodes to target before their device plugin advertises the resources, such as the
+	// labels set by Node Feature Discovery. A node having any of them is targeted.
+	// Defaults to feature.node.kubernetes.io/pci-10de.present=true for the DCGM Exporter, and to no labels for the
+	// Neuron Monitor.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+}
+
+// NodeDiscoveryStatus reports the nodes found by the node discovery.
+type NodeDiscoveryStatus struct {
+	// EligibleNodes is the number of nodes with accelerators.
+	EligibleNodes int32 `json:"eligibleNodes"`
+	// ScheduledNodes is the number of nodes running a pod of the DaemonSet.
+	ScheduledNodes int32 `json:"scheduledNodes"`
+}
@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type (
+	// RolloutStrategyType represents how the agent configuration changes are rolled out.
+	// +kubebuilder:validation:Enum=Canary
+	RolloutStrategyType string
+
+	// RolloutPhase represents the phase of the rollout of the agent configuration.
+	RolloutPhase string
+)
+
+const (
+	// RolloutStrategyCanary rolls a new configuration out to a percentage of the nodes first, and promotes it to
+	// every node once the canary pods stayed healthy for the bake time.
+	RolloutStrategyCanary RolloutStrategyType = "Canary"
+)
+
+const (
+	// RolloutPhaseStable means every node runs the same configuration.
+	RolloutPhaseStable RolloutPhase = "Stable"
+	// RolloutPhaseCanary means the canary nodes run a new configuration while it bakes.
+	RolloutPhaseCanary RolloutPhase = "Canary"
+	// RolloutPhaseRolledBack means the canary of the current configuration failed, and every node runs the
+	// stable configuration until the configuration changes again.
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+// RolloutStrategy configures how changes of spec.config and spec.otelConfig are rolled out.
+type RolloutStrategy struct {
+	// Type of the rollout strategy.
+	// +required
+	Type RolloutStrategyType `json:"type"`
+	// Canary configures the Canary rollout strategy.
+	// +optional
+	Canary *CanaryRollout `json:"canary,omitempty"`
+}
+
+// CanaryRollout configures the rollout of a new configuration to a subset of the nodes first.
+type CanaryRollout struct {
+	// NodePercentage is the percentage of the nodes running the new configuration during the canary.
+	// At least one node is always picked. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	NodePercentage *int32 `json:"nodePercentage,omitempty"`
+	// BakeTime is how long the canary pods must stay ready and healthy before the new configuration is promoted
+	// to every node. Defaults to 5m.
+	// +optional
+	BakeTime *metav1.Duration `json:"bakeTime,omitempty"`
+	// ProgressDeadline is how long the canary has to complete its bake time before it is rolled back.
+	// Defaults to 15m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+	// HealthCheck is the health endpoint of the agent probed on every canary pod. When unset, only the readiness
+	// of the canary pods is checked.
+	// +optional
+	HealthCheck *CanaryHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// CanaryHealthCheck is an HTTP health endpoint of the agent.
+type CanaryHealthCheck struct {
+	// Port of the health endpoint on the pod.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Path of the health endpoint. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// RolloutStatus reports the progress of the rollout of the agent configuration.
+type RolloutStatus struct {
+	// Phase of the rollout.
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// StableConfigHash is the hash of the configuration running on the nodes outside of the canary.
+	// +optional
+	StableConfigHash string `json:"stableConfigHash,omitempty"`
+	// CanaryConfigHash is the hash of the configuration being canaried, or of the configuration rolled back.
+	// +optional
+	CanaryConfigHash string `json:"canaryConfigHash,omitempty"`
+	// CanaryNodes are the nodes running the configuration being canaried.
+	// +optional
+	// +listType=atomic
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+	// CanaryStartTime is when the canary started.
+	// +optional
+	CanaryStartTime *metav1.Time `json:"canaryStartTime,omitempty"`
+	// HealthySince is when every canary pod became ready and healthy.
+	// +optional
+	HealthySince *metav1.Time `json:"healthySince,omitempty"`
+	// Message about the last transition of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,17 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+const (
+	// ConditionTypeSidecarsUpToDate is the type of the status condition telling whether the pods with a sidecar
+	// injected from the AmazonCloudWatchAgent run its current configuration.
+	ConditionTypeSidecarsUpToDate = "SidecarsUpToDate"
+
+	// ConditionReasonSidecarsUpToDate is the reason of the condition when all the injected sidecars are up to date.
+	ConditionReasonSidecarsUpToDate = "UpToDate"
+
+	// ConditionReasonSidecarsStale is the reason of the condition when the sidecars injected from the
+	// AmazonCloudWatchAgent differ from the one it injects now, or it is no longer in sidecar mode.
+	ConditionReasonSidecarsStale = "Stale"
+)
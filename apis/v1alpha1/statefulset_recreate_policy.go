@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+type (
+	// StatefulSetRecreatePolicy represents how the operator recreates the StatefulSet when an immutable field of its
+	// spec, like the selector or the volume claim templates, changes.
+	// +kubebuilder:validation:Enum=DeleteAndRecreate;OrphanAndRecreate
+	StatefulSetRecreatePolicy string
+)
+
+const (
+	// StatefulSetRecreatePolicyDeleteAndRecreate specifies that the StatefulSet is deleted along with its pods, and
+	// created again on the next reconciliation. This is the default.
+	StatefulSetRecreatePolicyDeleteAndRecreate StatefulSetRecreatePolicy = "DeleteAndRecreate"
+
+	// StatefulSetRecreatePolicyOrphanAndRecreate specifies that the StatefulSet is deleted leaving its pods and
+	// persistent volume claims behind, so that the recreated StatefulSet takes them over.
+	StatefulSetRecreatePolicyOrphanAndRecreate StatefulSetRecreatePolicy = "OrphanAndRecreate"
+)
+
+const (
+	// ConditionTypeStatefulSetRecreated is the type of the status condition describing the last recreation of the
+	// StatefulSet with the OrphanAndRecreate policy.
+	ConditionTypeStatefulSetRecreated = "StatefulSetRecreated"
+
+	// ConditionReasonStatefulSetRecreating is the reason of the condition while the orphaned StatefulSet is deleted.
+	ConditionReasonStatefulSetRecreating = "Recreating"
+
+	// ConditionReasonStatefulSetRecreated is the reason of the condition once the StatefulSet has been recreated.
+	ConditionReasonStatefulSetRecreated = "Recreated"
+)
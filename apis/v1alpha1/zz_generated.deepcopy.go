@@ -15,6 +15,220 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentApplicationSignals) DeepCopyInto(out *AgentApplicationSignals) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(AgentTLS)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentApplicationSignals.
+func (in *AgentApplicationSignals) DeepCopy() *AgentApplicationSignals {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentApplicationSignals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentConfig) DeepCopyInto(out *AgentConfig) {
+	*out = *in
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = (*in).DeepCopy()
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(AgentMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(AgentLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Traces != nil {
+		in, out := &in.Traces, &out.Traces
+		*out = new(AgentTraces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplicationSignals != nil {
+		in, out := &in.ApplicationSignals, &out.ApplicationSignals
+		*out = new(AgentApplicationSignals)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentConfig.
+func (in *AgentConfig) DeepCopy() *AgentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentKubernetes) DeepCopyInto(out *AgentKubernetes) {
+	*out = *in
+	if in.EnhancedContainerInsights != nil {
+		in, out := &in.EnhancedContainerInsights, &out.EnhancedContainerInsights
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AcceleratedComputeMetrics != nil {
+		in, out := &in.AcceleratedComputeMetrics, &out.AcceleratedComputeMetrics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.JMXContainerInsights != nil {
+		in, out := &in.JMXContainerInsights, &out.JMXContainerInsights
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MetricsCollectionInterval != nil {
+		in, out := &in.MetricsCollectionInterval, &out.MetricsCollectionInterval
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentKubernetes.
+func (in *AgentKubernetes) DeepCopy() *AgentKubernetes {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentKubernetes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentLogs) DeepCopyInto(out *AgentLogs) {
+	*out = *in
+	if in.MetricsCollected != nil {
+		in, out := &in.MetricsCollected, &out.MetricsCollected
+		*out = new(AgentLogsMetricsCollected)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogsCollected != nil {
+		in, out := &in.LogsCollected, &out.LogsCollected
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentLogs.
+func (in *AgentLogs) DeepCopy() *AgentLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentLogsMetricsCollected) DeepCopyInto(out *AgentLogsMetricsCollected) {
+	*out = *in
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(AgentKubernetes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EMF != nil {
+		in, out := &in.EMF, &out.EMF
+		*out = (*in).DeepCopy()
+	}
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentLogsMetricsCollected.
+func (in *AgentLogsMetricsCollected) DeepCopy() *AgentLogsMetricsCollected {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentLogsMetricsCollected)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentMetrics) DeepCopyInto(out *AgentMetrics) {
+	*out = *in
+	if in.MetricsCollected != nil {
+		in, out := &in.MetricsCollected, &out.MetricsCollected
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentMetrics.
+func (in *AgentMetrics) DeepCopy() *AgentMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentReference) DeepCopyInto(out *AgentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentReference.
+func (in *AgentReference) DeepCopy() *AgentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTLS) DeepCopyInto(out *AgentTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTLS.
+func (in *AgentTLS) DeepCopy() *AgentTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTraces) DeepCopyInto(out *AgentTraces) {
+	*out = *in
+	if in.TracesCollected != nil {
+		in, out := &in.TracesCollected, &out.TracesCollected
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTraces.
+func (in *AgentTraces) DeepCopy() *AgentTraces {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTraces)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudWatchAgent) DeepCopyInto(out *AmazonCloudWatchAgent) {
 	*out = *in
@@ -136,6 +350,21 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 	}
 	in.TargetAllocator.DeepCopyInto(&out.TargetAllocator)
 	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(AgentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigFrom != nil {
+		in, out := &in.ConfigFrom, &out.ConfigFrom
+		*out = new(ConfigSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OtelConfigFrom != nil {
+		in, out := &in.OtelConfigFrom, &out.OtelConfigFrom
+		*out = new(ConfigSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]corev1.VolumeMount, len(*in))
@@ -235,6 +464,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
 	in.DeploymentUpdateStrategy.DeepCopyInto(&out.DeploymentUpdateStrategy)
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentSpec.
@@ -256,6 +490,18 @@ func (in *AmazonCloudWatchAgentStatus) DeepCopyInto(out *AmazonCloudWatchAgentSt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentStatus.
@@ -316,6 +562,16 @@ func (in *AmazonCloudWatchAgentTargetAllocator) DeepCopyInto(out *AmazonCloudWat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsistentHashing != nil {
+		in, out := &in.ConsistentHashing, &out.ConsistentHashing
+		*out = new(AmazonCloudWatchAgentTargetAllocatorConsistentHashing)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTargetAllocator.
@@ -328,6 +584,31 @@ func (in *AmazonCloudWatchAgentTargetAllocator) DeepCopy() *AmazonCloudWatchAgen
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentTargetAllocatorConsistentHashing) DeepCopyInto(out *AmazonCloudWatchAgentTargetAllocatorConsistentHashing) {
+	*out = *in
+	if in.PartitionCount != nil {
+		in, out := &in.PartitionCount, &out.PartitionCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTargetAllocatorConsistentHashing.
+func (in *AmazonCloudWatchAgentTargetAllocatorConsistentHashing) DeepCopy() *AmazonCloudWatchAgentTargetAllocatorConsistentHashing {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentTargetAllocatorConsistentHashing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudWatchAgentTargetAllocatorPrometheusCR) DeepCopyInto(out *AmazonCloudWatchAgentTargetAllocatorPrometheusCR) {
 	*out = *in
@@ -432,6 +713,11 @@ func (in *AutoscalerSpec) DeepCopyInto(out *AutoscalerSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.KEDA != nil {
+		in, out := &in.KEDA, &out.KEDA
+		*out = new(KEDAScalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerSpec.
@@ -444,6 +730,56 @@ func (in *AutoscalerSpec) DeepCopy() *AutoscalerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryHealthCheck) DeepCopyInto(out *CanaryHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryHealthCheck.
+func (in *CanaryHealthCheck) DeepCopy() *CanaryHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollout) DeepCopyInto(out *CanaryRollout) {
+	*out = *in
+	if in.NodePercentage != nil {
+		in, out := &in.NodePercentage, &out.NodePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BakeTime != nil {
+		in, out := &in.BakeTime, &out.BakeTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(CanaryHealthCheck)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollout.
+func (in *CanaryRollout) DeepCopy() *CanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapsSpec) DeepCopyInto(out *ConfigMapsSpec) {
 	*out = *in
@@ -459,6 +795,31 @@ func (in *ConfigMapsSpec) DeepCopy() *ConfigMapsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSource) DeepCopyInto(out *ConfigSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSource.
+func (in *ConfigSource) DeepCopy() *ConfigSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DcgmExporter) DeepCopyInto(out *DcgmExporter) {
 	*out = *in
@@ -521,6 +882,11 @@ func (in *DcgmExporterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DcgmExporterSpec) DeepCopyInto(out *DcgmExporterSpec) {
 	*out = *in
+	if in.AgentRef != nil {
+		in, out := &in.AgentRef, &out.AgentRef
+		*out = new(AgentReference)
+		**out = **in
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
@@ -576,6 +942,11 @@ func (in *DcgmExporterSpec) DeepCopyInto(out *DcgmExporterSpec) {
 		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeDiscovery != nil {
+		in, out := &in.NodeDiscovery, &out.NodeDiscovery
+		*out = new(NodeDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecurityContext != nil {
 		in, out := &in.SecurityContext, &out.SecurityContext
 		*out = new(corev1.SecurityContext)
@@ -597,6 +968,11 @@ func (in *DcgmExporterSpec) DeepCopy() *DcgmExporterSpec {
 func (in *DcgmExporterStatus) DeepCopyInto(out *DcgmExporterStatus) {
 	*out = *in
 	out.Scale = in.Scale
+	if in.NodeDiscovery != nil {
+		in, out := &in.NodeDiscovery, &out.NodeDiscovery
+		*out = new(NodeDiscoveryStatus)
+		**out = **in
+	}
 	if in.Messages != nil {
 		in, out := &in.Messages, &out.Messages
 		*out = make([]string, len(*in))
@@ -657,6 +1033,62 @@ func (in *Exporter) DeepCopy() *Exporter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBackendTLS) DeepCopyInto(out *GatewayBackendTLS) {
+	*out = *in
+	if in.CACertificateRefs != nil {
+		in, out := &in.CACertificateRefs, &out.CACertificateRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackendTLS.
+func (in *GatewayBackendTLS) DeepCopy() *GatewayBackendTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBackendTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRoute) DeepCopyInto(out *GatewayRoute) {
+	*out = *in
+	out.ParentRef = in.ParentRef
+	if in.BackendTLS != nil {
+		in, out := &in.BackendTLS, &out.BackendTLS
+		*out = new(GatewayBackendTLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRoute.
+func (in *GatewayRoute) DeepCopy() *GatewayRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Go) DeepCopyInto(out *Go) {
 	*out = *in
@@ -708,6 +1140,11 @@ func (in *Ingress) DeepCopyInto(out *Ingress) {
 		**out = **in
 	}
 	out.Route = in.Route
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayRoute)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
@@ -859,6 +1296,26 @@ func (in *Java) DeepCopy() *Java {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KEDAScalerSpec) DeepCopyInto(out *KEDAScalerSpec) {
+	*out = *in
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KEDAScalerSpec.
+func (in *KEDAScalerSpec) DeepCopy() *KEDAScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KEDAScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 	*out = *in
@@ -867,6 +1324,16 @@ func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 		*out = new(v2.PodsMetricSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = new(v2.ObjectMetricSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(v2.ExternalMetricSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSpec.
@@ -956,6 +1423,11 @@ func (in *NeuronMonitorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NeuronMonitorSpec) DeepCopyInto(out *NeuronMonitorSpec) {
 	*out = *in
+	if in.AgentRef != nil {
+		in, out := &in.AgentRef, &out.AgentRef
+		*out = new(AgentReference)
+		**out = **in
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
@@ -1021,6 +1493,11 @@ func (in *NeuronMonitorSpec) DeepCopyInto(out *NeuronMonitorSpec) {
 		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeDiscovery != nil {
+		in, out := &in.NodeDiscovery, &out.NodeDiscovery
+		*out = new(NodeDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeuronMonitorSpec.
@@ -1037,6 +1514,11 @@ func (in *NeuronMonitorSpec) DeepCopy() *NeuronMonitorSpec {
 func (in *NeuronMonitorStatus) DeepCopyInto(out *NeuronMonitorStatus) {
 	*out = *in
 	out.Scale = in.Scale
+	if in.NodeDiscovery != nil {
+		in, out := &in.NodeDiscovery, &out.NodeDiscovery
+		*out = new(NodeDiscoveryStatus)
+		**out = **in
+	}
 	if in.Messages != nil {
 		in, out := &in.Messages, &out.Messages
 		*out = make([]string, len(*in))
@@ -1089,6 +1571,48 @@ func (in *Nginx) DeepCopy() *Nginx {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDiscovery) DeepCopyInto(out *NodeDiscovery) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDiscovery.
+func (in *NodeDiscovery) DeepCopy() *NodeDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDiscoveryStatus) DeepCopyInto(out *NodeDiscoveryStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDiscoveryStatus.
+func (in *NodeDiscoveryStatus) DeepCopy() *NodeDiscoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDiscoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeJS) DeepCopyInto(out *NodeJS) {
 	*out = *in
@@ -1291,6 +1815,54 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.CanaryNodes != nil {
+		in, out := &in.CanaryNodes, &out.CanaryNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CanaryStartTime != nil {
+		in, out := &in.CanaryStartTime, &out.CanaryStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.HealthySince != nil {
+		in, out := &in.HealthySince, &out.HealthySince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sampler) DeepCopyInto(out *Sampler) {
 	*out = *in
@@ -0,0 +1,183 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// ConversionDataAnnotation stores the v1alpha1 fields dropped from v1alpha2, so that converting a v1alpha1 object to
+// v1alpha2 and back is lossless.
+const ConversionDataAnnotation = "cloudwatch.aws.amazon.com/v1alpha1-conversion-data"
+
+var _ conversion.Convertible = (*AmazonCloudWatchAgent)(nil)
+
+// v1alpha1Data holds the deprecated v1alpha1 fields which v1alpha2 replaces with spec.autoscaler.
+type v1alpha1Data struct {
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// ConvertTo converts this AmazonCloudWatchAgent to the hub version, v1alpha1.
+func (src *AmazonCloudWatchAgent) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.AmazonCloudWatchAgent)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T", dstRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	data := v1alpha1Data{}
+	if raw, ok := dst.Annotations[ConversionDataAnnotation]; ok {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return fmt.Errorf("failed to read the %s annotation: %w", ConversionDataAnnotation, err)
+		}
+		delete(dst.Annotations, ConversionDataAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	dst.Spec.MinReplicas = data.MinReplicas
+	dst.Spec.MaxReplicas = data.MaxReplicas
+
+	dst.Spec.ManagementState = in.Spec.ManagementState
+	dst.Spec.Resources = in.Spec.Resources
+	dst.Spec.NodeSelector = in.Spec.NodeSelector
+	dst.Spec.Args = in.Spec.Args
+	dst.Spec.Replicas = in.Spec.Replicas
+	dst.Spec.Autoscaler = in.Spec.Autoscaler
+	dst.Spec.PodDisruptionBudget = in.Spec.PodDisruptionBudget
+	dst.Spec.SecurityContext = in.Spec.SecurityContext
+	dst.Spec.PodSecurityContext = in.Spec.PodSecurityContext
+	dst.Spec.PodAnnotations = in.Spec.PodAnnotations
+	dst.Spec.TargetAllocator = in.Spec.TargetAllocator
+	dst.Spec.Mode = in.Spec.Mode
+	dst.Spec.NativeSidecar = in.Spec.NativeSidecar
+	dst.Spec.ServiceAccount = in.Spec.ServiceAccount
+	dst.Spec.Image = in.Spec.Image
+	dst.Spec.WorkingDir = in.Spec.WorkingDir
+	dst.Spec.UpgradeStrategy = in.Spec.UpgradeStrategy
+	dst.Spec.ImagePullPolicy = in.Spec.ImagePullPolicy
+	dst.Spec.Prometheus = in.Spec.Prometheus
+	dst.Spec.Config = in.Spec.Config
+	dst.Spec.OtelConfig = in.Spec.OtelConfig
+	dst.Spec.Agent = in.Spec.Agent
+	dst.Spec.ConfigFrom = in.Spec.ConfigFrom
+	dst.Spec.OtelConfigFrom = in.Spec.OtelConfigFrom
+	dst.Spec.VolumeMounts = in.Spec.VolumeMounts
+	dst.Spec.Ports = in.Spec.Ports
+	dst.Spec.Env = in.Spec.Env
+	dst.Spec.EnvFrom = in.Spec.EnvFrom
+	dst.Spec.VolumeClaimTemplates = in.Spec.VolumeClaimTemplates
+	dst.Spec.Tolerations = in.Spec.Tolerations
+	dst.Spec.Volumes = in.Spec.Volumes
+	dst.Spec.Ingress = in.Spec.Ingress
+	dst.Spec.HostNetwork = in.Spec.HostNetwork
+	dst.Spec.PriorityClassName = in.Spec.PriorityClassName
+	dst.Spec.Affinity = in.Spec.Affinity
+	dst.Spec.Lifecycle = in.Spec.Lifecycle
+	dst.Spec.TerminationGracePeriodSeconds = in.Spec.TerminationGracePeriodSeconds
+	dst.Spec.LivenessProbe = in.Spec.LivenessProbe
+	dst.Spec.InitContainers = in.Spec.InitContainers
+	dst.Spec.AdditionalContainers = in.Spec.AdditionalContainers
+	dst.Spec.Observability = in.Spec.Observability
+	dst.Spec.TopologySpreadConstraints = in.Spec.TopologySpreadConstraints
+	dst.Spec.ConfigMaps = in.Spec.ConfigMaps
+	dst.Spec.UpdateStrategy = in.Spec.UpdateStrategy
+	dst.Spec.DeploymentUpdateStrategy = in.Spec.DeploymentUpdateStrategy
+	dst.Spec.RolloutStrategy = in.Spec.RolloutStrategy
+	dst.Spec.StatefulSetRecreatePolicy = in.Spec.StatefulSetRecreatePolicy
+
+	dst.Status.Scale = in.Status.Scale
+	dst.Status.Version = in.Status.Version
+	dst.Status.Image = in.Status.Image
+	dst.Status.Messages = in.Status.Messages
+	dst.Status.Replicas = in.Status.Replicas
+	dst.Status.Rollout = in.Status.Rollout
+	dst.Status.Conditions = in.Status.Conditions
+	return nil
+}
+
+// ConvertFrom converts the hub version, v1alpha1, to this AmazonCloudWatchAgent.
+func (dst *AmazonCloudWatchAgent) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.AmazonCloudWatchAgent)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	if in.Spec.MinReplicas != nil || in.Spec.MaxReplicas != nil {
+		raw, err := json.Marshal(v1alpha1Data{MinReplicas: in.Spec.MinReplicas, MaxReplicas: in.Spec.MaxReplicas})
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[ConversionDataAnnotation] = string(raw)
+	}
+
+	dst.Spec.ManagementState = in.Spec.ManagementState
+	dst.Spec.Resources = in.Spec.Resources
+	dst.Spec.NodeSelector = in.Spec.NodeSelector
+	dst.Spec.Args = in.Spec.Args
+	dst.Spec.Replicas = in.Spec.Replicas
+	dst.Spec.Autoscaler = in.Spec.Autoscaler
+	dst.Spec.PodDisruptionBudget = in.Spec.PodDisruptionBudget
+	dst.Spec.SecurityContext = in.Spec.SecurityContext
+	dst.Spec.PodSecurityContext = in.Spec.PodSecurityContext
+	dst.Spec.PodAnnotations = in.Spec.PodAnnotations
+	dst.Spec.TargetAllocator = in.Spec.TargetAllocator
+	dst.Spec.Mode = in.Spec.Mode
+	dst.Spec.NativeSidecar = in.Spec.NativeSidecar
+	dst.Spec.ServiceAccount = in.Spec.ServiceAccount
+	dst.Spec.Image = in.Spec.Image
+	dst.Spec.WorkingDir = in.Spec.WorkingDir
+	dst.Spec.UpgradeStrategy = in.Spec.UpgradeStrategy
+	dst.Spec.ImagePullPolicy = in.Spec.ImagePullPolicy
+	dst.Spec.Prometheus = in.Spec.Prometheus
+	dst.Spec.Config = in.Spec.Config
+	dst.Spec.OtelConfig = in.Spec.OtelConfig
+	dst.Spec.Agent = in.Spec.Agent
+	dst.Spec.ConfigFrom = in.Spec.ConfigFrom
+	dst.Spec.OtelConfigFrom = in.Spec.OtelConfigFrom
+	dst.Spec.VolumeMounts = in.Spec.VolumeMounts
+	dst.Spec.Ports = in.Spec.Ports
+	dst.Spec.Env = in.Spec.Env
+	dst.Spec.EnvFrom = in.Spec.EnvFrom
+	dst.Spec.VolumeClaimTemplates = in.Spec.VolumeClaimTemplates
+	dst.Spec.Tolerations = in.Spec.Tolerations
+	dst.Spec.Volumes = in.Spec.Volumes
+	dst.Spec.Ingress = in.Spec.Ingress
+	dst.Spec.HostNetwork = in.Spec.HostNetwork
+	dst.Spec.PriorityClassName = in.Spec.PriorityClassName
+	dst.Spec.Affinity = in.Spec.Affinity
+	dst.Spec.Lifecycle = in.Spec.Lifecycle
+	dst.Spec.TerminationGracePeriodSeconds = in.Spec.TerminationGracePeriodSeconds
+	dst.Spec.LivenessProbe = in.Spec.LivenessProbe
+	dst.Spec.InitContainers = in.Spec.InitContainers
+	dst.Spec.AdditionalContainers = in.Spec.AdditionalContainers
+	dst.Spec.Observability = in.Spec.Observability
+	dst.Spec.TopologySpreadConstraints = in.Spec.TopologySpreadConstraints
+	dst.Spec.ConfigMaps = in.Spec.ConfigMaps
+	dst.Spec.UpdateStrategy = in.Spec.UpdateStrategy
+	dst.Spec.DeploymentUpdateStrategy = in.Spec.DeploymentUpdateStrategy
+	dst.Spec.RolloutStrategy = in.Spec.RolloutStrategy
+	dst.Spec.StatefulSetRecreatePolicy = in.Spec.StatefulSetRecreatePolicy
+
+	dst.Status.Scale = in.Status.Scale
+	dst.Status.Version = in.Status.Version
+	dst.Status.Image = in.Status.Image
+	dst.Status.Messages = in.Status.Messages
+	dst.Status.Replicas = in.Status.Replicas
+	dst.Status.Rollout = in.Status.Rollout
+	dst.Status.Conditions = in.Status.Conditions
+	return nil
+}
@@ -1,8 +1,6 @@
 // Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
 // SPDX-License-Identifier: Apache-2.0
 
-// +kubebuilder:skip
-
 package v1alpha2
 
 import (
@@ -75,6 +73,11 @@ type AmazonCloudWatchAgentSpec struct {
 	// Mode represents how the collector should be deployed (deployment, daemonset, statefulset or sidecar)
 	// +optional
 	Mode v1alpha1.Mode `json:"mode,omitempty"`
+	// NativeSidecar injects the agent in sidecar mode as a Kubernetes native sidecar, an init container with
+	// restartPolicy Always which is started before the containers of the pod and doesn't keep Jobs from completing.
+	// The agent is injected as a regular container when the API server doesn't support native sidecars.
+	// +optional
+	NativeSidecar bool `json:"nativeSidecar,omitempty"`
 	// ServiceAccount indicates the name of an existing service account to use with this instance. When set,
 	// the operator will not automatically create a ServiceAccount for the collector.
 	// +optional
@@ -102,6 +105,16 @@ type AmazonCloudWatchAgentSpec struct {
 	// Config is the raw YAML to be used as the collector's configuration. Refer to the OpenTelemetry Collector documentation for details.
 	// +optional
 	OtelConfig string `json:"otelConfig,omitempty"`
+	// Agent is the structured form of the common sections of the agent configuration. It is rendered to JSON and
+	// merged over spec.config, or the configuration read through spec.configFrom, its keys taking precedence.
+	// +optional
+	Agent *v1alpha1.AgentConfig `json:"agent,omitempty"`
+	// ConfigFrom reads the raw JSON configuration from a ConfigMap or Secret key instead of spec.config.
+	// +optional
+	ConfigFrom *v1alpha1.ConfigSource `json:"configFrom,omitempty"`
+	// OtelConfigFrom reads the raw YAML configuration from a ConfigMap or Secret key instead of spec.otelConfig.
+	// +optional
+	OtelConfigFrom *v1alpha1.ConfigSource `json:"otelConfigFrom,omitempty"`
 	// VolumeMounts represents the mount points to use in the underlying collector deployment(s)
 	// +optional
 	// +listType=atomic
@@ -211,6 +224,17 @@ type AmazonCloudWatchAgentSpec struct {
 	// This is only applicable to Deployment mode.
 	// +optional
 	DeploymentUpdateStrategy appsv1.DeploymentStrategy `json:"deploymentUpdateStrategy,omitempty"`
+	// RolloutStrategy stages the rollout of the changes of spec.config and spec.otelConfig, instead of letting the
+	// DaemonSet roll them out to every node at once.
+	// This is only applicable to Daemonset mode.
+	// +optional
+	RolloutStrategy *v1alpha1.RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// StatefulSetRecreatePolicy defines how the StatefulSet is recreated when an immutable field of its spec changes.
+	// DeleteAndRecreate, the default, deletes the StatefulSet along with its pods. OrphanAndRecreate deletes the
+	// StatefulSet only, and the recreated StatefulSet takes over the existing pods and persistent volume claims.
+	// This is only applicable to Statefulset mode.
+	// +optional
+	StatefulSetRecreatePolicy v1alpha1.StatefulSetRecreatePolicy `json:"statefulSetRecreatePolicy,omitempty"`
 }
 
 // AmazonCloudWatchAgentStatus defines the observed state of AmazonCloudWatchAgent.
@@ -237,10 +261,29 @@ type AmazonCloudWatchAgentStatus struct {
 	// +optional
 	// Deprecated: use "AmazonCloudWatchAgent.Status.Scale.Replicas" instead.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// Rollout is the progress of the rollout of the agent configuration when spec.rolloutStrategy is set.
+	// +optional
+	Rollout *v1alpha1.RolloutStatus `json:"rollout,omitempty"`
+
+	// Conditions describe the actions taken by the operator on the managed objects, like the recreation of the
+	// StatefulSet.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
-//+kubebuilder:object:root=true
-//+kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=otelcol;otelcols
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Deployment Mode"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="CloudWatch Agent Version"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.scale.statusReplicas"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.image"
+// +kubebuilder:printcolumn:name="Management",type="string",JSONPath=".spec.managementState",description="Management State"
 
 // AmazonCloudWatchAgent is the Schema for the amazoncloudwatchagents API.
 type AmazonCloudWatchAgent struct {
@@ -251,7 +294,7 @@ type AmazonCloudWatchAgent struct {
 	Status AmazonCloudWatchAgentStatus `json:"status,omitempty"`
 }
 
-//+kubebuilder:object:root=true
+// +kubebuilder:object:root=true
 
 // AmazonCloudWatchAgentList contains a list of AmazonCloudWatchAgent.
 type AmazonCloudWatchAgentList struct {
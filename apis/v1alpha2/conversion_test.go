@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	"sigs.k8s.io/randfill"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// seeds are the fuzz inputs run by go test, without -fuzz.
+var seeds = [][]byte{
+	{},
+	[]byte("amazon-cloudwatch-agent"),
+	[]byte("0123456789abcdefghijklmnopqrstuvwxyz"),
+	[]byte{0xff, 0x00, 0x7f, 0x80, 0x01, 0xfe, 0x10, 0xef},
+}
+
+// newFiller creates a filler of random objects. Quantities are filled with valid values, since they are compared
+// semantically, and the raw configurations with scalars, since the filler can't pick the type of an interface.
+func newFiller(data []byte) *randfill.Filler {
+	return randfill.NewFromGoFuzz(data).NilChance(0.3).NumElements(0, 2).MaxDepth(12).Funcs(
+		func(q *resource.Quantity, c randfill.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+		func(ac *v1alpha1.AnyConfig, c randfill.Continue) {
+			ac.Object = map[string]interface{}{c.String(0): c.String(0), c.String(0): c.Int63()}
+		},
+	)
+}
+
+func assertSemanticEqual(t *testing.T, expected, actual interface{}) {
+	t.Helper()
+	if !apiequality.Semantic.DeepEqual(expected, actual) {
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func FuzzAmazonCloudWatchAgentHubRoundTrip(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hub := &v1alpha1.AmazonCloudWatchAgent{}
+		newFiller(data).Fill(hub)
+		delete(hub.Annotations, ConversionDataAnnotation)
+
+		spoke := &AmazonCloudWatchAgent{}
+		require.NoError(t, spoke.ConvertFrom(hub))
+		converted := &v1alpha1.AmazonCloudWatchAgent{}
+		require.NoError(t, spoke.ConvertTo(converted))
+
+		assertSemanticEqual(t, hub.ObjectMeta, converted.ObjectMeta)
+		assertSemanticEqual(t, hub.Spec, converted.Spec)
+		assertSemanticEqual(t, hub.Status, converted.Status)
+	})
+}
+
+func FuzzAmazonCloudWatchAgentSpokeRoundTrip(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		spoke := &AmazonCloudWatchAgent{}
+		newFiller(data).Fill(spoke)
+		delete(spoke.Annotations, ConversionDataAnnotation)
+
+		hub := &v1alpha1.AmazonCloudWatchAgent{}
+		require.NoError(t, spoke.ConvertTo(hub))
+		converted := &AmazonCloudWatchAgent{}
+		require.NoError(t, converted.ConvertFrom(hub))
+
+		assertSemanticEqual(t, spoke.ObjectMeta, converted.ObjectMeta)
+		assertSemanticEqual(t, spoke.Spec, converted.Spec)
+		assertSemanticEqual(t, spoke.Status, converted.Status)
+	})
+}
+
+func FuzzInstrumentationHubRoundTrip(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hub := &v1alpha1.Instrumentation{}
+		newFiller(data).Fill(hub)
+
+		spoke := &Instrumentation{}
+		require.NoError(t, spoke.ConvertFrom(hub))
+		converted := &v1alpha1.Instrumentation{}
+		require.NoError(t, spoke.ConvertTo(converted))
+
+		assertSemanticEqual(t, hub.ObjectMeta, converted.ObjectMeta)
+		assertSemanticEqual(t, hub.Spec, converted.Spec)
+		assertSemanticEqual(t, hub.Status, converted.Status)
+	})
+}
+
+func FuzzInstrumentationSpokeRoundTrip(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		spoke := &Instrumentation{}
+		newFiller(data).Fill(spoke)
+
+		hub := &v1alpha1.Instrumentation{}
+		require.NoError(t, spoke.ConvertTo(hub))
+		converted := &Instrumentation{}
+		require.NoError(t, converted.ConvertFrom(hub))
+
+		assertSemanticEqual(t, spoke.ObjectMeta, converted.ObjectMeta)
+		assertSemanticEqual(t, spoke.Spec, converted.Spec)
+		assertSemanticEqual(t, spoke.Status, converted.Status)
+	})
+}
+
+func TestConvertDeprecatedReplicas(t *testing.T) {
+	hub := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Annotations: map[string]string{"key": "value"}},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			MinReplicas: ptr.To[int32](1),
+			MaxReplicas: ptr.To[int32](3),
+		},
+	}
+
+	spoke := &AmazonCloudWatchAgent{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	assert.JSONEq(t, `{"minReplicas":1,"maxReplicas":3}`, spoke.Annotations[ConversionDataAnnotation])
+	assert.Equal(t, "value", spoke.Annotations["key"])
+	assert.NotContains(t, hub.Annotations, ConversionDataAnnotation, "the hub is left untouched")
+
+	converted := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	assert.Equal(t, hub.Spec.MinReplicas, converted.Spec.MinReplicas)
+	assert.Equal(t, hub.Spec.MaxReplicas, converted.Spec.MaxReplicas)
+	assert.Equal(t, map[string]string{"key": "value"}, converted.Annotations)
+}
+
+func TestConvertInvalidConversionData(t *testing.T) {
+	spoke := &AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ConversionDataAnnotation: "{"}},
+	}
+	assert.ErrorContains(t, spoke.ConvertTo(&v1alpha1.AmazonCloudWatchAgent{}), ConversionDataAnnotation)
+}
+
+func TestConvertUnsupportedHub(t *testing.T) {
+	assert.Error(t, (&AmazonCloudWatchAgent{}).ConvertTo(&v1alpha1.Instrumentation{}))
+	assert.Error(t, (&AmazonCloudWatchAgent{}).ConvertFrom(&v1alpha1.Instrumentation{}))
+	assert.Error(t, (&Instrumentation{}).ConvertTo(&v1alpha1.AmazonCloudWatchAgent{}))
+	assert.Error(t, (&Instrumentation{}).ConvertFrom(&v1alpha1.AmazonCloudWatchAgent{}))
+}
+
+func TestConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	for _, obj := range []runtime.Object{&v1alpha1.AmazonCloudWatchAgent{}, &v1alpha1.Instrumentation{}} {
+		ok, err := conversion.IsConvertible(scheme, obj)
+		require.NoError(t, err)
+		assert.True(t, ok, "the conversion webhook is registered for %T", obj)
+	}
+}
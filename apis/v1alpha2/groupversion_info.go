@@ -3,7 +3,7 @@
 
 // Package v1alpha2 contains API Schema definitions for the  v1alpha2 API group
 // +kubebuilder:object:generate=true
-// +groupName=opentelemetry.io
+// +groupName=cloudwatch.aws.amazon.com
 package v1alpha2
 
 import (
@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+var _ conversion.Convertible = (*Instrumentation)(nil)
+
+// ConvertTo converts this Instrumentation to the hub version, v1alpha1.
+func (src *Instrumentation) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Instrumentation)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T", dstRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = v1alpha1.InstrumentationSpec{
+		Exporter: v1alpha1.Exporter{Endpoint: in.Spec.Exporter.Endpoint},
+		Resource: v1alpha1.Resource{
+			Attributes:          in.Spec.Resource.Attributes,
+			AddK8sUIDAttributes: in.Spec.Resource.AddK8sUIDAttributes,
+		},
+		Propagators: in.Spec.Propagators,
+		Sampler: v1alpha1.Sampler{
+			Type:     in.Spec.Sampler.Type,
+			Argument: in.Spec.Sampler.Argument,
+		},
+		Env: in.Spec.Env,
+		Java: v1alpha1.Java{
+			Image:           in.Spec.Java.Image,
+			VolumeSizeLimit: in.Spec.Java.VolumeSizeLimit,
+			Env:             in.Spec.Java.Env,
+			Resources:       in.Spec.Java.Resources,
+		},
+		NodeJS: v1alpha1.NodeJS{
+			Image:           in.Spec.NodeJS.Image,
+			VolumeSizeLimit: in.Spec.NodeJS.VolumeSizeLimit,
+			Env:             in.Spec.NodeJS.Env,
+			Resources:       in.Spec.NodeJS.Resources,
+		},
+		Python: v1alpha1.Python{
+			Image:           in.Spec.Python.Image,
+			VolumeSizeLimit: in.Spec.Python.VolumeSizeLimit,
+			Env:             in.Spec.Python.Env,
+			Resources:       in.Spec.Python.Resources,
+		},
+		DotNet: v1alpha1.DotNet{
+			Image:           in.Spec.DotNet.Image,
+			VolumeSizeLimit: in.Spec.DotNet.VolumeSizeLimit,
+			Env:             in.Spec.DotNet.Env,
+			Resources:       in.Spec.DotNet.Resources,
+		},
+		Go: v1alpha1.Go{
+			Image:           in.Spec.Go.Image,
+			VolumeSizeLimit: in.Spec.Go.VolumeSizeLimit,
+			Env:             in.Spec.Go.Env,
+			Resources:       in.Spec.Go.Resources,
+		},
+		ApacheHttpd: v1alpha1.ApacheHttpd{
+			Image:           in.Spec.ApacheHttpd.Image,
+			VolumeSizeLimit: in.Spec.ApacheHttpd.VolumeSizeLimit,
+			Env:             in.Spec.ApacheHttpd.Env,
+			Attrs:           in.Spec.ApacheHttpd.Attrs,
+			Version:         in.Spec.ApacheHttpd.Version,
+			ConfigPath:      in.Spec.ApacheHttpd.ConfigPath,
+			Resources:       in.Spec.ApacheHttpd.Resources,
+		},
+		Nginx: v1alpha1.Nginx{
+			Image:           in.Spec.Nginx.Image,
+			VolumeSizeLimit: in.Spec.Nginx.VolumeSizeLimit,
+			Env:             in.Spec.Nginx.Env,
+			Attrs:           in.Spec.Nginx.Attrs,
+			ConfigFile:      in.Spec.Nginx.ConfigFile,
+			Resources:       in.Spec.Nginx.Resources,
+		},
+	}
+	dst.Status = v1alpha1.InstrumentationStatus{}
+	return nil
+}
+
+// ConvertFrom converts the hub version, v1alpha1, to this Instrumentation.
+func (dst *Instrumentation) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Instrumentation)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T", srcRaw)
+	}
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = InstrumentationSpec{
+		Exporter: Exporter{Endpoint: in.Spec.Exporter.Endpoint},
+		Resource: Resource{
+			Attributes:          in.Spec.Resource.Attributes,
+			AddK8sUIDAttributes: in.Spec.Resource.AddK8sUIDAttributes,
+		},
+		Propagators: in.Spec.Propagators,
+		Sampler: Sampler{
+			Type:     in.Spec.Sampler.Type,
+			Argument: in.Spec.Sampler.Argument,
+		},
+		Env: in.Spec.Env,
+		Java: Java{
+			Image:           in.Spec.Java.Image,
+			VolumeSizeLimit: in.Spec.Java.VolumeSizeLimit,
+			Env:             in.Spec.Java.Env,
+			Resources:       in.Spec.Java.Resources,
+		},
+		NodeJS: NodeJS{
+			Image:           in.Spec.NodeJS.Image,
+			VolumeSizeLimit: in.Spec.NodeJS.VolumeSizeLimit,
+			Env:             in.Spec.NodeJS.Env,
+			Resources:       in.Spec.NodeJS.Resources,
+		},
+		Python: Python{
+			Image:           in.Spec.Python.Image,
+			VolumeSizeLimit: in.Spec.Python.VolumeSizeLimit,
+			Env:             in.Spec.Python.Env,
+			Resources:       in.Spec.Python.Resources,
+		},
+		DotNet: DotNet{
+			Image:           in.Spec.DotNet.Image,
+			VolumeSizeLimit: in.Spec.DotNet.VolumeSizeLimit,
+			Env:             in.Spec.DotNet.Env,
+			Resources:       in.Spec.DotNet.Resources,
+		},
+		Go: Go{
+			Image:           in.Spec.Go.Image,
+			VolumeSizeLimit: in.Spec.Go.VolumeSizeLimit,
+			Env:             in.Spec.Go.Env,
+			Resources:       in.Spec.Go.Resources,
+		},
+		ApacheHttpd: ApacheHttpd{
+			Image:           in.Spec.ApacheHttpd.Image,
+			VolumeSizeLimit: in.Spec.ApacheHttpd.VolumeSizeLimit,
+			Env:             in.Spec.ApacheHttpd.Env,
+			Attrs:           in.Spec.ApacheHttpd.Attrs,
+			Version:         in.Spec.ApacheHttpd.Version,
+			ConfigPath:      in.Spec.ApacheHttpd.ConfigPath,
+			Resources:       in.Spec.ApacheHttpd.Resources,
+		},
+		Nginx: Nginx{
+			Image:           in.Spec.Nginx.Image,
+			VolumeSizeLimit: in.Spec.Nginx.VolumeSizeLimit,
+			Env:             in.Spec.Nginx.Env,
+			Attrs:           in.Spec.Nginx.Attrs,
+			ConfigFile:      in.Spec.Nginx.ConfigFile,
+			Resources:       in.Spec.Nginx.Resources,
+		},
+	}
+	dst.Status = InstrumentationStatus{}
+	return nil
+}
@@ -58,3 +58,15 @@ func MakeNNewTargetsWithEmptyCollectors(n int, startingIndex int) map[string]*ta
 	}
 	return toReturn
 }
+
+// targetsInRange returns the targets made by one of the functions above whose index is in [from, to).
+func targetsInRange(targets map[string]*target.Item, from, to int) map[string]*target.Item {
+	toReturn := map[string]*target.Item{}
+	for k, item := range targets {
+		i, err := strconv.Atoi(string(item.Labels["i"]))
+		if err == nil && i >= from && i < to {
+			toReturn[k] = item
+		}
+	}
+	return toReturn
+}
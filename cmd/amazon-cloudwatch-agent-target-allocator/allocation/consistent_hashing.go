@@ -4,8 +4,12 @@
 package allocation
 
 import (
+	"maps"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/buraksezer/consistent"
 	"github.com/cespare/xxhash/v2"
@@ -18,7 +22,18 @@ import (
 
 var _ Allocator = &consistentHashingAllocator{}
 
-const consistentHashingStrategyName = "consistent-hashing"
+const (
+	consistentHashingStrategyName = "consistent-hashing"
+
+	defaultPartitionCount    = 1061
+	defaultReplicationFactor = 5
+	defaultLoadFactor        = 1.1
+
+	// defaultHandOverPeriod is how long a moved target stays on its previous collector after the new owner first
+	// fetched it. It matches the default interval at which the collectors refresh their targets, so the new owner
+	// has started scraping the target by the time the previous collector stops.
+	defaultHandOverPeriod = 30 * time.Second
+)
 
 type hasher struct{}
 
@@ -27,10 +42,19 @@ func (h hasher) Sum64(data []byte) uint64 {
 }
 
 type consistentHashingAllocator struct {
-	// m protects consistentHasher, collectors and targetItems for concurrent use.
+	// m serializes writers and protects consistentHasher, collectors, targetItems and
+	// targetItemsPerJobPerCollector. Readers use state instead and never take it.
 	m sync.RWMutex
 
+	// state is the allocation readers see. It is replaced, never modified, after every change.
+	state atomic.Pointer[allocationState]
+
+	// dirty records the collector and job pairs changed since state was last published.
+	// collectorKey -> job -> true
+	dirty map[string]map[string]bool
+
 	consistentHasher *consistent.Consistent
+	hasherConfig     consistent.Config
 
 	// collectors is a map from a Collector's name to a Collector instance
 	// collectorKey -> collector pointer
@@ -43,26 +67,107 @@ type consistentHashingAllocator struct {
 	// collectorKey -> job -> target item hash -> true
 	targetItemsPerJobPerCollector map[string]map[string]map[string]bool
 
+	// drainTimeout is the longest a moved target is kept on its previous collector. Zero disables draining.
+	drainTimeout time.Duration
+
+	// handOverPeriod is how long a moved target is kept on its previous collector once the new owner fetched it.
+	handOverPeriod time.Duration
+
+	// drainMtx protects draining and drainingPerJobPerCollector. It is acquired after m, and is needed
+	// because draining targets are handed over from the read path.
+	drainMtx sync.Mutex
+
+	// draining holds the targets that moved between two live collectors and are still served to the previous one.
+	// The drain state is local to this allocator: every Target Allocator replica tracks the fetches it serves on
+	// its own, so a replica the new owner never fetches from keeps draining until drainTimeout.
+	// targetItem hash -> drain state
+	draining map[string]*drainingTarget
+
+	// previous collectorKey -> job -> target item hash -> true
+	drainingPerJobPerCollector map[string]map[string]map[string]bool
+
+	now func() time.Time
+
 	log logr.Logger
 
 	filter Filter
 }
 
+// allocationState is an immutable view of the allocation. Unchanged collector and job entries are shared between
+// successive states, so publishing costs are proportional to what changed.
+type allocationState struct {
+	collectors map[string]*Collector
+	// collectorKey -> job -> target items
+	targetItemsPerJobPerCollector map[string]map[string][]*target.Item
+	// unassigned holds the targets discovered while no collector is present.
+	unassigned []*target.Item
+	numTargets int
+}
+
+// drainingTarget tracks the hand-over of a target from its previous collector to its new owner.
+type drainingTarget struct {
+	item              *target.Item
+	previousCollector string
+	deadline          time.Time
+	// handedOverAt is set when the new owner first fetches the target. The drain completes one hand-over period
+	// later, once the new owner has been scraping the target.
+	handedOverAt time.Time
+}
+
+// ConsistentHashingConfig tunes the consistent-hashing strategy. Zero values keep the defaults.
+type ConsistentHashingConfig struct {
+	// PartitionCount is the number of partitions on the hash ring.
+	PartitionCount int
+	// LoadFactor bounds the partitions a collector may own to LoadFactor times the average, and must be above 1.
+	LoadFactor float64
+	// DrainTimeout is the longest a moved target stays on its previous collector while the new owner takes over.
+	DrainTimeout time.Duration
+}
+
+// WithConsistentHashingConfig applies cfg to a consistent-hashing allocator and is a no-op for other strategies.
+// It must be applied before any collector is set.
+func WithConsistentHashingConfig(cfg ConsistentHashingConfig) AllocationOption {
+	return func(allocator Allocator) {
+		c, ok := allocator.(*consistentHashingAllocator)
+		if !ok {
+			return
+		}
+		if cfg.PartitionCount > 0 {
+			c.hasherConfig.PartitionCount = cfg.PartitionCount
+		}
+		if cfg.LoadFactor > 0 {
+			c.hasherConfig.Load = cfg.LoadFactor
+		}
+		c.consistentHasher = consistent.New(nil, c.hasherConfig)
+		c.drainTimeout = cfg.DrainTimeout
+	}
+}
+
 func newConsistentHashingAllocator(log logr.Logger, opts ...AllocationOption) Allocator {
 	config := consistent.Config{
-		PartitionCount:    1061,
-		ReplicationFactor: 5,
-		Load:              1.1,
+		PartitionCount:    defaultPartitionCount,
+		ReplicationFactor: defaultReplicationFactor,
+		Load:              defaultLoadFactor,
 		Hasher:            hasher{},
 	}
 	consistentHasher := consistent.New(nil, config)
 	chAllocator := &consistentHashingAllocator{
 		consistentHasher:              consistentHasher,
+		hasherConfig:                  config,
 		collectors:                    make(map[string]*Collector),
 		targetItems:                   make(map[string]*target.Item),
 		targetItemsPerJobPerCollector: make(map[string]map[string]map[string]bool),
+		dirty:                         make(map[string]map[string]bool),
+		draining:                      make(map[string]*drainingTarget),
+		drainingPerJobPerCollector:    make(map[string]map[string]map[string]bool),
+		handOverPeriod:                defaultHandOverPeriod,
+		now:                           time.Now,
 		log:                           log,
 	}
+	chAllocator.state.Store(&allocationState{
+		collectors:                    map[string]*Collector{},
+		targetItemsPerJobPerCollector: map[string]map[string][]*target.Item{},
+	})
 	for _, opt := range opts {
 		opt(chAllocator)
 	}
@@ -86,27 +191,113 @@ func (c *consistentHashingAllocator) addCollectorTargetItemMapping(tg *target.It
 		c.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] = make(map[string]bool)
 	}
 	c.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName][tg.Hash()] = true
+	c.markDirty(tg.CollectorName, tg.JobName)
+}
+
+// removeCollectorTargetItemMapping is the inverse of addCollectorTargetItemMapping. The caller has to acquire a lock.
+func (c *consistentHashingAllocator) removeCollectorTargetItemMapping(tg *target.Item) {
+	delete(c.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName], tg.Hash())
+	c.markDirty(tg.CollectorName, tg.JobName)
+}
+
+func (c *consistentHashingAllocator) markDirty(collector, job string) {
+	if c.dirty[collector] == nil {
+		c.dirty[collector] = make(map[string]bool)
+	}
+	c.dirty[collector][job] = true
+}
+
+// publish makes the changes since the last publish visible to readers. Only the collector and job pairs marked dirty
+// are rebuilt; everything else is shared with the previous state. The caller has to acquire a lock.
+func (c *consistentHashingAllocator) publish() {
+	previous := c.state.Load()
+	next := &allocationState{
+		collectors:                    make(map[string]*Collector, len(c.collectors)),
+		targetItemsPerJobPerCollector: make(map[string]map[string][]*target.Item, len(c.targetItemsPerJobPerCollector)),
+		numTargets:                    len(c.targetItems),
+	}
+	for name, col := range c.collectors {
+		colCopy := *col
+		next.collectors[name] = &colCopy
+	}
+	for name, jobs := range c.targetItemsPerJobPerCollector {
+		previousJobs, published := previous.targetItemsPerJobPerCollector[name]
+		dirtyJobs, isDirty := c.dirty[name]
+		switch {
+		case published && !isDirty:
+			next.targetItemsPerJobPerCollector[name] = previousJobs
+		case published:
+			nextJobs := maps.Clone(previousJobs)
+			for job := range dirtyJobs {
+				c.publishJob(nextJobs, job, jobs[job])
+			}
+			next.targetItemsPerJobPerCollector[name] = nextJobs
+		default:
+			nextJobs := make(map[string][]*target.Item, len(jobs))
+			for job, hashes := range jobs {
+				c.publishJob(nextJobs, job, hashes)
+			}
+			next.targetItemsPerJobPerCollector[name] = nextJobs
+		}
+	}
+	if len(c.collectors) == 0 {
+		next.unassigned = make([]*target.Item, 0, len(c.targetItems))
+		for _, item := range c.targetItems {
+			next.unassigned = append(next.unassigned, item)
+		}
+	}
+	c.dirty = make(map[string]map[string]bool)
+	c.state.Store(next)
+}
+
+// publishJob stores the targets with the given hashes as the job's entry in jobs.
+func (c *consistentHashingAllocator) publishJob(jobs map[string][]*target.Item, job string, hashes map[string]bool) {
+	if len(hashes) == 0 {
+		delete(jobs, job)
+		return
+	}
+	items := make([]*target.Item, 0, len(hashes))
+	for hash := range hashes {
+		items = append(items, c.targetItems[hash])
+	}
+	jobs[job] = items
 }
 
 // addTargetToTargetItems assigns a target to the collector based on its hash and adds it to the allocator's targetItems
-// This method is called from within SetTargets and SetCollectors, which acquire the needed lock.
+// This method is called from within SetTargets, UpdateTargets and SetCollectors, which acquire the needed lock.
 // This is only called after the collectors are cleared or when a new target has been found in the tempTargetMap.
 // INVARIANT: c.collectors must have at least 1 collector set.
-// NOTE: by not creating a new target item, there is the potential for a race condition where we modify this target
-// item while it's being encoded by the server JSON handler.
-func (c *consistentHashingAllocator) addTargetToTargetItems(tg *target.Item) {
-	// Check if this is a reassignment, if so, decrement the previous collector's NumTargets
-	if previousColName, ok := c.collectors[tg.CollectorName]; ok {
-		previousColName.NumTargets--
-		delete(c.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName], tg.Hash())
-		TargetsPerCollector.WithLabelValues(previousColName.String(), consistentHashingStrategyName).Set(float64(c.collectors[previousColName.String()].NumTargets))
-	}
-	colOwner := c.consistentHasher.LocateKey([]byte(strings.Join(tg.TargetURL, "")))
-	tg.CollectorName = colOwner.String()
+// Target items are shared with the discoverer's index and with readers, so the allocator stores a copy of the item
+// carrying its collector rather than modifying it. The stored item is returned.
+func (c *consistentHashingAllocator) addTargetToTargetItems(tg *target.Item) *target.Item {
+	colOwner := c.consistentHasher.LocateKey([]byte(strings.Join(tg.TargetURL, ""))).String()
+	if previous, ok := c.targetItems[tg.Hash()]; ok {
+		if previous.CollectorName == colOwner {
+			return previous
+		}
+		// This is a reassignment, so decrement the previous collector's NumTargets
+		if previousCol, ok := c.collectors[previous.CollectorName]; ok {
+			previousCol.NumTargets--
+			c.removeCollectorTargetItemMapping(previous)
+			TargetsPerCollector.WithLabelValues(previousCol.String(), consistentHashingStrategyName).Set(float64(previousCol.NumTargets))
+		}
+		tg = previous
+	}
+	tg = tg.WithCollector(colOwner)
 	c.targetItems[tg.Hash()] = tg
 	c.addCollectorTargetItemMapping(tg)
-	c.collectors[colOwner.String()].NumTargets++
-	TargetsPerCollector.WithLabelValues(colOwner.String(), consistentHashingStrategyName).Set(float64(c.collectors[colOwner.String()].NumTargets))
+	c.collectors[colOwner].NumTargets++
+	TargetsPerCollector.WithLabelValues(colOwner, consistentHashingStrategyName).Set(float64(c.collectors[colOwner].NumTargets))
+	return tg
+}
+
+// unassigned clears any collector set on a target stored while no collector is present, so that it is counted once
+// collectors arrive.
+func unassigned(tg *target.Item) *target.Item {
+	if tg.CollectorName == "" {
+		return tg
+	}
+	return tg.WithCollector("")
 }
 
 // handleTargets receives the new and removed targets and reconciles the current state.
@@ -114,15 +305,17 @@ func (c *consistentHashingAllocator) addTargetToTargetItems(tg *target.Item) {
 // Any net-new additions are assigned to the next available collector.
 func (c *consistentHashingAllocator) handleTargets(diff diff.Changes[*target.Item]) {
 	// Check for removals
-	for k, item := range c.targetItems {
-		// if the current item is in the removals list
-		if _, ok := diff.Removals()[k]; ok {
-			col := c.collectors[item.CollectorName]
-			col.NumTargets--
-			delete(c.targetItems, k)
-			delete(c.targetItemsPerJobPerCollector[item.CollectorName][item.JobName], item.Hash())
-			TargetsPerCollector.WithLabelValues(item.CollectorName, consistentHashingStrategyName).Set(float64(col.NumTargets))
+	for k := range diff.Removals() {
+		item, ok := c.targetItems[k]
+		if !ok {
+			continue
 		}
+		col := c.collectors[item.CollectorName]
+		col.NumTargets--
+		delete(c.targetItems, k)
+		c.removeCollectorTargetItemMapping(item)
+		TargetsPerCollector.WithLabelValues(item.CollectorName, consistentHashingStrategyName).Set(float64(col.NumTargets))
+		c.finishDrain(k)
 	}
 
 	// Check for additions
@@ -139,13 +332,16 @@ func (c *consistentHashingAllocator) handleTargets(diff diff.Changes[*target.Ite
 
 // handleCollectors receives the new and removed collectors and reconciles the current state.
 // Any removals are removed from the allocator's collectors. New collectors are added to the allocator's collector map.
-// Finally, update all targets' collectors to match the consistent hashing.
+// Finally, update all targets' collectors to match the consistent hashing. Targets moving away from a collector that
+// is still present are drained, so the previous collector keeps scraping them until the new owner has taken over.
 func (c *consistentHashingAllocator) handleCollectors(diff diff.Changes[*Collector]) {
 	// Clear removed collectors
 	for _, k := range diff.Removals() {
 		delete(c.collectors, k.Name)
 		delete(c.targetItemsPerJobPerCollector, k.Name)
+		delete(c.dirty, k.Name)
 		c.consistentHasher.Remove(k.Name)
+		c.clearDrainsFrom(k.Name)
 		TargetsPerCollector.WithLabelValues(k.Name, consistentHashingStrategyName).Set(0)
 	}
 	// Insert the new collectors
@@ -155,9 +351,75 @@ func (c *consistentHashingAllocator) handleCollectors(diff diff.Changes[*Collect
 	}
 
 	// Re-Allocate all targets
+	moved := 0
 	for _, item := range c.targetItems {
-		c.addTargetToTargetItems(item)
+		previous := item.CollectorName
+		item = c.addTargetToTargetItems(item)
+		if previous == "" || previous == item.CollectorName {
+			continue
+		}
+		moved++
+		if _, ok := c.collectors[previous]; ok && c.drainTimeout > 0 {
+			c.startDrain(item, previous)
+		}
 	}
+	TargetsMoved.WithLabelValues(consistentHashingStrategyName).Observe(float64(moved))
+	c.log.V(2).Info("Rebalanced targets", "moved", moved, "draining", len(c.draining))
+}
+
+// startDrain keeps tg on previous until its new owner has taken it over or the drain times out. A target moving back
+// to the collector it is being drained from simply stops draining. The caller has to acquire m.
+func (c *consistentHashingAllocator) startDrain(tg *target.Item, previous string) {
+	c.drainMtx.Lock()
+	defer c.drainMtx.Unlock()
+	if d, ok := c.draining[tg.Hash()]; ok {
+		c.removeDrain(tg.Hash(), d)
+		if d.previousCollector == tg.CollectorName {
+			return
+		}
+	}
+	c.draining[tg.Hash()] = &drainingTarget{
+		item:              tg,
+		previousCollector: previous,
+		deadline:          c.now().Add(c.drainTimeout),
+	}
+	if c.drainingPerJobPerCollector[previous] == nil {
+		c.drainingPerJobPerCollector[previous] = make(map[string]map[string]bool)
+	}
+	if c.drainingPerJobPerCollector[previous][tg.JobName] == nil {
+		c.drainingPerJobPerCollector[previous][tg.JobName] = make(map[string]bool)
+	}
+	c.drainingPerJobPerCollector[previous][tg.JobName][tg.Hash()] = true
+	TargetsDraining.WithLabelValues(consistentHashingStrategyName).Set(float64(len(c.draining)))
+}
+
+// finishDrain stops serving the target to its previous collector, if it is draining.
+func (c *consistentHashingAllocator) finishDrain(hash string) {
+	c.drainMtx.Lock()
+	defer c.drainMtx.Unlock()
+	if d, ok := c.draining[hash]; ok {
+		c.removeDrain(hash, d)
+	}
+}
+
+// clearDrainsFrom drops every drain whose previous collector is gone.
+func (c *consistentHashingAllocator) clearDrainsFrom(collector string) {
+	c.drainMtx.Lock()
+	defer c.drainMtx.Unlock()
+	for _, hashes := range c.drainingPerJobPerCollector[collector] {
+		for hash := range hashes {
+			delete(c.draining, hash)
+		}
+	}
+	delete(c.drainingPerJobPerCollector, collector)
+	TargetsDraining.WithLabelValues(consistentHashingStrategyName).Set(float64(len(c.draining)))
+}
+
+// removeDrain requires drainMtx to be held.
+func (c *consistentHashingAllocator) removeDrain(hash string, d *drainingTarget) {
+	delete(c.draining, hash)
+	delete(c.drainingPerJobPerCollector[d.previousCollector][d.item.JobName], hash)
+	TargetsDraining.WithLabelValues(consistentHashingStrategyName).Set(float64(len(c.draining)))
 }
 
 // SetTargets accepts a list of targets that will be used to make
@@ -174,6 +436,7 @@ func (c *consistentHashingAllocator) SetTargets(targets map[string]*target.Item)
 
 	c.m.Lock()
 	defer c.m.Unlock()
+	defer c.publish()
 
 	if len(c.collectors) == 0 {
 		c.log.Info("No collector instances present, saving targets to allocate to collector(s)")
@@ -181,7 +444,7 @@ func (c *consistentHashingAllocator) SetTargets(targets map[string]*target.Item)
 		if len(c.targetItems) == 0 {
 			c.log.Info("Not discovered any targets previously, saving targets found to the targetItems set")
 			for k, item := range targets {
-				c.targetItems[k] = item
+				c.targetItems[k] = unassigned(item)
 			}
 		} else {
 			// If there were previously discovered targets, add or remove accordingly
@@ -196,7 +459,7 @@ func (c *consistentHashingAllocator) SetTargets(targets map[string]*target.Item)
 						continue
 					} else {
 						// Add item to item pool
-						c.targetItems[k] = item
+						c.targetItems[k] = unassigned(item)
 					}
 				}
 			}
@@ -220,6 +483,36 @@ func (c *consistentHashingAllocator) SetTargets(targets map[string]*target.Item)
 	}
 }
 
+// UpdateTargets applies the targets added and removed since the last update. Unlike SetTargets, its cost depends only
+// on the size of the change.
+func (c *consistentHashingAllocator) UpdateTargets(changes diff.Changes[*target.Item]) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("UpdateTargets", consistentHashingStrategyName))
+	defer timer.ObserveDuration()
+
+	changes = ApplyFilterToChanges(c.filter, changes)
+	RecordTargetsKept(changes.Additions())
+	if len(changes.Additions()) == 0 && len(changes.Removals()) == 0 {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	defer c.publish()
+
+	if len(c.collectors) == 0 {
+		for k := range changes.Removals() {
+			delete(c.targetItems, k)
+		}
+		for k, item := range changes.Additions() {
+			if _, ok := c.targetItems[k]; !ok {
+				c.targetItems[k] = unassigned(item)
+			}
+		}
+		return
+	}
+	c.handleTargets(changes)
+}
+
 // SetCollectors sets the set of collectors with key=collectorName, value=Collector object.
 // This method is called when Collectors are added or removed.
 func (c *consistentHashingAllocator) SetCollectors(collectors map[string]*Collector) {
@@ -234,51 +527,122 @@ func (c *consistentHashingAllocator) SetCollectors(collectors map[string]*Collec
 
 	c.m.Lock()
 	defer c.m.Unlock()
+	defer c.publish()
 
 	// Check for collector changes
 	collectorsDiff := diff.Maps(c.collectors, collectors)
 	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 {
 		c.handleCollectors(collectorsDiff)
 	}
+	// Readiness does not take part in the diff, so carry it over for every known collector.
+	for name, col := range collectors {
+		if existing, ok := c.collectors[name]; ok {
+			existing.Ready = col.Ready
+		}
+	}
 	c.log.Info("Setting collector completed")
 }
 
+// GetTargetsForCollectorAndJob returns the targets owned by the collector for the job, along with the targets that are
+// still being drained from it. Fetching also starts the hand-over of any draining target the collector now owns.
+// The returned slice is shared and must not be modified.
 func (c *consistentHashingAllocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
-	c.m.RLock()
-	defer c.m.RUnlock()
-	if _, ok := c.targetItemsPerJobPerCollector[collector]; !ok {
-		return []*target.Item{}
+	items := c.state.Load().targetItemsPerJobPerCollector[collector][job]
+	if items == nil {
+		items = []*target.Item{}
 	}
-	if _, ok := c.targetItemsPerJobPerCollector[collector][job]; !ok {
-		return []*target.Item{}
+	if c.drainTimeout == 0 {
+		return items
 	}
-	targetItemsCopy := make([]*target.Item, len(c.targetItemsPerJobPerCollector[collector][job]))
-	index := 0
-	for targetHash := range c.targetItemsPerJobPerCollector[collector][job] {
-		targetItemsCopy[index] = c.targetItems[targetHash]
-		index++
+
+	c.drainMtx.Lock()
+	defer c.drainMtx.Unlock()
+	now := c.now()
+	for _, item := range items {
+		d, ok := c.draining[item.Hash()]
+		if ok && d.previousCollector != collector && d.handedOverAt.IsZero() {
+			d.handedOverAt = now
+		}
 	}
-	return targetItemsCopy
+	// Cap the capacity so that appending the draining targets never writes to the shared slice.
+	items = items[:len(items):len(items)]
+	for targetHash := range c.drainingPerJobPerCollector[collector][job] {
+		d := c.draining[targetHash]
+		handedOver := !d.handedOverAt.IsZero() && !now.Before(d.handedOverAt.Add(c.handOverPeriod))
+		if handedOver || now.After(d.deadline) {
+			c.removeDrain(targetHash, d)
+			continue
+		}
+		items = append(items, d.item)
+	}
+	return items
 }
 
-// TargetItems returns a shallow copy of the targetItems map.
+// TargetItems returns a map of every target item, built from the published state without taking a lock.
 func (c *consistentHashingAllocator) TargetItems() map[string]*target.Item {
-	c.m.RLock()
-	defer c.m.RUnlock()
-	targetItemsCopy := make(map[string]*target.Item)
-	for k, v := range c.targetItems {
-		targetItemsCopy[k] = v
+	state := c.state.Load()
+	targetItemsCopy := make(map[string]*target.Item, state.numTargets)
+	for _, item := range state.unassigned {
+		targetItemsCopy[item.Hash()] = item
+	}
+	for _, jobs := range state.targetItemsPerJobPerCollector {
+		for _, items := range jobs {
+			for _, item := range items {
+				targetItemsCopy[item.Hash()] = item
+			}
+		}
 	}
 	return targetItemsCopy
 }
 
-// Collectors returns a shallow copy of the collectors map.
+// Collectors returns a shallow copy of the published collectors map.
 func (c *consistentHashingAllocator) Collectors() map[string]*Collector {
-	c.m.RLock()
-	defer c.m.RUnlock()
-	collectorsCopy := make(map[string]*Collector)
-	for k, v := range c.collectors {
+	collectors := c.state.Load().collectors
+	collectorsCopy := make(map[string]*Collector, len(collectors))
+	for k, v := range collectors {
 		collectorsCopy[k] = v
 	}
 	return collectorsCopy
 }
+
+// Snapshot returns the current state of the hash ring along with the per-collector target counts.
+func (c *consistentHashingAllocator) Snapshot() Snapshot {
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	loads := c.consistentHasher.LoadDistribution()
+	snapshot := Snapshot{
+		Strategy:          consistentHashingStrategyName,
+		PartitionCount:    c.hasherConfig.PartitionCount,
+		ReplicationFactor: c.hasherConfig.ReplicationFactor,
+		Load:              c.hasherConfig.Load,
+		AverageLoad:       c.consistentHasher.AverageLoad(),
+		NumTargets:        len(c.targetItems),
+		NumDraining:       c.numDraining(),
+		Members:           make([]SnapshotMember, 0, len(c.collectors)),
+		PartitionOwners:   make([]string, c.hasherConfig.PartitionCount),
+	}
+	for name, col := range c.collectors {
+		snapshot.Members = append(snapshot.Members, SnapshotMember{
+			Name:       name,
+			Ready:      col.Ready,
+			NumTargets: col.NumTargets,
+			Partitions: loads[name],
+		})
+	}
+	sort.Slice(snapshot.Members, func(i, j int) bool {
+		return snapshot.Members[i].Name < snapshot.Members[j].Name
+	})
+	for partID := range snapshot.PartitionOwners {
+		if owner := c.consistentHasher.GetPartitionOwner(partID); owner != nil {
+			snapshot.PartitionOwners[partID] = owner.String()
+		}
+	}
+	return snapshot
+}
+
+func (c *consistentHashingAllocator) numDraining() int {
+	c.drainMtx.Lock()
+	defer c.drainMtx.Unlock()
+	return len(c.draining)
+}
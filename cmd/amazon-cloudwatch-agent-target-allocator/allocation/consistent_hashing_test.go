@@ -4,10 +4,15 @@
 package allocation
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
 var logger = logf.Log.WithName("unit-tests")
@@ -24,6 +29,21 @@ func TestCanSetSingleTarget(t *testing.T) {
 	}
 }
 
+func TestAllocationLeavesDiscoveredTargetsUnchanged(t *testing.T) {
+	c := newConsistentHashingAllocator(logger)
+	c.SetCollectors(MakeNCollectors(3, 0))
+	targets := MakeNNewTargetsWithEmptyCollectors(10, 0)
+	c.SetTargets(targets)
+	c.SetCollectors(MakeNCollectors(5, 0))
+	for _, item := range targets {
+		// the discovered items are shared with the discoverer, so the collector is set on copies only
+		assert.Empty(t, item.CollectorName)
+	}
+	for _, item := range c.TargetItems() {
+		assert.NotEmpty(t, item.CollectorName)
+	}
+}
+
 func TestRelativelyEvenDistribution(t *testing.T) {
 	numCols := 15
 	numItems := 10000
@@ -134,3 +154,285 @@ func TestTargetsWithNoCollectorsConsistentHashing(t *testing.T) {
 		assert.InDelta(t, col.NumTargets, expectedPerCollector, expectedDelta)
 	}
 }
+
+func TestSnapshotConsistentHashing(t *testing.T) {
+	cols := MakeNCollectors(3, 0)
+	cols["collector-0"].Ready = true
+	c := newConsistentHashingAllocator(logger)
+	c.SetCollectors(cols)
+	c.SetTargets(MakeNNewTargetsWithEmptyCollectors(100, 0))
+
+	snapshot := c.Snapshot()
+	assert.Equal(t, consistentHashingStrategyName, snapshot.Strategy)
+	assert.Equal(t, 100, snapshot.NumTargets)
+	assert.Len(t, snapshot.PartitionOwners, snapshot.PartitionCount)
+	assert.Len(t, snapshot.Members, 3)
+	numTargets := 0
+	var partitions float64
+	for i, member := range snapshot.Members {
+		assert.Equal(t, cols[member.Name].Name, member.Name)
+		assert.Equal(t, i == 0, member.Ready)
+		numTargets += member.NumTargets
+		partitions += member.Partitions
+	}
+	assert.Equal(t, 100, numTargets)
+	assert.Equal(t, float64(snapshot.PartitionCount), partitions)
+	for _, owner := range snapshot.PartitionOwners {
+		assert.Contains(t, cols, owner)
+	}
+}
+
+// TestReplicasAllocateIdentically verifies that allocators reaching the same collectors and targets through different
+// histories produce the same assignment, which is what allows the target allocator to run with multiple replicas.
+func TestReplicasAllocateIdentically(t *testing.T) {
+	targets := MakeNNewTargetsWithEmptyCollectors(1000, 0)
+
+	first := newConsistentHashingAllocator(logger)
+	first.SetCollectors(MakeNCollectors(5, 0))
+	first.SetTargets(targets)
+
+	second := newConsistentHashingAllocator(logger)
+	second.SetTargets(MakeNNewTargetsWithEmptyCollectors(1000, 0))
+	second.SetCollectors(MakeNCollectors(3, 2))
+	second.SetCollectors(MakeNCollectors(7, 0))
+	second.SetCollectors(MakeNCollectors(5, 0))
+
+	firstItems := first.TargetItems()
+	secondItems := second.TargetItems()
+	assert.Len(t, secondItems, len(firstItems))
+	for k, item := range firstItems {
+		assert.Equal(t, item.CollectorName, secondItems[k].CollectorName)
+	}
+	assert.Equal(t, first.Snapshot().PartitionOwners, second.Snapshot().PartitionOwners)
+}
+
+func TestConsistentHashingConfig(t *testing.T) {
+	c := newConsistentHashingAllocator(logger, WithConsistentHashingConfig(ConsistentHashingConfig{
+		PartitionCount: 271,
+		LoadFactor:     1.25,
+	}))
+	c.SetCollectors(MakeNCollectors(3, 0))
+	c.SetTargets(MakeNNewTargetsWithEmptyCollectors(100, 0))
+
+	snapshot := c.Snapshot()
+	assert.Equal(t, 271, snapshot.PartitionCount)
+	assert.Equal(t, 1.25, snapshot.Load)
+	assert.Len(t, snapshot.PartitionOwners, 271)
+	assert.Equal(t, 0, snapshot.NumDraining)
+}
+
+// movedTargets scales the collectors from 3 to 4 and returns the targets that moved, keyed by hash, with the
+// collector they moved from.
+func movedTargets(t *testing.T, c Allocator) map[string]string {
+	c.SetCollectors(MakeNCollectors(3, 0))
+	c.SetTargets(MakeNNewTargetsWithEmptyCollectors(300, 0))
+	before := make(map[string]string)
+	for k, item := range c.TargetItems() {
+		before[k] = item.CollectorName
+	}
+	c.SetCollectors(MakeNCollectors(4, 0))
+	moved := make(map[string]string)
+	for k, item := range c.TargetItems() {
+		if before[k] != item.CollectorName {
+			moved[k] = before[k]
+		}
+	}
+	assert.NotEmpty(t, moved)
+	return moved
+}
+
+func containsTarget(items []*target.Item, hash string) bool {
+	for _, item := range items {
+		if item.Hash() == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDrainMovedTargets(t *testing.T) {
+	now := time.Now()
+	c := newConsistentHashingAllocator(logger, WithConsistentHashingConfig(ConsistentHashingConfig{
+		DrainTimeout: 10 * time.Minute,
+	}))
+	c.(*consistentHashingAllocator).now = func() time.Time { return now }
+	moved := movedTargets(t, c)
+	assert.Equal(t, len(moved), c.Snapshot().NumDraining)
+
+	for hash, previous := range moved {
+		item := c.TargetItems()[hash]
+		// the target is served to both collectors until the new owner has had it for a hand-over period,
+		// however often the new owner fetches it
+		assert.True(t, containsTarget(c.GetTargetsForCollectorAndJob(previous, item.JobName), hash))
+		assert.True(t, containsTarget(c.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), hash))
+		assert.True(t, containsTarget(c.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), hash))
+		assert.True(t, containsTarget(c.GetTargetsForCollectorAndJob(previous, item.JobName), hash))
+	}
+	assert.Equal(t, len(moved), c.Snapshot().NumDraining)
+
+	now = now.Add(defaultHandOverPeriod)
+	for hash, previous := range moved {
+		item := c.TargetItems()[hash]
+		assert.False(t, containsTarget(c.GetTargetsForCollectorAndJob(previous, item.JobName), hash))
+		assert.True(t, containsTarget(c.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), hash))
+	}
+	assert.Equal(t, 0, c.Snapshot().NumDraining)
+}
+
+func TestDrainTimeout(t *testing.T) {
+	now := time.Now()
+	c := newConsistentHashingAllocator(logger, WithConsistentHashingConfig(ConsistentHashingConfig{
+		DrainTimeout: time.Minute,
+	}))
+	c.(*consistentHashingAllocator).now = func() time.Time { return now }
+	moved := movedTargets(t, c)
+
+	now = now.Add(2 * time.Minute)
+	for hash, previous := range moved {
+		item := c.TargetItems()[hash]
+		assert.False(t, containsTarget(c.GetTargetsForCollectorAndJob(previous, item.JobName), hash))
+	}
+	assert.Equal(t, 0, c.Snapshot().NumDraining)
+}
+
+func TestNoDrainFromRemovedCollector(t *testing.T) {
+	c := newConsistentHashingAllocator(logger, WithConsistentHashingConfig(ConsistentHashingConfig{
+		DrainTimeout: time.Minute,
+	}))
+	movedTargets(t, c)
+	c.SetCollectors(MakeNCollectors(2, 0))
+	for _, item := range c.TargetItems() {
+		assert.Contains(t, []string{"collector-0", "collector-1"}, item.CollectorName)
+	}
+	for col := range MakeNCollectors(4, 0) {
+		for _, item := range c.TargetItems() {
+			if containsTarget(c.GetTargetsForCollectorAndJob(col, item.JobName), item.Hash()) {
+				assert.Contains(t, []string{"collector-0", "collector-1"}, col)
+			}
+		}
+	}
+}
+
+func TestNoDrainByDefault(t *testing.T) {
+	c := newConsistentHashingAllocator(logger)
+	moved := movedTargets(t, c)
+	assert.Equal(t, 0, c.Snapshot().NumDraining)
+	for hash, previous := range moved {
+		item := c.TargetItems()[hash]
+		assert.False(t, containsTarget(c.GetTargetsForCollectorAndJob(previous, item.JobName), hash))
+	}
+}
+
+func TestUpdateTargets(t *testing.T) {
+	targets := MakeNNewTargetsWithEmptyCollectors(120, 0)
+	initial := targetsInRange(targets, 0, 100)
+	removed := targetsInRange(targets, 0, 10)
+	added := targetsInRange(targets, 100, 120)
+
+	c := newConsistentHashingAllocator(logger)
+	c.SetCollectors(MakeNCollectors(3, 0))
+	c.UpdateTargets(diff.NewChanges(initial, nil))
+	assert.Len(t, c.TargetItems(), 100)
+	c.UpdateTargets(diff.NewChanges(added, removed))
+
+	expected := newConsistentHashingAllocator(logger)
+	expected.SetCollectors(MakeNCollectors(3, 0))
+	expected.SetTargets(targetsInRange(MakeNNewTargetsWithEmptyCollectors(120, 0), 10, 120))
+
+	actualItems := c.TargetItems()
+	expectedItems := expected.TargetItems()
+	assert.Len(t, actualItems, 110)
+	for k, item := range expectedItems {
+		if assert.Contains(t, actualItems, k) {
+			assert.Equal(t, item.CollectorName, actualItems[k].CollectorName)
+			assert.Contains(t, c.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), actualItems[k])
+		}
+	}
+	for k := range removed {
+		assert.NotContains(t, actualItems, k)
+	}
+	for name, col := range c.Collectors() {
+		assert.Equal(t, expected.Collectors()[name].NumTargets, col.NumTargets)
+	}
+}
+
+func TestUpdateTargetsWithNoCollectors(t *testing.T) {
+	targets := MakeNNewTargets(10, 3, 0)
+	c := newConsistentHashingAllocator(logger)
+	c.UpdateTargets(diff.NewChanges(targets, nil))
+	c.UpdateTargets(diff.NewChanges(nil, targetsInRange(targets, 0, 5)))
+	assert.Len(t, c.TargetItems(), 5)
+
+	c.SetCollectors(MakeNCollectors(3, 0))
+	numTargets := 0
+	for _, col := range c.Collectors() {
+		numTargets += col.NumTargets
+	}
+	assert.Equal(t, 5, numTargets)
+}
+
+// TestPublishedStateIsImmutable verifies that items and collectors handed to readers are not modified by later
+// changes, which is what allows reading without a lock.
+func TestPublishedStateIsImmutable(t *testing.T) {
+	c := newConsistentHashingAllocator(logger)
+	c.SetCollectors(MakeNCollectors(3, 0))
+	c.SetTargets(MakeNNewTargetsWithEmptyCollectors(300, 0))
+
+	itemsBefore := c.TargetItems()
+	collectorsBefore := c.Collectors()
+	assignedBefore := make(map[string]string, len(itemsBefore))
+	for k, item := range itemsBefore {
+		assignedBefore[k] = item.CollectorName
+	}
+	numTargetsBefore := make(map[string]int, len(collectorsBefore))
+	for name, col := range collectorsBefore {
+		numTargetsBefore[name] = col.NumTargets
+	}
+
+	c.SetCollectors(MakeNCollectors(5, 0))
+	moved := 0
+	for k, item := range c.TargetItems() {
+		assert.Equal(t, assignedBefore[k], itemsBefore[k].CollectorName)
+		if item.CollectorName != assignedBefore[k] {
+			moved++
+		}
+	}
+	assert.NotZero(t, moved)
+	for name, col := range collectorsBefore {
+		assert.Equal(t, numTargetsBefore[name], col.NumTargets)
+	}
+}
+
+func TestConcurrentReadsAndUpdates(t *testing.T) {
+	c := newConsistentHashingAllocator(logger)
+	c.SetCollectors(MakeNCollectors(3, 0))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, col := range c.Collectors() {
+				for _, item := range c.GetTargetsForCollectorAndJob(col.Name, "test-job-1") {
+					_ = item.CollectorName
+				}
+			}
+			_ = c.TargetItems()
+		}
+	}()
+	targets := MakeNNewTargetsWithEmptyCollectors(600, 0)
+	c.SetTargets(targetsInRange(targets, 0, 100))
+	for i := 0; i < 50; i++ {
+		c.UpdateTargets(diff.NewChanges(targetsInRange(targets, 100+i*10, 110+i*10), targetsInRange(targets, i*10, 10+i*10)))
+		c.SetCollectors(MakeNCollectors(3+i%3, 0))
+	}
+	close(done)
+	wg.Wait()
+	assert.Len(t, c.TargetItems(), 100)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+// Snapshot is a point-in-time view of an allocator's internal state, intended for debugging.
+type Snapshot struct {
+	Strategy          string           `json:"strategy"`
+	PartitionCount    int              `json:"partition_count"`
+	ReplicationFactor int              `json:"replication_factor"`
+	Load              float64          `json:"load"`
+	AverageLoad       float64          `json:"average_load"`
+	NumTargets        int              `json:"num_targets"`
+	NumDraining       int              `json:"num_draining"`
+	Members           []SnapshotMember `json:"members"`
+	// PartitionOwners maps each partition ID (the slice index) to the name of the collector owning it.
+	PartitionOwners []string `json:"partition_owners"`
+}
+
+// SnapshotMember describes a single collector in a Snapshot.
+type SnapshotMember struct {
+	Name       string  `json:"name"`
+	Ready      bool    `json:"ready"`
+	NumTargets int     `json:"num_targets"`
+	Partitions float64 `json:"partitions"`
+}
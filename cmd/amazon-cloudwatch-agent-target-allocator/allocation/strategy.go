@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
@@ -34,6 +35,15 @@ var (
 		Name: "cloudwatch_agent_allocator_time_to_allocate",
 		Help: "The time it takes to allocate",
 	}, []string{"method", "strategy"})
+	TargetsMoved = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cloudwatch_agent_allocator_targets_moved",
+		Help:    "The number of targets moved to another collector per rebalance.",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+	}, []string{"strategy"})
+	TargetsDraining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_allocator_targets_draining",
+		Help: "The number of moved targets still served to their previous collector.",
+	}, []string{"strategy"})
 	targetsRemaining = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "cloudwatch_agent_allocator_targets_remaining",
 		Help: "Number of targets kept after filtering.",
@@ -46,6 +56,25 @@ type Filter interface {
 	Apply(map[string]*target.Item) map[string]*target.Item
 }
 
+// IncrementalFilter is a Filter that can also be applied to a change set. Filters keeping state about the targets they
+// have seen implement it so that state is released when targets are removed.
+type IncrementalFilter interface {
+	Filter
+	ApplyChanges(diff.Changes[*target.Item]) diff.Changes[*target.Item]
+}
+
+// ApplyFilterToChanges filters the additions in changes. Removals are always kept, as removing a target the allocator
+// does not know is a no-op.
+func ApplyFilterToChanges(filter Filter, changes diff.Changes[*target.Item]) diff.Changes[*target.Item] {
+	if filter == nil {
+		return changes
+	}
+	if incremental, ok := filter.(IncrementalFilter); ok {
+		return incremental.ApplyChanges(changes)
+	}
+	return diff.NewChanges(filter.Apply(changes.Additions()), changes.Removals())
+}
+
 func WithFilter(filter Filter) AllocationOption {
 	return func(allocator Allocator) {
 		allocator.SetFilter(filter)
@@ -82,10 +111,12 @@ func GetRegisteredAllocatorNames() []string {
 type Allocator interface {
 	SetCollectors(collectors map[string]*Collector)
 	SetTargets(targets map[string]*target.Item)
+	UpdateTargets(changes diff.Changes[*target.Item])
 	TargetItems() map[string]*target.Item
 	Collectors() map[string]*Collector
 	GetTargetsForCollectorAndJob(collector string, job string) []*target.Item
 	SetFilter(filter Filter)
+	Snapshot() Snapshot
 }
 
 var _ consistent.Member = Collector{}
@@ -96,6 +127,9 @@ var _ consistent.Member = Collector{}
 type Collector struct {
 	Name       string
 	NumTargets int
+	// Ready reports whether the collector pod has passed its readiness checks. It is informational only and
+	// does not affect allocation decisions.
+	Ready bool
 }
 
 func (c Collector) Hash() string {
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
 func BenchmarkGetAllTargetsByCollectorAndJob(b *testing.B) {
@@ -123,3 +124,50 @@ func TestCollectorDiff(t *testing.T) {
 		})
 	}
 }
+
+// Benchmark_UpdateTargets compares applying a small change incrementally with setting the full target map.
+func Benchmark_UpdateTargets(b *testing.B) {
+	numCollectors := 100
+	for _, numTargets := range []int{10_000, 100_000, 500_000} {
+		// one percent of the targets churn on every update
+		numChanged := numTargets / 100
+		for _, s := range GetRegisteredAllocatorNames() {
+			targets := MakeNNewTargetsWithEmptyCollectors(numTargets+numChanged, 0)
+			a, _ := New(s, logger)
+			a.SetCollectors(MakeNCollectors(numCollectors, 0))
+			a.SetTargets(targetsInRange(targets, 0, numTargets))
+			updates := []diff.Changes[*target.Item]{
+				diff.NewChanges(targetsInRange(targets, numTargets, numTargets+numChanged), targetsInRange(targets, 0, numChanged)),
+				diff.NewChanges(targetsInRange(targets, 0, numChanged), targetsInRange(targets, numTargets, numTargets+numChanged)),
+			}
+			b.Run(fmt.Sprintf("%s_incremental_num_targets_%d", s, numTargets), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					a.UpdateTargets(updates[i%2])
+				}
+			})
+
+			fullSets := []map[string]*target.Item{
+				targetsInRange(targets, numChanged, numTargets+numChanged),
+				targetsInRange(targets, 0, numTargets),
+			}
+			b.Run(fmt.Sprintf("%s_full_num_targets_%d", s, numTargets), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					a.SetTargets(fullSets[i%2])
+				}
+			})
+
+			b.Run(fmt.Sprintf("%s_read_num_targets_%d", s, numTargets), func(b *testing.B) {
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						a.GetTargetsForCollectorAndJob(fmt.Sprintf("collector-%d", i%numCollectors), fmt.Sprintf("test-job-%d", i%numTargets))
+						i++
+					}
+				})
+			})
+		}
+	}
+}
@@ -66,7 +66,7 @@ func (k *Client) Watch(ctx context.Context, labelMap map[string]string, fn func(
 	for i := range pods.Items {
 		pod := pods.Items[i]
 		if pod.GetObjectMeta().GetDeletionTimestamp() == nil {
-			collectorMap[pod.Name] = allocation.NewCollector(pod.Name)
+			collectorMap[pod.Name] = newCollectorFromPod(&pod)
 		}
 	}
 	fn(collectorMap)
@@ -118,7 +118,11 @@ func runWatch(ctx context.Context, k *Client, c <-chan watch.Event, collectorMap
 
 			switch event.Type { //nolint:exhaustive
 			case watch.Added:
-				collectorMap[pod.Name] = allocation.NewCollector(pod.Name)
+				collectorMap[pod.Name] = newCollectorFromPod(pod)
+			case watch.Modified:
+				if col, ok := collectorMap[pod.Name]; ok {
+					col.Ready = isPodReady(pod)
+				}
 			case watch.Deleted:
 				delete(collectorMap, pod.Name)
 			}
@@ -127,6 +131,21 @@ func runWatch(ctx context.Context, k *Client, c <-chan watch.Event, collectorMap
 	}
 }
 
+func newCollectorFromPod(pod *v1.Pod) *allocation.Collector {
+	col := allocation.NewCollector(pod.Name)
+	col.Ready = isPodReady(pod)
+	return col
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (k *Client) Close() {
 	close(k.close)
 }
@@ -96,6 +96,25 @@ func Test_runWatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pod add ready",
+			args: args{
+				kubeFn: func(t *testing.T, client Client, group *sync.WaitGroup) {
+					p := pod("test-pod1")
+					p.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+					group.Add(1)
+					_, err := client.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), p, metav1.CreateOptions{})
+					assert.NoError(t, err)
+				},
+				collectorMap: map[string]*allocation.Collector{},
+			},
+			want: map[string]*allocation.Collector{
+				"test-pod1": {
+					Name:  "test-pod1",
+					Ready: true,
+				},
+			},
+		},
 		{
 			name: "pod delete",
 			args: args{
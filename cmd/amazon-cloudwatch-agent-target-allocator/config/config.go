@@ -56,6 +56,7 @@ type Config struct {
 	ServiceMonitorSelector map[string]string     `yaml:"service_monitor_selector,omitempty"`
 	CollectorSelector      *metav1.LabelSelector `yaml:"collector_selector,omitempty"`
 	HTTPS                  HTTPSServerConfig     `yaml:"https,omitempty"`
+	ConsistentHashing      ConsistentHashing     `yaml:"consistent_hashing,omitempty"`
 }
 
 type PrometheusCRConfig struct {
@@ -63,6 +64,13 @@ type PrometheusCRConfig struct {
 	ScrapeInterval model.Duration `yaml:"scrape_interval,omitempty"`
 }
 
+// ConsistentHashing tunes the consistent-hashing allocation strategy. Zero values keep the allocator defaults.
+type ConsistentHashing struct {
+	PartitionCount int            `yaml:"partition_count,omitempty"`
+	LoadFactor     float64        `yaml:"load_factor,omitempty"`
+	DrainTimeout   model.Duration `yaml:"drain_timeout,omitempty"`
+}
+
 type HTTPSServerConfig struct {
 	Enabled         bool   `yaml:"enabled,omitempty"`
 	ListenAddr      string `yaml:"listen_addr,omitempty"`
@@ -210,6 +218,15 @@ func ValidateConfig(config *Config) error {
 	if !config.PrometheusCR.Enabled && !scrapeConfigsPresent {
 		return fmt.Errorf("at least one scrape config must be defined, or Prometheus CR watching must be enabled")
 	}
+	if config.ConsistentHashing.PartitionCount < 0 {
+		return fmt.Errorf("consistent_hashing.partition_count must be positive, got %d", config.ConsistentHashing.PartitionCount)
+	}
+	if config.ConsistentHashing.LoadFactor != 0 && config.ConsistentHashing.LoadFactor <= 1 {
+		return fmt.Errorf("consistent_hashing.load_factor must be greater than 1, got %v", config.ConsistentHashing.LoadFactor)
+	}
+	if config.ConsistentHashing.DrainTimeout < 0 {
+		return fmt.Errorf("consistent_hashing.drain_timeout must not be negative, got %s", config.ConsistentHashing.DrainTimeout)
+	}
 	return nil
 }
 
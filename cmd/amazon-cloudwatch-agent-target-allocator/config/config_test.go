@@ -19,16 +19,16 @@ func TestLoad(t *testing.T) {
 		file string
 	}
 	tests := []struct {
-		name           string
-		args           args
-		wantErr        assert.ErrorAssertionFunc
-		wantHTTPS      HTTPSServerConfig
-		wantLabels     map[string]string
-		wantPromCR     PrometheusCRConfig
-		wantAlloc      *string
-		wantPodMonSel  map[string]string
-		wantSvcMonSel  map[string]string
-		wantJobNames   []string
+		name          string
+		args          args
+		wantErr       assert.ErrorAssertionFunc
+		wantHTTPS     HTTPSServerConfig
+		wantLabels    map[string]string
+		wantPromCR    PrometheusCRConfig
+		wantAlloc     *string
+		wantPodMonSel map[string]string
+		wantSvcMonSel map[string]string
+		wantJobNames  []string
 	}{
 		{
 			name: "file sd load",
@@ -58,8 +58,8 @@ func TestLoad(t *testing.T) {
 			args: args{
 				file: "./testdata/no_config.yaml",
 			},
-			wantErr:   assert.NoError,
-			wantHTTPS: CreateDefaultConfig().HTTPS,
+			wantErr:    assert.NoError,
+			wantHTTPS:  CreateDefaultConfig().HTTPS,
 			wantLabels: nil,
 			wantPromCR: CreateDefaultConfig().PrometheusCR,
 			wantAlloc:  CreateDefaultConfig().AllocationStrategy,
@@ -154,6 +154,30 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "consistent hashing tuned",
+			fileConfig: Config{
+				PrometheusCR:      PrometheusCRConfig{Enabled: true},
+				ConsistentHashing: ConsistentHashing{PartitionCount: 271, LoadFactor: 1.25, DrainTimeout: model.Duration(time.Minute)},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "consistent hashing load factor too low",
+			fileConfig: Config{
+				PrometheusCR:      PrometheusCRConfig{Enabled: true},
+				ConsistentHashing: ConsistentHashing{LoadFactor: 1},
+			},
+			expectedErr: fmt.Errorf("consistent_hashing.load_factor must be greater than 1, got 1"),
+		},
+		{
+			name: "consistent hashing negative partition count",
+			fileConfig: Config{
+				PrometheusCR:      PrometheusCRConfig{Enabled: true},
+				ConsistentHashing: ConsistentHashing{PartitionCount: -1},
+			},
+			expectedErr: fmt.Errorf("consistent_hashing.partition_count must be positive, got -1"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/oklog/run"
 	"github.com/prometheus/client_golang/prometheus"
@@ -73,13 +74,23 @@ func main() {
 	log := ctrl.Log.WithName("allocator")
 
 	allocatorPrehook = prehook.New(cfg.GetTargetsFilterStrategy(), log)
-	allocator, err = allocation.New(cfg.GetAllocationStrategy(), log, allocation.WithFilter(allocatorPrehook))
+	allocator, err = allocation.New(cfg.GetAllocationStrategy(), log,
+		allocation.WithFilter(allocatorPrehook),
+		allocation.WithConsistentHashingConfig(allocation.ConsistentHashingConfig{
+			PartitionCount: cfg.ConsistentHashing.PartitionCount,
+			LoadFactor:     cfg.ConsistentHashing.LoadFactor,
+			DrainTimeout:   time.Duration(cfg.ConsistentHashing.DrainTimeout),
+		}),
+	)
 	if err != nil {
 		setupLog.Error(err, "Unable to initialize allocation strategy")
 		os.Exit(1)
 	}
 
 	httpOptions := []server.Option{}
+	if dropReporter, ok := allocatorPrehook.(prehook.DropReporter); ok {
+		httpOptions = append(httpOptions, server.WithDropReporter(dropReporter))
+	}
 	tlsConfig, confErr := cfg.HTTPS.NewTLSConfig(ctx)
 	if confErr != nil {
 		setupLog.Error(confErr, "Unable to initialize TLS configuration", "Config", cfg.HTTPS)
@@ -166,7 +177,7 @@ func main() {
 				setupLog.Error(err, "Unable to apply initial configuration")
 				return err
 			}
-			err := targetDiscoverer.Watch(allocator.SetTargets)
+			err := targetDiscoverer.Watch(allocator)
 			setupLog.Info("Target discoverer exited")
 			return err
 		},
@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var (
+	_ DropReporter      = &ChainedTargetFilter{}
+	_ TargetLimitSetter = &ChainedTargetFilter{}
+)
+
+// ChainedTargetFilter applies several hooks in order, each one filtering the targets kept by the previous one.
+type ChainedTargetFilter struct {
+	hooks []Hook
+}
+
+func NewChainedTargetFilter(hooks ...Hook) Hook {
+	return &ChainedTargetFilter{hooks: hooks}
+}
+
+func (tf *ChainedTargetFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	for _, hook := range tf.hooks {
+		targets = hook.Apply(targets)
+	}
+	return targets
+}
+
+// ApplyChanges passes the changes through every hook. Removals go through every hook too, since each of them may
+// have dropped the removed targets.
+func (tf *ChainedTargetFilter) ApplyChanges(changes diff.Changes[*target.Item]) diff.Changes[*target.Item] {
+	for _, hook := range tf.hooks {
+		changes = hook.ApplyChanges(changes)
+	}
+	return changes
+}
+
+// DroppedTargets returns the targets dropped by every hook of the chain that reports them.
+func (tf *ChainedTargetFilter) DroppedTargets() []DroppedTarget {
+	var dropped []DroppedTarget
+	for _, hook := range tf.hooks {
+		if reporter, ok := hook.(DropReporter); ok {
+			dropped = append(dropped, reporter.DroppedTargets()...)
+		}
+	}
+	return dropped
+}
+
+func (tf *ChainedTargetFilter) SetTargetLimits(limits map[string]uint) {
+	for _, hook := range tf.hooks {
+		if setter, ok := hook.(TargetLimitSetter); ok {
+			setter.SetTargetLimits(limits)
+		}
+	}
+}
+
+func (tf *ChainedTargetFilter) SetConfig(cfgs map[string][]*relabel.Config) {
+	for _, hook := range tf.hooks {
+		hook.SetConfig(cfgs)
+	}
+}
+
+// GetConfig merges the relabel configs of every hook of the chain, in the order the hooks are applied.
+func (tf *ChainedTargetFilter) GetConfig() map[string][]*relabel.Config {
+	cfgs := make(map[string][]*relabel.Config)
+	for _, hook := range tf.hooks {
+		for job, jobCfgs := range hook.GetConfig() {
+			cfgs[job] = append(cfgs[job], jobCfgs...)
+		}
+	}
+	return cfgs
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestNewChained(t *testing.T) {
+	assert.IsType(t, &RelabelConfigTargetFilter{}, New("relabel-config", logger))
+	assert.IsType(t, &ChainedTargetFilter{}, New("relabel-config,drop-unhealthy", logger))
+	assert.IsType(t, &ChainedTargetFilter{}, New("relabel-config, unknown", logger))
+	assert.Nil(t, New("unknown", logger))
+	assert.Nil(t, New("unknown,other", logger))
+}
+
+func TestChainedApply(t *testing.T) {
+	allocatorPrehook := New("relabel-config,drop-unhealthy,limit-per-job", logger)
+	require.NotNil(t, allocatorPrehook)
+	reporter := allocatorPrehook.(DropReporter)
+
+	dropCfg := relabelConfigs[4].cfg[0]
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{"relabeled": {dropCfg}})
+	allocatorPrehook.(TargetLimitSetter).SetTargetLimits(map[string]uint{"limited": 1})
+	assert.Equal(t, map[string][]*relabel.Config{"relabeled": {dropCfg}}, allocatorPrehook.GetConfig())
+
+	relabeled := target.NewItem("relabeled", "url", model.LabelSet{"i": "0"}, "")
+	unhealthy := target.NewItem("limited", "unhealthy-url", model.LabelSet{podPhaseLabel: "Failed"}, "")
+	limited := makeJobTargets("limited", 2)
+	targets := copyTargets(limited)
+	targets[relabeled.Hash()] = relabeled
+	targets[unhealthy.Hash()] = unhealthy
+
+	remainingItems := allocatorPrehook.Apply(targets)
+	assert.Equal(t, lowestHashes(limited, 1), remainingItems)
+	assert.Len(t, reporter.DroppedTargets(), 3)
+
+	// removals reach every hook of the chain
+	changes := allocatorPrehook.ApplyChanges(diff.NewChanges(nil, map[string]*target.Item{
+		relabeled.Hash(): relabeled,
+		unhealthy.Hash(): unhealthy,
+	}))
+	assert.Len(t, changes.Removals(), 2)
+	assert.Len(t, reporter.DroppedTargets(), 1)
+}
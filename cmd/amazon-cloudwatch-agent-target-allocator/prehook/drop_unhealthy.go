@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+const (
+	// podPhaseLabel and podReadyLabel are set by the kubernetes service discovery on targets backed by a pod.
+	podPhaseLabel model.LabelName = "__meta_kubernetes_pod_phase"
+	podReadyLabel model.LabelName = "__meta_kubernetes_pod_ready"
+
+	podPhaseRunning = "Running"
+	podReady        = "true"
+)
+
+var _ DropReporter = &DropUnhealthyTargetFilter{}
+
+// DropUnhealthyTargetFilter drops the targets whose pod is not Running and Ready, so that no collector is assigned a
+// target it can't scrape. Targets that are not backed by a pod are kept.
+type DropUnhealthyTargetFilter struct {
+	log     logr.Logger
+	dropped *droppedTargets
+}
+
+func NewDropUnhealthyTargetFilter(log logr.Logger) Hook {
+	return &DropUnhealthyTargetFilter{
+		log:     log,
+		dropped: newDroppedTargets(),
+	}
+}
+
+func (tf *DropUnhealthyTargetFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	numTargets := len(targets)
+	tf.dropped.set(tf.filter(targets))
+	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
+
+// ApplyChanges filters the added targets and keeps the dropped targets in sync with the removals.
+func (tf *DropUnhealthyTargetFilter) ApplyChanges(changes diff.Changes[*target.Item]) diff.Changes[*target.Item] {
+	numTargets := len(changes.Additions())
+	tf.dropped.update(changes.Removals(), tf.filter(changes.Additions()))
+	tf.log.V(2).Info("Filtering changes complete", "seen", numTargets, "kept", len(changes.Additions()), "removed", len(changes.Removals()))
+	return changes
+}
+
+// filter deletes the targets of unhealthy pods from targets and returns them.
+func (tf *DropUnhealthyTargetFilter) filter(targets map[string]*target.Item) map[string]DroppedTarget {
+	dropped := make(map[string]DroppedTarget)
+	for k, tItem := range targets {
+		if reason := unhealthyReason(tItem.Labels); reason != "" {
+			delete(targets, k)
+			dropped[k] = DroppedTarget{Item: tItem, Reason: reason}
+		}
+	}
+	return dropped
+}
+
+// unhealthyReason returns why the pod of a target is unhealthy, or an empty string if it is healthy.
+func unhealthyReason(lbls model.LabelSet) string {
+	if phase, ok := lbls[podPhaseLabel]; ok && phase != podPhaseRunning {
+		return fmt.Sprintf("pod phase is %s", phase)
+	}
+	if ready, ok := lbls[podReadyLabel]; ok && ready != podReady {
+		return "pod is not ready"
+	}
+	return ""
+}
+
+// DroppedTargets returns the discovered targets that are currently dropped.
+func (tf *DropUnhealthyTargetFilter) DroppedTargets() []DroppedTarget {
+	return tf.dropped.list()
+}
+
+// SetConfig is a no-op, the hook doesn't use the relabel configs.
+func (tf *DropUnhealthyTargetFilter) SetConfig(map[string][]*relabel.Config) {}
+
+// GetConfig returns no relabel configs, the hook doesn't use them.
+func (tf *DropUnhealthyTargetFilter) GetConfig() map[string][]*relabel.Config {
+	return make(map[string][]*relabel.Config)
+}
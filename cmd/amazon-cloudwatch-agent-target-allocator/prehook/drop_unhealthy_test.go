@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestDropUnhealthyApply(t *testing.T) {
+	allocatorPrehook := New("drop-unhealthy", logger)
+	assert.NotNil(t, allocatorPrehook)
+	reporter := allocatorPrehook.(DropReporter)
+
+	healthy := target.NewItem("job", "healthy-url", model.LabelSet{podPhaseLabel: "Running", podReadyLabel: "true"}, "")
+	pending := target.NewItem("job", "pending-url", model.LabelSet{podPhaseLabel: "Pending", podReadyLabel: "false"}, "")
+	notReady := target.NewItem("job", "not-ready-url", model.LabelSet{podPhaseLabel: "Running", podReadyLabel: "false"}, "")
+	noPod := target.NewItem("job", "no-pod-url", model.LabelSet{"i": "0"}, "")
+
+	remainingItems := allocatorPrehook.Apply(map[string]*target.Item{
+		healthy.Hash():  healthy,
+		pending.Hash():  pending,
+		notReady.Hash(): notReady,
+		noPod.Hash():    noPod,
+	})
+	assert.Equal(t, map[string]*target.Item{healthy.Hash(): healthy, noPod.Hash(): noPod}, remainingItems)
+
+	reasons := map[string]string{}
+	for _, dropped := range reporter.DroppedTargets() {
+		reasons[dropped.Item.Hash()] = dropped.Reason
+	}
+	assert.Equal(t, map[string]string{
+		pending.Hash():  "pod phase is Pending",
+		notReady.Hash(): "pod is not ready",
+	}, reasons)
+}
+
+func TestDropUnhealthyApplyChanges(t *testing.T) {
+	allocatorPrehook := New("drop-unhealthy", logger)
+	reporter := allocatorPrehook.(DropReporter)
+
+	healthy := target.NewItem("job", "url", model.LabelSet{podPhaseLabel: "Running", podReadyLabel: "true"}, "")
+	notReady := target.NewItem("job", "url", model.LabelSet{podPhaseLabel: "Running", podReadyLabel: "false"}, "")
+
+	changes := allocatorPrehook.ApplyChanges(diff.NewChanges(map[string]*target.Item{notReady.Hash(): notReady}, nil))
+	assert.Empty(t, changes.Additions())
+	assert.Len(t, reporter.DroppedTargets(), 1)
+
+	// the pod becoming ready replaces the target with one carrying the new labels
+	changes = allocatorPrehook.ApplyChanges(diff.NewChanges(
+		map[string]*target.Item{healthy.Hash(): healthy},
+		map[string]*target.Item{notReady.Hash(): notReady},
+	))
+	assert.Equal(t, map[string]*target.Item{healthy.Hash(): healthy}, changes.Additions())
+	assert.Empty(t, reporter.DroppedTargets())
+}
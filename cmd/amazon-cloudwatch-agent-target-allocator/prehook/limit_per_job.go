@@ -0,0 +1,249 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var (
+	targetsOverLimit = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_allocator_targets_dropped_by_limit",
+		Help: "Number of targets dropped because their job has more targets than its target_limit.",
+	}, []string{"job_name"})
+)
+
+var (
+	_ DropReporter      = &LimitPerJobTargetFilter{}
+	_ TargetLimitSetter = &LimitPerJobTargetFilter{}
+)
+
+// LimitPerJobTargetFilter enforces the target_limit of each job's scrape config before allocation. Instead of every
+// scrape of the job failing once the limit is exceeded, the targets with the lowest hashes are kept and the others are
+// dropped. Keeping the lowest hashes makes every target allocator replica keep the same targets.
+type LimitPerJobTargetFilter struct {
+	log     logr.Logger
+	dropped *droppedTargets
+
+	// mtx protects limits and jobs, since the limits are set when the scrape configs change.
+	mtx sync.Mutex
+	// job name -> maximum number of targets, jobs without a limit are absent
+	limits map[string]uint
+	// job name -> targets of the job, only for jobs with a limit
+	jobs map[string]*limitedJob
+}
+
+// limitedJob holds the targets of a job with a limit, split into the targets kept and those over the limit.
+type limitedJob struct {
+	// target item hash -> target item
+	kept map[string]*target.Item
+	// target item hash -> target item
+	over map[string]*target.Item
+}
+
+func NewLimitPerJobTargetFilter(log logr.Logger) Hook {
+	return &LimitPerJobTargetFilter{
+		log:     log,
+		dropped: newDroppedTargets(),
+		limits:  make(map[string]uint),
+		jobs:    make(map[string]*limitedJob),
+	}
+}
+
+func (tf *LimitPerJobTargetFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	tf.mtx.Lock()
+	defer tf.mtx.Unlock()
+
+	numTargets := len(targets)
+	candidates := make(map[string]map[string]*target.Item)
+	for k, tItem := range targets {
+		if _, ok := tf.limits[tItem.JobName]; !ok {
+			continue
+		}
+		if candidates[tItem.JobName] == nil {
+			candidates[tItem.JobName] = make(map[string]*target.Item)
+		}
+		candidates[tItem.JobName][k] = tItem
+	}
+
+	jobs := make(map[string]*limitedJob, len(candidates))
+	dropped := make(map[string]DroppedTarget)
+	for jobName, jobTargets := range candidates {
+		job := tf.limit(jobName, jobTargets)
+		for k, tItem := range job.over {
+			delete(targets, k)
+			dropped[k] = tf.droppedTarget(jobName, tItem)
+		}
+		jobs[jobName] = job
+	}
+	tf.setJobs(jobs)
+	tf.dropped.set(dropped)
+
+	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
+
+// ApplyChanges filters the added targets. Removing a kept target makes room for a target over the limit, which is
+// then added, and a new target with a lower hash than a kept one replaces it, which is then removed.
+func (tf *LimitPerJobTargetFilter) ApplyChanges(changes diff.Changes[*target.Item]) diff.Changes[*target.Item] {
+	tf.mtx.Lock()
+	defer tf.mtx.Unlock()
+
+	additions := make(map[string]*target.Item, len(changes.Additions()))
+	removals := make(map[string]*target.Item, len(changes.Removals()))
+	// forgotten holds the targets no longer dropped, including those now kept after being over the limit
+	forgotten := make(map[string]*target.Item, len(changes.Removals()))
+	for k, tItem := range changes.Removals() {
+		removals[k] = tItem
+		forgotten[k] = tItem
+	}
+
+	// job name -> targets of the job after the changes
+	candidates := make(map[string]map[string]*target.Item)
+	jobCandidates := func(jobName string) map[string]*target.Item {
+		if c, ok := candidates[jobName]; ok {
+			return c
+		}
+		c := make(map[string]*target.Item)
+		if job, ok := tf.jobs[jobName]; ok {
+			for k, tItem := range job.kept {
+				c[k] = tItem
+			}
+			for k, tItem := range job.over {
+				c[k] = tItem
+			}
+		}
+		candidates[jobName] = c
+		return c
+	}
+	for k, tItem := range changes.Removals() {
+		if _, ok := tf.limits[tItem.JobName]; ok {
+			delete(jobCandidates(tItem.JobName), k)
+		}
+	}
+	for k, tItem := range changes.Additions() {
+		if _, ok := tf.limits[tItem.JobName]; !ok {
+			additions[k] = tItem
+			continue
+		}
+		jobCandidates(tItem.JobName)[k] = tItem
+	}
+
+	jobs := make(map[string]*limitedJob, len(tf.jobs))
+	for jobName, job := range tf.jobs {
+		jobs[jobName] = job
+	}
+	dropped := make(map[string]DroppedTarget)
+	for jobName, jobTargets := range candidates {
+		job := tf.limit(jobName, jobTargets)
+		previous, ok := tf.jobs[jobName]
+		if !ok {
+			previous = &limitedJob{}
+		}
+		for k, tItem := range job.kept {
+			if _, wasKept := previous.kept[k]; !wasKept {
+				additions[k] = tItem
+			}
+		}
+		for k, tItem := range previous.kept {
+			if _, isKept := job.kept[k]; !isKept {
+				removals[k] = tItem
+			}
+		}
+		for k, tItem := range job.over {
+			dropped[k] = tf.droppedTarget(jobName, tItem)
+		}
+		for k, tItem := range previous.over {
+			if _, isOver := job.over[k]; !isOver {
+				forgotten[k] = tItem
+			}
+		}
+		if len(jobTargets) == 0 {
+			delete(jobs, jobName)
+			continue
+		}
+		jobs[jobName] = job
+	}
+	tf.setJobs(jobs)
+	tf.dropped.update(forgotten, dropped)
+
+	tf.log.V(2).Info("Filtering changes complete", "seen", len(changes.Additions()), "kept", len(additions), "removed", len(removals))
+	return diff.NewChanges(additions, removals)
+}
+
+// limit splits the targets of a job into the targets kept, which are those with the lowest hashes, and those over the
+// job's limit.
+func (tf *LimitPerJobTargetFilter) limit(jobName string, jobTargets map[string]*target.Item) *limitedJob {
+	job := &limitedJob{
+		kept: make(map[string]*target.Item, len(jobTargets)),
+		over: make(map[string]*target.Item),
+	}
+	hashes := make([]string, 0, len(jobTargets))
+	for k := range jobTargets {
+		hashes = append(hashes, k)
+	}
+	sort.Strings(hashes)
+	limit := tf.limits[jobName]
+	for i, k := range hashes {
+		if uint(i) < limit {
+			job.kept[k] = jobTargets[k]
+		} else {
+			job.over[k] = jobTargets[k]
+		}
+	}
+	return job
+}
+
+// setJobs replaces the targets of the jobs with a limit and updates the number of targets dropped for each job.
+func (tf *LimitPerJobTargetFilter) setJobs(jobs map[string]*limitedJob) {
+	for jobName := range tf.jobs {
+		if _, ok := jobs[jobName]; !ok {
+			targetsOverLimit.DeleteLabelValues(jobName)
+		}
+	}
+	for jobName, job := range jobs {
+		targetsOverLimit.WithLabelValues(jobName).Set(float64(len(job.over)))
+	}
+	tf.jobs = jobs
+}
+
+func (tf *LimitPerJobTargetFilter) droppedTarget(jobName string, tItem *target.Item) DroppedTarget {
+	return DroppedTarget{Item: tItem, Reason: fmt.Sprintf("job exceeds its target_limit of %d", tf.limits[jobName])}
+}
+
+// SetTargetLimits sets the target_limit of each job. A limit of 0 means the job has no limit.
+func (tf *LimitPerJobTargetFilter) SetTargetLimits(limits map[string]uint) {
+	tf.mtx.Lock()
+	defer tf.mtx.Unlock()
+	limitsCopy := make(map[string]uint)
+	for jobName, limit := range limits {
+		if limit > 0 {
+			limitsCopy[jobName] = limit
+		}
+	}
+	tf.limits = limitsCopy
+}
+
+// DroppedTargets returns the discovered targets that are currently dropped.
+func (tf *LimitPerJobTargetFilter) DroppedTargets() []DroppedTarget {
+	return tf.dropped.list()
+}
+
+// SetConfig is a no-op, the hook doesn't use the relabel configs.
+func (tf *LimitPerJobTargetFilter) SetConfig(map[string][]*relabel.Config) {}
+
+// GetConfig returns no relabel configs, the hook doesn't use them.
+func (tf *LimitPerJobTargetFilter) GetConfig() map[string][]*relabel.Config {
+	return make(map[string][]*relabel.Config)
+}
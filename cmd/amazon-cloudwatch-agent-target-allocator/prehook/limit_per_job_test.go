@@ -0,0 +1,149 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func makeJobTargets(jobName string, n int) map[string]*target.Item {
+	targets := make(map[string]*target.Item, n)
+	for i := 0; i < n; i++ {
+		item := target.NewItem(jobName, fmt.Sprintf("url-%d", i), model.LabelSet{"i": model.LabelValue(fmt.Sprint(i))}, "")
+		targets[item.Hash()] = item
+	}
+	return targets
+}
+
+// lowestHashes returns the n targets with the lowest hashes.
+func lowestHashes(targets map[string]*target.Item, n int) map[string]*target.Item {
+	hashes := make([]string, 0, len(targets))
+	for k := range targets {
+		hashes = append(hashes, k)
+	}
+	sort.Strings(hashes)
+	lowest := make(map[string]*target.Item, n)
+	for _, k := range hashes[:n] {
+		lowest[k] = targets[k]
+	}
+	return lowest
+}
+
+func copyTargets(targets map[string]*target.Item) map[string]*target.Item {
+	targetsCopy := make(map[string]*target.Item, len(targets))
+	for k, v := range targets {
+		targetsCopy[k] = v
+	}
+	return targetsCopy
+}
+
+func TestLimitPerJobApply(t *testing.T) {
+	allocatorPrehook := New("limit-per-job", logger)
+	require.NotNil(t, allocatorPrehook)
+	allocatorPrehook.(TargetLimitSetter).SetTargetLimits(map[string]uint{"limited": 3, "unlimited": 0})
+	reporter := allocatorPrehook.(DropReporter)
+
+	limited := makeJobTargets("limited", 10)
+	unlimited := makeJobTargets("unlimited", 10)
+	targets := copyTargets(limited)
+	for k, v := range unlimited {
+		targets[k] = v
+	}
+
+	remainingItems := allocatorPrehook.Apply(targets)
+	expected := lowestHashes(limited, 3)
+	for k, v := range unlimited {
+		expected[k] = v
+	}
+	assert.Equal(t, expected, remainingItems)
+	assert.Len(t, reporter.DroppedTargets(), 7)
+	assert.Equal(t, "job exceeds its target_limit of 3", reporter.DroppedTargets()[0].Reason)
+	assert.Equal(t, float64(7), testutil.ToFloat64(targetsOverLimit.WithLabelValues("limited")))
+}
+
+func TestLimitPerJobApplyChanges(t *testing.T) {
+	allocatorPrehook := New("limit-per-job", logger)
+	allocatorPrehook.(TargetLimitSetter).SetTargetLimits(map[string]uint{"limited": 3})
+	reporter := allocatorPrehook.(DropReporter)
+
+	all := makeJobTargets("limited", 10)
+	kept := allocatorPrehook.Apply(copyTargets(all))
+	require.Len(t, kept, 3)
+
+	// removing a kept target promotes the target over the limit with the lowest hash
+	removed := lowestHashes(kept, 1)
+	remaining := copyTargets(all)
+	for k := range removed {
+		delete(remaining, k)
+	}
+	promoted := lowestHashes(remaining, 3)
+	for k := range kept {
+		delete(promoted, k)
+	}
+	changes := allocatorPrehook.ApplyChanges(diff.NewChanges(nil, removed))
+	assert.Equal(t, removed, changes.Removals())
+	assert.Equal(t, promoted, changes.Additions())
+	assert.Len(t, reporter.DroppedTargets(), 6)
+	assert.Equal(t, float64(6), testutil.ToFloat64(targetsOverLimit.WithLabelValues("limited")))
+
+	// adding back a target with a lower hash than a kept one demotes the kept target with the highest hash
+	changes = allocatorPrehook.ApplyChanges(diff.NewChanges(removed, nil))
+	assert.Equal(t, removed, changes.Additions())
+	assert.Equal(t, promoted, changes.Removals())
+	assert.Len(t, reporter.DroppedTargets(), 7)
+
+	// removing every target forgets the job
+	changes = allocatorPrehook.ApplyChanges(diff.NewChanges(nil, all))
+	assert.Empty(t, changes.Additions())
+	assert.Equal(t, all, changes.Removals())
+	assert.Empty(t, reporter.DroppedTargets())
+}
+
+func TestLimitPerJobMatchesFullApply(t *testing.T) {
+	limits := map[string]uint{"limited": 5}
+	incremental := New("limit-per-job", logger)
+	incremental.(TargetLimitSetter).SetTargetLimits(limits)
+
+	all := makeJobTargets("limited", 20)
+	current := map[string]*target.Item{}
+	kept := map[string]*target.Item{}
+	i := 0
+	for k, v := range all {
+		additions := map[string]*target.Item{k: v}
+		removals := map[string]*target.Item{}
+		// remove every third target again to exercise promotions
+		if i%3 == 2 {
+			for rk, rv := range current {
+				removals[rk] = rv
+				break
+			}
+		}
+		i++
+		for rk := range removals {
+			delete(current, rk)
+		}
+		current[k] = v
+		changes := incremental.ApplyChanges(diff.NewChanges(additions, removals))
+		for rk := range changes.Removals() {
+			delete(kept, rk)
+		}
+		for ak, av := range changes.Additions() {
+			kept[ak] = av
+		}
+
+		full := New("limit-per-job", logger)
+		full.(TargetLimitSetter).SetTargetLimits(limits)
+		assert.Equal(t, full.Apply(copyTargets(current)), kept)
+	}
+}
@@ -5,36 +5,127 @@ package prehook
 
 import (
 	"errors"
+	"strings"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/prometheus/model/relabel"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
 const (
 	relabelConfigTargetFilterName = "relabel-config"
+	dropUnhealthyTargetFilterName = "drop-unhealthy"
+	limitPerJobTargetFilterName   = "limit-per-job"
+
+	// filterStrategySeparator separates the names of the hooks chained by a filter strategy.
+	filterStrategySeparator = ","
 )
 
 type Hook interface {
 	Apply(map[string]*target.Item) map[string]*target.Item
+	// ApplyChanges filters the additions of an incremental update and forgets the removed targets.
+	ApplyChanges(diff.Changes[*target.Item]) diff.Changes[*target.Item]
 	SetConfig(map[string][]*relabel.Config)
 	GetConfig() map[string][]*relabel.Config
 }
 
+// DropReporter is implemented by hooks that can explain why a target currently discovered was filtered out.
+type DropReporter interface {
+	DroppedTargets() []DroppedTarget
+}
+
+// TargetLimitSetter is implemented by hooks enforcing the target_limit of each job's scrape config.
+type TargetLimitSetter interface {
+	SetTargetLimits(map[string]uint)
+}
+
+// DroppedTarget describes a target removed by a hook and the rule responsible for it.
+type DroppedTarget struct {
+	Item *target.Item
+	// RuleIndex is the position of the dropping rule within the job's relabel_configs.
+	RuleIndex int
+	Rule      *relabel.Config
+	// Reason explains why the target was dropped by a hook that doesn't use relabel rules.
+	Reason string
+}
+
+// droppedTargets tracks the targets currently dropped by a hook. It is written by Apply and ApplyChanges and read
+// by the debug server.
+type droppedTargets struct {
+	mtx sync.RWMutex
+	// target item hash -> dropped target
+	targets map[string]DroppedTarget
+}
+
+func newDroppedTargets() *droppedTargets {
+	return &droppedTargets{targets: make(map[string]DroppedTarget)}
+}
+
+// set replaces the dropped targets after a full sync.
+func (d *droppedTargets) set(dropped map[string]DroppedTarget) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.targets = dropped
+}
+
+// update forgets the removed targets and records the newly dropped ones.
+func (d *droppedTargets) update(removals map[string]*target.Item, dropped map[string]DroppedTarget) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for k := range removals {
+		delete(d.targets, k)
+	}
+	for k, t := range dropped {
+		d.targets[k] = t
+	}
+}
+
+func (d *droppedTargets) list() []DroppedTarget {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	dropped := make([]DroppedTarget, 0, len(d.targets))
+	for _, t := range d.targets {
+		dropped = append(dropped, t)
+	}
+	return dropped
+}
+
 type HookProvider func(log logr.Logger) Hook
 
 var (
 	registry = map[string]HookProvider{}
 )
 
+// New returns the hook registered under name. A comma separated list of names chains the hooks, which are applied
+// in the order given.
 func New(name string, log logr.Logger) Hook {
-	if p, ok := registry[name]; ok {
-		return p(log.WithName("Prehook").WithName(name))
+	if !strings.Contains(name, filterStrategySeparator) {
+		if p, ok := registry[name]; ok {
+			return p(log.WithName("Prehook").WithName(name))
+		}
+
+		log.Info("Unrecognized filter strategy; filtering disabled")
+		return nil
 	}
 
-	log.Info("Unrecognized filter strategy; filtering disabled")
-	return nil
+	var hooks []Hook
+	for _, n := range strings.Split(name, filterStrategySeparator) {
+		n = strings.TrimSpace(n)
+		p, ok := registry[n]
+		if !ok {
+			log.Info("Unrecognized filter strategy in chain; skipping it", "filterStrategy", n)
+			continue
+		}
+		hooks = append(hooks, p(log.WithName("Prehook").WithName(n)))
+	}
+	if len(hooks) == 0 {
+		log.Info("No recognized filter strategy in chain; filtering disabled")
+		return nil
+	}
+	return NewChainedTargetFilter(hooks...)
 }
 
 func Register(name string, provider HookProvider) error {
@@ -46,8 +137,14 @@ func Register(name string, provider HookProvider) error {
 }
 
 func init() {
-	err := Register(relabelConfigTargetFilterName, NewRelabelConfigTargetFilter)
-	if err != nil {
-		panic(err)
+	providers := map[string]HookProvider{
+		relabelConfigTargetFilterName: NewRelabelConfigTargetFilter,
+		dropUnhealthyTargetFilterName: NewDropUnhealthyTargetFilter,
+		limitPerJobTargetFilterName:   NewLimitPerJobTargetFilter,
+	}
+	for name, provider := range providers {
+		if err := Register(name, provider); err != nil {
+			panic(err)
+		}
 	}
 }
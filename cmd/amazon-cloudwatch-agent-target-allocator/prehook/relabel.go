@@ -9,18 +9,23 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/relabel"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
+var _ DropReporter = &RelabelConfigTargetFilter{}
+
 type RelabelConfigTargetFilter struct {
 	log        logr.Logger
 	relabelCfg map[string][]*relabel.Config
+	dropped    *droppedTargets
 }
 
 func NewRelabelConfigTargetFilter(log logr.Logger) Hook {
 	return &RelabelConfigTargetFilter{
 		log:        log,
 		relabelCfg: make(map[string][]*relabel.Config),
+		dropped:    newDroppedTargets(),
 	}
 }
 
@@ -38,22 +43,53 @@ func (tf *RelabelConfigTargetFilter) Apply(targets map[string]*target.Item) map[
 
 	// need to wait until relabelCfg is set
 	if len(tf.relabelCfg) == 0 {
+		tf.dropped.set(make(map[string]DroppedTarget))
 		return targets
 	}
 
+	tf.dropped.set(tf.filter(targets))
+
+	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
+
+// ApplyChanges filters the added targets and keeps the dropped targets in sync with the removals.
+func (tf *RelabelConfigTargetFilter) ApplyChanges(changes diff.Changes[*target.Item]) diff.Changes[*target.Item] {
+	if len(tf.relabelCfg) == 0 {
+		tf.dropped.set(make(map[string]DroppedTarget))
+		return changes
+	}
+
+	numTargets := len(changes.Additions())
+	tf.dropped.update(changes.Removals(), tf.filter(changes.Additions()))
+
+	tf.log.V(2).Info("Filtering changes complete", "seen", numTargets, "kept", len(changes.Additions()), "removed", len(changes.Removals()))
+	return changes
+}
+
+// filter deletes the targets dropped by their job's relabel_configs from targets and returns them.
+func (tf *RelabelConfigTargetFilter) filter(targets map[string]*target.Item) map[string]DroppedTarget {
+	dropped := make(map[string]DroppedTarget)
 	// Note: jobNameKey != tItem.JobName (jobNameKey is hashed)
 	for jobNameKey, tItem := range targets {
 		lset := convertLabelToPromLabelSet(tItem.Labels)
 		lb := labels.NewBuilder(lset)
-		keep := relabel.ProcessBuilder(lb, tf.relabelCfg[tItem.JobName]...)
-
-		if !keep {
-			delete(targets, jobNameKey)
+		// Process the rules one at a time rather than through relabel.ProcessBuilder so that the
+		// rule dropping the target can be reported. The outcome is identical.
+		for i, cfg := range tf.relabelCfg[tItem.JobName] {
+			if !relabel.ProcessBuilder(lb, cfg) {
+				delete(targets, jobNameKey)
+				dropped[jobNameKey] = DroppedTarget{Item: tItem, RuleIndex: i, Rule: cfg}
+				break
+			}
 		}
 	}
+	return dropped
+}
 
-	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
-	return targets
+// DroppedTargets returns the discovered targets that are currently dropped.
+func (tf *RelabelConfigTargetFilter) DroppedTargets() []DroppedTarget {
+	return tf.dropped.list()
 }
 
 func (tf *RelabelConfigTargetFilter) SetConfig(cfgs map[string][]*relabel.Config) {
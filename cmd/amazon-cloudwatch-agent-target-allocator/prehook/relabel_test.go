@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
@@ -268,3 +269,85 @@ func TestSetConfig(t *testing.T) {
 	allocatorPrehook.SetConfig(relabelCfg)
 	assert.Equal(t, relabelCfg, allocatorPrehook.GetConfig())
 }
+
+func TestDroppedTargets(t *testing.T) {
+	allocatorPrehook := New("relabel-config", logger)
+	assert.NotNil(t, allocatorPrehook)
+	reporter, ok := allocatorPrehook.(DropReporter)
+	assert.True(t, ok)
+
+	keepCfg := relabelConfigs[0].cfg[0]
+	dropCfg := relabelConfigs[4].cfg[0]
+	kept := target.NewItem("kept-job", "kept-url", model.LabelSet{"i": "0"}, "")
+	dropped := target.NewItem("dropped-job", "dropped-url", model.LabelSet{"i": "1"}, "")
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{
+		"kept-job":    {keepCfg},
+		"dropped-job": {keepCfg, dropCfg},
+	})
+	remainingItems := allocatorPrehook.Apply(map[string]*target.Item{
+		kept.Hash():    kept,
+		dropped.Hash(): dropped,
+	})
+	assert.Len(t, remainingItems, 1)
+
+	droppedTargets := reporter.DroppedTargets()
+	assert.Len(t, droppedTargets, 1)
+	assert.Equal(t, dropped, droppedTargets[0].Item)
+	assert.Equal(t, 1, droppedTargets[0].RuleIndex)
+	assert.Equal(t, dropCfg, droppedTargets[0].Rule)
+}
+
+func TestDroppedTargetsClearedWithoutRelabelCfg(t *testing.T) {
+	allocatorPrehook := New("relabel-config", logger)
+	assert.NotNil(t, allocatorPrehook)
+	reporter := allocatorPrehook.(DropReporter)
+
+	dropped := target.NewItem("dropped-job", "dropped-url", model.LabelSet{"i": "1"}, "")
+	targets := map[string]*target.Item{dropped.Hash(): dropped}
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{
+		"dropped-job": {relabelConfigs[4].cfg[0]},
+	})
+	allocatorPrehook.Apply(targets)
+	assert.Len(t, reporter.DroppedTargets(), 1)
+
+	// once the relabel configs are removed nothing is dropped anymore
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{})
+	assert.Equal(t, targets, allocatorPrehook.Apply(targets))
+	assert.Empty(t, reporter.DroppedTargets())
+
+	// later changes can be recorded after the reset
+	allocatorPrehook.ApplyChanges(diff.NewChanges(targets, nil))
+	assert.Empty(t, reporter.DroppedTargets())
+}
+
+func TestApplyChanges(t *testing.T) {
+	allocatorPrehook := New("relabel-config", logger)
+	assert.NotNil(t, allocatorPrehook)
+	reporter := allocatorPrehook.(DropReporter)
+
+	keepCfg := relabelConfigs[0].cfg[0]
+	dropCfg := relabelConfigs[4].cfg[0]
+	kept := target.NewItem("kept-job", "kept-url", model.LabelSet{"i": "0"}, "")
+	dropped := target.NewItem("dropped-job", "dropped-url", model.LabelSet{"i": "1"}, "")
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{
+		"kept-job":    {keepCfg},
+		"dropped-job": {dropCfg},
+	})
+
+	changes := allocatorPrehook.ApplyChanges(diff.NewChanges(map[string]*target.Item{
+		kept.Hash():    kept,
+		dropped.Hash(): dropped,
+	}, nil))
+	assert.Equal(t, map[string]*target.Item{kept.Hash(): kept}, changes.Additions())
+	assert.Len(t, reporter.DroppedTargets(), 1)
+
+	// a later change keeps the targets dropped before it
+	other := target.NewItem("kept-job", "other-url", model.LabelSet{"i": "2"}, "")
+	allocatorPrehook.ApplyChanges(diff.NewChanges(map[string]*target.Item{other.Hash(): other}, nil))
+	assert.Len(t, reporter.DroppedTargets(), 1)
+
+	// removing the dropped target forgets it
+	changes = allocatorPrehook.ApplyChanges(diff.NewChanges(nil, map[string]*target.Item{dropped.Hash(): dropped}))
+	assert.Equal(t, map[string]*target.Item{dropped.Hash(): dropped}, changes.Removals())
+	assert.Empty(t, reporter.DroppedTargets())
+}
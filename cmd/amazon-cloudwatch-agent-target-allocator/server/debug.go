@@ -0,0 +1,200 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/common/model"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/prehook"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+const (
+	targetStateAllocated   = "allocated"
+	targetStateUnallocated = "unallocated"
+	targetStateDropped     = "dropped"
+)
+
+type collectorStatusJSON struct {
+	NumTargets int  `json:"num_targets"`
+	Ready      bool `json:"ready"`
+}
+
+type targetStatusJSON struct {
+	JobName       string           `json:"job_name"`
+	TargetURL     []string         `json:"targets"`
+	Labels        model.LabelSet   `json:"labels"`
+	State         string           `json:"state"`
+	CollectorName string           `json:"collector_name,omitempty"`
+	DroppedBy     *relabelRuleJSON `json:"dropped_by,omitempty"`
+	DropReason    string           `json:"drop_reason,omitempty"`
+}
+
+type relabelRuleJSON struct {
+	Index        int      `json:"index"`
+	Action       string   `json:"action"`
+	SourceLabels []string `json:"source_labels,omitempty"`
+	Regex        string   `json:"regex,omitempty"`
+	TargetLabel  string   `json:"target_label,omitempty"`
+}
+
+// WithDropReporter exposes the targets dropped by the prehook on the /targets endpoint.
+func WithDropReporter(reporter prehook.DropReporter) Option {
+	return func(s *Server) {
+		s.dropReporter = reporter
+	}
+}
+
+// CollectorsHandler lists every collector known to the allocator with its target count and readiness.
+func (s *Server) CollectorsHandler(c *gin.Context) {
+	displayData := make(map[string]collectorStatusJSON)
+	for name, col := range s.allocator.Collectors() {
+		displayData[name] = collectorStatusJSON{
+			NumTargets: col.NumTargets,
+			Ready:      col.Ready,
+		}
+	}
+	s.debugHandler(c, collectorsTemplate, displayData)
+}
+
+// TargetStatusHandler reports which collector owns the targets matching the url query parameter, or which
+// prehook rule dropped them.
+func (s *Server) TargetStatusHandler(c *gin.Context) {
+	targetURL := c.Query("url")
+	if targetURL == "" {
+		c.Writer.WriteHeader(http.StatusBadRequest)
+		s.jsonHandler(c.Writer, map[string]string{"error": "missing required query parameter: url"})
+		return
+	}
+
+	displayData := []targetStatusJSON{}
+	for _, item := range s.allocator.TargetItems() {
+		if !hasTargetURL(item, targetURL) {
+			continue
+		}
+		status := newTargetStatusJSON(item, targetStateAllocated)
+		if item.CollectorName == "" {
+			status.State = targetStateUnallocated
+		}
+		displayData = append(displayData, status)
+	}
+	if s.dropReporter != nil {
+		for _, dropped := range s.dropReporter.DroppedTargets() {
+			if !hasTargetURL(dropped.Item, targetURL) {
+				continue
+			}
+			status := newTargetStatusJSON(dropped.Item, targetStateDropped)
+			status.CollectorName = ""
+			if dropped.Rule != nil {
+				status.DroppedBy = newRelabelRuleJSON(dropped)
+			}
+			status.DropReason = dropped.Reason
+			displayData = append(displayData, status)
+		}
+	}
+	sort.Slice(displayData, func(i, j int) bool {
+		return displayData[i].JobName < displayData[j].JobName
+	})
+	s.debugHandler(c, targetsTemplate, displayData)
+}
+
+// AllocationHandler returns a snapshot of the allocator's hash ring.
+func (s *Server) AllocationHandler(c *gin.Context) {
+	s.debugHandler(c, allocationTemplate, s.allocator.Snapshot())
+}
+
+// debugHandler renders data as HTML for browsers and as JSON for every other client.
+func (s *Server) debugHandler(c *gin.Context, tmpl *template.Template, data interface{}) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		s.jsonHandler(c.Writer, data)
+		return
+	}
+	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(c.Writer, data); err != nil {
+		s.logger.Error(err, "failed to render template for http response")
+	}
+}
+
+func hasTargetURL(item *target.Item, targetURL string) bool {
+	for _, u := range item.TargetURL {
+		if u == targetURL {
+			return true
+		}
+	}
+	return false
+}
+
+func newTargetStatusJSON(item *target.Item, state string) targetStatusJSON {
+	return targetStatusJSON{
+		JobName:       item.JobName,
+		TargetURL:     item.TargetURL,
+		Labels:        item.Labels,
+		State:         state,
+		CollectorName: item.CollectorName,
+	}
+}
+
+func newRelabelRuleJSON(dropped prehook.DroppedTarget) *relabelRuleJSON {
+	rule := &relabelRuleJSON{Index: dropped.RuleIndex}
+	rule.Action = string(dropped.Rule.Action)
+	rule.TargetLabel = dropped.Rule.TargetLabel
+	if dropped.Rule.Regex.Regexp != nil {
+		rule.Regex = dropped.Rule.Regex.String()
+	}
+	for _, l := range dropped.Rule.SourceLabels {
+		rule.SourceLabels = append(rule.SourceLabels, string(l))
+	}
+	return rule
+}
+
+const debugPageHeader = `<!DOCTYPE html>
+<html><head><title>CloudWatch Agent Target Allocator</title>
+<style>body{font-family:sans-serif}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:4px 8px;text-align:left}</style>
+</head><body>
+<p><a href="/collectors">Collectors</a> | <a href="/jobs">Jobs</a> | <a href="/debug/allocation">Allocation</a></p>
+`
+
+const debugPageFooter = `</body></html>
+`
+
+var (
+	collectorsTemplate = template.Must(template.New("collectors").Parse(debugPageHeader + `<h1>Collectors</h1>
+<table><tr><th>Collector</th><th>Targets</th><th>Ready</th></tr>
+{{range $name, $col := .}}<tr><td>{{$name}}</td><td>{{$col.NumTargets}}</td><td>{{$col.Ready}}</td></tr>
+{{end}}</table>
+` + debugPageFooter))
+
+	targetsTemplate = template.Must(template.New("targets").Parse(debugPageHeader + `<h1>Targets</h1>
+<table><tr><th>Job</th><th>Targets</th><th>State</th><th>Collector</th><th>Dropped by</th><th>Labels</th></tr>
+{{range .}}<tr><td>{{.JobName}}</td><td>{{range .TargetURL}}{{.}} {{end}}</td><td>{{.State}}</td><td>{{.CollectorName}}</td>
+<td>{{with .DroppedBy}}relabel_configs[{{.Index}}] action={{.Action}} source_labels={{.SourceLabels}} regex={{.Regex}}{{end}}{{.DropReason}}</td>
+<td>{{range $k, $v := .Labels}}{{$k}}={{$v}}<br>{{end}}</td></tr>
+{{end}}</table>
+` + debugPageFooter))
+
+	allocationTemplate = template.Must(template.New("allocation").Parse(debugPageHeader + `<h1>Allocation</h1>
+<table>
+<tr><th>Strategy</th><td>{{.Strategy}}</td></tr>
+<tr><th>Partitions</th><td>{{.PartitionCount}}</td></tr>
+<tr><th>Replication factor</th><td>{{.ReplicationFactor}}</td></tr>
+<tr><th>Load</th><td>{{.Load}}</td></tr>
+<tr><th>Average load</th><td>{{.AverageLoad}}</td></tr>
+<tr><th>Targets</th><td>{{.NumTargets}}</td></tr>
+<tr><th>Draining</th><td>{{.NumDraining}}</td></tr>
+</table>
+<h2>Members</h2>
+<table><tr><th>Collector</th><th>Partitions</th><th>Targets</th><th>Ready</th></tr>
+{{range .Members}}<tr><td>{{.Name}}</td><td>{{.Partitions}}</td><td>{{.NumTargets}}</td><td>{{.Ready}}</td></tr>
+{{end}}</table>
+<h2>Partition owners</h2>
+<table><tr><th>Partition</th><th>Collector</th></tr>
+{{range $id, $owner := .PartitionOwners}}<tr><td>{{$id}}</td><td>{{$owner}}</td></tr>
+{{end}}</table>
+` + debugPageFooter))
+)
@@ -0,0 +1,195 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/prehook"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+type mockDropReporter struct {
+	dropped []prehook.DroppedTarget
+}
+
+func (m *mockDropReporter) DroppedTargets() []prehook.DroppedTarget {
+	return m.dropped
+}
+
+func TestServer_CollectorsHandler(t *testing.T) {
+	consistentHashing, _ := allocation.New("consistent-hashing", logger)
+	consistentHashing.SetCollectors(map[string]*allocation.Collector{
+		"collector-0": {Name: "collector-0", Ready: true},
+		"collector-1": {Name: "collector-1"},
+	})
+	consistentHashing.SetTargets(map[string]*target.Item{baseTargetItem.Hash(): baseTargetItem})
+	s := NewServer(logger, consistentHashing, ":8080")
+
+	request := httptest.NewRequest("GET", "/collectors", nil)
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, request)
+	result := w.Result()
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	body := result.Body
+	bodyBytes, err := io.ReadAll(body)
+	require.NoError(t, err)
+	var actual map[string]collectorStatusJSON
+	require.NoError(t, json.Unmarshal(bodyBytes, &actual))
+	assert.Len(t, actual, 2)
+	assert.True(t, actual["collector-0"].Ready)
+	assert.False(t, actual["collector-1"].Ready)
+	assert.Equal(t, 1, actual["collector-0"].NumTargets+actual["collector-1"].NumTargets)
+}
+
+func TestServer_TargetStatusHandler(t *testing.T) {
+	dropRule := &relabel.Config{
+		SourceLabels: model.LabelNames{"test_label"},
+		Regex:        relabel.MustNewRegexp("test-value2"),
+		Action:       relabel.Drop,
+	}
+	droppedItem := target.NewItem("dropped-job", "test-url", testJobLabelSetTwo, "")
+	unhealthyItem := target.NewItem("unhealthy-job", "unhealthy-url", testJobLabelSetTwo, "")
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		want       []targetStatusJSON
+	}{
+		{
+			name:       "missing url",
+			url:        "/targets",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no match",
+			url:        "/targets?url=unknown-url",
+			wantStatus: http.StatusOK,
+			want:       []targetStatusJSON{},
+		},
+		{
+			name:       "allocated and dropped",
+			url:        "/targets?url=test-url",
+			wantStatus: http.StatusOK,
+			want: []targetStatusJSON{
+				{
+					JobName:   "dropped-job",
+					TargetURL: []string{"test-url"},
+					Labels:    testJobLabelSetTwo,
+					State:     targetStateDropped,
+					DroppedBy: &relabelRuleJSON{
+						Index:        1,
+						Action:       "drop",
+						SourceLabels: []string{"test_label"},
+						Regex:        "test-value2",
+					},
+				},
+				{
+					JobName:       "test-job",
+					TargetURL:     []string{"test-url"},
+					Labels:        baseLabelSet,
+					State:         targetStateAllocated,
+					CollectorName: "collector-0",
+				},
+			},
+		},
+		{
+			name:       "dropped without rule",
+			url:        "/targets?url=unhealthy-url",
+			wantStatus: http.StatusOK,
+			want: []targetStatusJSON{
+				{
+					JobName:    "unhealthy-job",
+					TargetURL:  []string{"unhealthy-url"},
+					Labels:     testJobLabelSetTwo,
+					State:      targetStateDropped,
+					DropReason: "pod is not ready",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consistentHashing, _ := allocation.New("consistent-hashing", logger)
+			consistentHashing.SetCollectors(map[string]*allocation.Collector{"collector-0": {Name: "collector-0"}})
+			item := target.NewItem("test-job", "test-url", baseLabelSet, "")
+			consistentHashing.SetTargets(map[string]*target.Item{item.Hash(): item})
+			reporter := &mockDropReporter{dropped: []prehook.DroppedTarget{
+				{Item: droppedItem, RuleIndex: 1, Rule: dropRule},
+				{Item: unhealthyItem, Reason: "pod is not ready"},
+			}}
+			s := NewServer(logger, consistentHashing, ":8080", WithDropReporter(reporter))
+
+			request := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			s.server.Handler.ServeHTTP(w, request)
+			result := w.Result()
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+			if tt.want == nil {
+				return
+			}
+			bodyBytes, err := io.ReadAll(result.Body)
+			require.NoError(t, err)
+			var actual []targetStatusJSON
+			require.NoError(t, json.Unmarshal(bodyBytes, &actual))
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func TestServer_AllocationHandler(t *testing.T) {
+	consistentHashing, _ := allocation.New("consistent-hashing", logger)
+	consistentHashing.SetCollectors(allocation.MakeNCollectors(3, 0))
+	consistentHashing.SetTargets(allocation.MakeNNewTargetsWithEmptyCollectors(10, 0))
+	s := NewServer(logger, consistentHashing, ":8080")
+
+	request := httptest.NewRequest("GET", "/debug/allocation", nil)
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, request)
+	result := w.Result()
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "application/json", result.Header.Get("Content-Type"))
+
+	bodyBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	var actual allocation.Snapshot
+	require.NoError(t, json.Unmarshal(bodyBytes, &actual))
+	assert.Equal(t, "consistent-hashing", actual.Strategy)
+	assert.Equal(t, 10, actual.NumTargets)
+	assert.Len(t, actual.Members, 3)
+	assert.Len(t, actual.PartitionOwners, actual.PartitionCount)
+}
+
+func TestServer_DebugHandlerHTML(t *testing.T) {
+	consistentHashing, _ := allocation.New("consistent-hashing", logger)
+	consistentHashing.SetCollectors(allocation.MakeNCollectors(1, 0))
+	consistentHashing.SetTargets(map[string]*target.Item{baseTargetItem.Hash(): baseTargetItem})
+	s := NewServer(logger, consistentHashing, ":8080")
+
+	for _, path := range []string{"/collectors", "/targets?url=test-url", "/debug/allocation"} {
+		request := httptest.NewRequest("GET", path, nil)
+		request.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, request)
+		result := w.Result()
+		assert.Equal(t, http.StatusOK, result.StatusCode, path)
+		assert.True(t, strings.HasPrefix(result.Header.Get("Content-Type"), "text/html"), path)
+		bodyBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(bodyBytes), "collector-0", path)
+	}
+}
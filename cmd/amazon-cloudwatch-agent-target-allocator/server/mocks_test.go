@@ -5,6 +5,7 @@ package server
 
 import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
@@ -18,9 +19,11 @@ type mockAllocator struct {
 
 func (m *mockAllocator) SetCollectors(_ map[string]*allocation.Collector)               {}
 func (m *mockAllocator) SetTargets(_ map[string]*target.Item)                           {}
+func (m *mockAllocator) UpdateTargets(_ diff.Changes[*target.Item])                     {}
 func (m *mockAllocator) Collectors() map[string]*allocation.Collector                   { return nil }
 func (m *mockAllocator) GetTargetsForCollectorAndJob(_ string, _ string) []*target.Item { return nil }
 func (m *mockAllocator) SetFilter(_ allocation.Filter)                                  {}
+func (m *mockAllocator) Snapshot() allocation.Snapshot                                  { return allocation.Snapshot{} }
 
 func (m *mockAllocator) TargetItems() map[string]*target.Item {
 	return m.targetItems
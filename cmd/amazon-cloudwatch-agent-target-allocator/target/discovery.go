@@ -4,8 +4,10 @@
 package target
 
 import (
+	"fmt"
 	"hash"
 	"hash/fnv"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,9 +15,11 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/model/relabel"
 	"gopkg.in/yaml.v3"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	allocatorWatcher "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/watcher"
 )
 
@@ -34,12 +38,48 @@ type Discoverer struct {
 	hook                 discoveryHook
 	scrapeConfigsHash    hash.Hash
 	scrapeConfigsUpdater scrapeConfigsUpdater
+
+	// targetSets indexes the targets last reported by job, so that every sync only reports what changed.
+	// Only Watch uses it.
+	// job name -> targets
+	targetSets map[string]*jobTargets
+	// fullSync is set when the scrape configs change, since the relabel configs used to filter targets may have
+	// changed too. The next sync then reports every target instead of the changes.
+	fullSync atomic.Bool
+}
+
+// jobTargets holds the targets of a single job along with the target groups they came from.
+type jobTargets struct {
+	// group key -> group
+	groups map[string]*groupTargets
+	// target item hash -> target item
+	items map[string]*Item
+	// refs counts the groups containing each target, since a target may be in several groups.
+	// target item hash -> number of groups
+	refs map[string]int
+}
+
+// groupTargets remembers a target group by its fingerprint so an unchanged group is skipped without building items.
+type groupTargets struct {
+	fingerprint uint64
+	// target item hash -> true
+	hashes map[string]bool
+}
+
+type targetsUpdater interface {
+	SetTargets(map[string]*Item)
+	UpdateTargets(diff.Changes[*Item])
 }
 
 type discoveryHook interface {
 	SetConfig(map[string][]*relabel.Config)
 }
 
+// targetLimitHook is implemented by hooks enforcing the target_limit of each job.
+type targetLimitHook interface {
+	SetTargetLimits(map[string]uint)
+}
+
 type scrapeConfigsUpdater interface {
 	UpdateScrapeConfigResponse(map[string]*config.ScrapeConfig) error
 }
@@ -52,6 +92,7 @@ func NewDiscoverer(log logr.Logger, manager *discovery.Manager, hook discoveryHo
 		configsMap:           make(map[allocatorWatcher.EventSource]*config.Config),
 		hook:                 hook,
 		scrapeConfigsUpdater: scrapeConfigsUpdater,
+		targetSets:           make(map[string]*jobTargets),
 	}
 }
 
@@ -65,12 +106,14 @@ func (m *Discoverer) ApplyConfig(source allocatorWatcher.EventSource, cfg *confi
 
 	discoveryCfg := make(map[string]discovery.Configs)
 	relabelCfg := make(map[string][]*relabel.Config)
+	targetLimits := make(map[string]uint)
 
 	for _, value := range m.configsMap {
 		for _, scrapeConfig := range value.ScrapeConfigs {
 			jobToScrapeConfig[scrapeConfig.JobName] = scrapeConfig
 			discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
 			relabelCfg[scrapeConfig.JobName] = scrapeConfig.RelabelConfigs
+			targetLimits[scrapeConfig.JobName] = scrapeConfig.TargetLimit
 		}
 	}
 
@@ -91,33 +134,169 @@ func (m *Discoverer) ApplyConfig(source allocatorWatcher.EventSource, cfg *confi
 
 	if m.hook != nil {
 		m.hook.SetConfig(relabelCfg)
+		if limitHook, ok := m.hook.(targetLimitHook); ok {
+			limitHook.SetTargetLimits(targetLimits)
+		}
 	}
+	m.fullSync.Store(true)
 	return m.manager.ApplyConfig(discoveryCfg)
 }
 
-func (m *Discoverer) Watch(fn func(targets map[string]*Item)) error {
+// Watch reports the targets found by service discovery to updater. Each sync reports only the targets added and
+// removed since the previous one, except after a configuration change, when every target is set again.
+func (m *Discoverer) Watch(updater targetsUpdater) error {
 	for {
 		select {
 		case <-m.close:
 			m.log.Info("Service Discovery watch event stopped: discovery manager closed")
 			return nil
 		case tsets := <-m.manager.SyncCh():
-			targets := map[string]*Item{}
-
-			for jobName, tgs := range tsets {
-				var count float64 = 0
-				for _, tg := range tgs {
-					for _, t := range tg.Targets {
-						count++
-						item := NewItem(jobName, string(t[model.AddressLabel]), t.Merge(tg.Labels), "")
-						targets[item.Hash()] = item
-					}
-				}
-				targetsDiscovered.WithLabelValues(jobName).Set(count)
+			changes := m.updateTargetSets(tsets)
+			if m.fullSync.Swap(false) {
+				updater.SetTargets(m.targets())
+			} else if len(changes.Additions()) > 0 || len(changes.Removals()) > 0 {
+				updater.UpdateTargets(changes)
+			}
+		}
+	}
+}
+
+// updateTargetSets applies a sync to the per-job index and returns the targets added and removed.
+func (m *Discoverer) updateTargetSets(tsets map[string][]*targetgroup.Group) diff.Changes[*Item] {
+	added := map[string]*Item{}
+	removed := map[string]*Item{}
+	for jobName, set := range m.targetSets {
+		if _, ok := tsets[jobName]; ok {
+			continue
+		}
+		for k, item := range set.items {
+			removed[k] = item
+		}
+		delete(m.targetSets, jobName)
+		targetsDiscovered.WithLabelValues(jobName).Set(0)
+	}
+	for jobName, tgs := range tsets {
+		set, ok := m.targetSets[jobName]
+		if !ok {
+			set = &jobTargets{
+				groups: make(map[string]*groupTargets),
+				items:  make(map[string]*Item),
+				refs:   make(map[string]int),
+			}
+			m.targetSets[jobName] = set
+		}
+		count := set.update(jobName, tgs, added, removed)
+		targetsDiscovered.WithLabelValues(jobName).Set(count)
+	}
+	return diff.NewChanges(added, removed)
+}
+
+// targets returns every target in the index.
+func (m *Discoverer) targets() map[string]*Item {
+	targets := map[string]*Item{}
+	for _, set := range m.targetSets {
+		for k, item := range set.items {
+			targets[k] = item
+		}
+	}
+	return targets
+}
+
+// update replaces the job's target groups with tgs, recording the targets added to and removed from the job, and
+// returns the number of targets discovered for it. Groups whose fingerprint did not change are skipped.
+func (s *jobTargets) update(jobName string, tgs []*targetgroup.Group, added, removed map[string]*Item) float64 {
+	var count float64
+	seen := make(map[string]bool, len(tgs))
+	for _, tg := range tgs {
+		count += float64(len(tg.Targets))
+		// Sources are only unique per discovery provider, and a job may have several.
+		key := tg.Source
+		for i := 1; seen[key]; i++ {
+			key = fmt.Sprintf("%s#%d", tg.Source, i)
+		}
+		seen[key] = true
+
+		fingerprint := groupFingerprint(tg)
+		group, ok := s.groups[key]
+		if ok && group.fingerprint == fingerprint {
+			continue
+		}
+		if !ok {
+			group = &groupTargets{}
+			s.groups[key] = group
+		}
+		hashes := make(map[string]bool, len(tg.Targets))
+		for _, t := range tg.Targets {
+			item := NewItem(jobName, string(t[model.AddressLabel]), t.Merge(tg.Labels), "")
+			if hashes[item.Hash()] {
+				continue
+			}
+			hashes[item.Hash()] = true
+			if !group.hashes[item.Hash()] {
+				s.acquire(item, added, removed)
+			}
+		}
+		for k := range group.hashes {
+			if !hashes[k] {
+				s.release(k, added, removed)
 			}
-			fn(targets)
 		}
+		group.fingerprint = fingerprint
+		group.hashes = hashes
+	}
+	for key, group := range s.groups {
+		if seen[key] {
+			continue
+		}
+		for k := range group.hashes {
+			s.release(k, added, removed)
+		}
+		delete(s.groups, key)
+	}
+	return count
+}
+
+// acquire references the item from one more group, adding it to the job when it is the first.
+func (s *jobTargets) acquire(item *Item, added, removed map[string]*Item) {
+	k := item.Hash()
+	s.refs[k]++
+	if s.refs[k] > 1 {
+		return
+	}
+	// A target moving between groups within one sync is neither added nor removed.
+	if previous, ok := removed[k]; ok {
+		delete(removed, k)
+		s.items[k] = previous
+		return
+	}
+	s.items[k] = item
+	added[k] = item
+}
+
+// release drops a group's reference to the target, removing it from the job when it was the last.
+func (s *jobTargets) release(k string, added, removed map[string]*Item) {
+	s.refs[k]--
+	if s.refs[k] > 0 {
+		return
+	}
+	item := s.items[k]
+	delete(s.refs, k)
+	delete(s.items, k)
+	if _, ok := added[k]; ok {
+		delete(added, k)
+		return
+	}
+	removed[k] = item
+}
+
+// groupFingerprint combines the fingerprints of the group's labels and targets, in order.
+func groupFingerprint(tg *targetgroup.Group) uint64 {
+	const prime64 = 1099511628211
+	fingerprint := uint64(tg.Labels.Fingerprint())
+	for _, t := range tg.Targets {
+		fingerprint = (fingerprint ^ uint64(t.Fingerprint())) * prime64
 	}
+	return fingerprint
 }
 
 func (m *Discoverer) Close() {
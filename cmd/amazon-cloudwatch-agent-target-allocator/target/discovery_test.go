@@ -6,6 +6,7 @@ package target
 import (
 	"context"
 	"errors"
+	"fmt"
 	"hash"
 	"log/slog"
 	"sort"
@@ -17,12 +18,14 @@ import (
 	"github.com/prometheus/common/model"
 	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
 	allocatorWatcher "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/watcher"
 )
 
@@ -67,13 +70,13 @@ func TestDiscovery(t *testing.T) {
 		assert.Error(t, err)
 	}()
 	go func() {
-		err := manager.Watch(func(targets map[string]*Item) {
+		err := manager.Watch(&mockTargetsUpdater{fn: func(targets map[string]*Item) {
 			var result []string
 			for _, t := range targets {
 				result = append(result, t.TargetURL[0])
 			}
 			results <- result
-		})
+		}})
 		assert.NoError(t, err)
 	}()
 	for _, tt := range tests {
@@ -413,3 +416,129 @@ func (m *mockScrapeConfigUpdater) UpdateScrapeConfigResponse(cfg map[string]*pro
 	m.mockCfg = cfg
 	return nil
 }
+
+var _ targetsUpdater = &mockTargetsUpdater{}
+
+// mockTargetsUpdater applies the updates it receives to its own set of targets and passes the result to fn.
+type mockTargetsUpdater struct {
+	targets map[string]*Item
+	fn      func(map[string]*Item)
+}
+
+func (m *mockTargetsUpdater) SetTargets(targets map[string]*Item) {
+	m.targets = targets
+	m.fn(m.targets)
+}
+
+func (m *mockTargetsUpdater) UpdateTargets(changes diff.Changes[*Item]) {
+	for k := range changes.Removals() {
+		delete(m.targets, k)
+	}
+	for k, item := range changes.Additions() {
+		m.targets[k] = item
+	}
+	m.fn(m.targets)
+}
+
+func targetGroup(source string, addresses ...string) *targetgroup.Group {
+	tg := &targetgroup.Group{Source: source, Labels: model.LabelSet{"group": model.LabelValue(source)}}
+	for _, address := range addresses {
+		tg.Targets = append(tg.Targets, model.LabelSet{model.AddressLabel: model.LabelValue(address)})
+	}
+	return tg
+}
+
+func targetURLs(items map[string]*Item) []string {
+	var urls []string
+	for _, item := range items {
+		urls = append(urls, item.TargetURL[0])
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func TestUpdateTargetSets(t *testing.T) {
+	manager := NewDiscoverer(ctrl.Log.WithName("test"), nil, nil, nil)
+
+	changes := manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job-a": {targetGroup("a", "a:1", "a:2"), targetGroup("b", "b:1")},
+		"job-b": {targetGroup("a", "c:1")},
+	})
+	assert.Equal(t, []string{"a:1", "a:2", "b:1", "c:1"}, targetURLs(changes.Additions()))
+	assert.Empty(t, changes.Removals())
+
+	// unchanged groups report nothing
+	changes = manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job-a": {targetGroup("a", "a:1", "a:2"), targetGroup("b", "b:1")},
+		"job-b": {targetGroup("a", "c:1")},
+	})
+	assert.Empty(t, changes.Additions())
+	assert.Empty(t, changes.Removals())
+
+	// only the changed group and the removed job are reported
+	changes = manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job-a": {targetGroup("a", "a:1", "a:3"), targetGroup("b", "b:1")},
+	})
+	assert.Equal(t, []string{"a:3"}, targetURLs(changes.Additions()))
+	assert.Equal(t, []string{"a:2", "c:1"}, targetURLs(changes.Removals()))
+	assert.Equal(t, []string{"a:1", "a:3", "b:1"}, targetURLs(manager.targets()))
+}
+
+func TestUpdateTargetSetsSharedTargets(t *testing.T) {
+	manager := NewDiscoverer(ctrl.Log.WithName("test"), nil, nil, nil)
+	shared := func(source string, addresses ...string) *targetgroup.Group {
+		tg := targetGroup(source, addresses...)
+		tg.Labels = nil
+		return tg
+	}
+
+	changes := manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job": {shared("a", "x:1"), shared("b", "x:1")},
+	})
+	assert.Equal(t, []string{"x:1"}, targetURLs(changes.Additions()))
+
+	// the target is still in group b
+	changes = manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job": {shared("a"), shared("b", "x:1")},
+	})
+	assert.Empty(t, changes.Additions())
+	assert.Empty(t, changes.Removals())
+
+	// the target moves from group b to group c
+	changes = manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job": {shared("a"), shared("b"), shared("c", "x:1")},
+	})
+	assert.Empty(t, changes.Additions())
+	assert.Empty(t, changes.Removals())
+
+	changes = manager.updateTargetSets(map[string][]*targetgroup.Group{
+		"job": {shared("c")},
+	})
+	assert.Empty(t, changes.Additions())
+	assert.Equal(t, []string{"x:1"}, targetURLs(changes.Removals()))
+	assert.Empty(t, manager.targets())
+}
+
+func BenchmarkUpdateTargetSets(b *testing.B) {
+	groupSize := 100
+	for _, numTargets := range []int{10_000, 100_000, 500_000} {
+		manager := NewDiscoverer(ctrl.Log.WithName("test"), nil, nil, nil)
+		groups := make([]*targetgroup.Group, 0, numTargets/groupSize)
+		for i := 0; i < numTargets/groupSize; i++ {
+			addresses := make([]string, groupSize)
+			for j := range addresses {
+				addresses[j] = fmt.Sprintf("10.0.%d.%d:8080", i, j)
+			}
+			groups = append(groups, targetGroup(fmt.Sprintf("pod/%d", i), addresses...))
+		}
+		manager.updateTargetSets(map[string][]*targetgroup.Group{"job": groups})
+		b.Run(fmt.Sprintf("num_targets_%d", numTargets), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				// a single group changes between syncs
+				groups[0] = targetGroup("pod/0", fmt.Sprintf("10.1.0.%d:8080", i%2))
+				manager.updateTargetSets(map[string][]*targetgroup.Group{"job": groups})
+			}
+		})
+	}
+}
@@ -42,3 +42,11 @@ func NewItem(jobName string, targetURL string, label model.LabelSet, collectorNa
 		CollectorName: collectorName,
 	}
 }
+
+// WithCollector returns a copy of the item assigned to collectorName. Items handed out to readers must not be
+// modified, so reassigning an item creates a new one.
+func (t *Item) WithCollector(collectorName string) *Item {
+	item := *t
+	item.CollectorName = collectorName
+	return &item
+}
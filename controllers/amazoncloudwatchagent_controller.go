@@ -6,41 +6,70 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/certificates"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/configsource"
+	kedav1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/internal/keda/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 	collectorStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
 )
 
+// configSecretRequeueInterval is how often the instances reading their configuration from a Secret are reconciled,
+// since the Secrets the operator doesn't manage aren't watched.
+const configSecretRequeueInterval = 5 * time.Minute
+
 // AmazonCloudWatchAgentReconciler reconciles a AmazonCloudWatchAgent object.
 type AmazonCloudWatchAgentReconciler struct {
 	client.Client
-	recorder record.EventRecorder
-	scheme   *runtime.Scheme
-	log      logr.Logger
-	config   config.Config
+	// apiReader reads the Secrets referenced by spec.configFrom and spec.otelConfigFrom, and the pods of the
+	// collectors, which aren't cached.
+	apiReader client.Reader
+	recorder  record.EventRecorder
+	scheme    *runtime.Scheme
+	log       logr.Logger
+	config    config.Config
+	// healthCheck probes the health endpoint of the canary pods.
+	healthCheck healthChecker
+	// certificates provisions the TLS certificates of the target allocator when the operator manages them.
+	certificates *certificates.Manager
 }
 
 // Params is the set of options to build a new AmazonCloudWatchAgentReconciler.
 type Params struct {
 	client.Client
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
-	Log      logr.Logger
-	Config   config.Config
+	// APIReader reads the objects the manager doesn't cache, defaulting to the client except for the
+	// SidecarReconciler.
+	APIReader client.Reader
+	Recorder  record.EventRecorder
+	Scheme    *runtime.Scheme
+	Log       logr.Logger
+	Config    config.Config
 }
 
 func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx context.Context, owner v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error) {
@@ -53,11 +82,14 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 	}
 	// Define lists for different Kubernetes resources
 	configMapList := &corev1.ConfigMapList{}
+	secretList := &corev1.SecretList{}
 	serviceList := &corev1.ServiceList{}
 	serviceAccountList := &corev1.ServiceAccountList{}
 	deploymentList := &appsv1.DeploymentList{}
 	statefulSetList := &appsv1.StatefulSetList{}
 	daemonSetList := &appsv1.DaemonSetList{}
+	podDisruptionBudgetList := &policyV1.PodDisruptionBudgetList{}
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
 	var err error
 
 	// List ConfigMaps
@@ -69,6 +101,15 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 		ownedObjects[configMapList.Items[i].GetUID()] = &configMapList.Items[i]
 	}
 
+	// List Secrets
+	err = r.List(ctx, secretList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secretList.Items {
+		ownedObjects[secretList.Items[i].GetUID()] = &secretList.Items[i]
+	}
+
 	// List Services
 	err = r.List(ctx, serviceList, listOps)
 	if err != nil {
@@ -113,6 +154,72 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 		ownedObjects[daemonSetList.Items[i].GetUID()] = &daemonSetList.Items[i]
 	}
 
+	// List PodDisruptionBudgets
+	err = r.List(ctx, podDisruptionBudgetList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range podDisruptionBudgetList.Items {
+		ownedObjects[podDisruptionBudgetList.Items[i].GetUID()] = &podDisruptionBudgetList.Items[i]
+	}
+
+	// List HorizontalPodAutoscalers
+	err = r.List(ctx, hpaList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hpaList.Items {
+		// the HorizontalPodAutoscaler of a ScaledObject carries the labels of the ScaledObject but is managed by KEDA
+		if !metav1.IsControlledBy(&hpaList.Items[i], &owner) {
+			continue
+		}
+		ownedObjects[hpaList.Items[i].GetUID()] = &hpaList.Items[i]
+	}
+
+	if r.config.KEDAAvailable() {
+		// List ScaledObjects
+		scaledObjectList := &kedav1alpha1.ScaledObjectList{}
+		err = r.List(ctx, scaledObjectList, listOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range scaledObjectList.Items {
+			ownedObjects[scaledObjectList.Items[i].GetUID()] = &scaledObjectList.Items[i]
+		}
+	}
+
+	if r.config.GatewayAPIAvailable() {
+		// List HTTPRoutes
+		httpRouteList := &gatewayv1.HTTPRouteList{}
+		err = r.List(ctx, httpRouteList, listOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range httpRouteList.Items {
+			ownedObjects[httpRouteList.Items[i].GetUID()] = &httpRouteList.Items[i]
+		}
+
+		// List GRPCRoutes
+		grpcRouteList := &gatewayv1.GRPCRouteList{}
+		err = r.List(ctx, grpcRouteList, listOps)
+		if err != nil {
+			return nil, err
+		}
+		for i := range grpcRouteList.Items {
+			ownedObjects[grpcRouteList.Items[i].GetUID()] = &grpcRouteList.Items[i]
+		}
+
+		// List BackendTLSPolicies, which are not served by every Gateway API installation
+		backendTLSPolicyList := &gatewayv1.BackendTLSPolicyList{}
+		err = r.List(ctx, backendTLSPolicyList, listOps)
+		if err != nil && !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+		for i := range backendTLSPolicyList.Items {
+			ownedObjects[backendTLSPolicyList.Items[i].GetUID()] = &backendTLSPolicyList.Items[i]
+		}
+	}
+
 	return ownedObjects, nil
 
 }
@@ -129,18 +236,28 @@ func (r *AmazonCloudWatchAgentReconciler) getParams(instance v1alpha1.AmazonClou
 
 // NewReconciler creates a new reconciler for AmazonCloudWatchAgent objects.
 func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
+	apiReader := p.APIReader
+	if apiReader == nil {
+		apiReader = p.Client
+	}
 	r := &AmazonCloudWatchAgentReconciler{
-		Client:   p.Client,
-		log:      p.Log,
-		scheme:   p.Scheme,
-		config:   p.Config,
-		recorder: p.Recorder,
+		Client:    p.Client,
+		apiReader: apiReader,
+		log:       p.Log,
+		scheme:    p.Scheme,
+		config:    p.Config,
+		recorder:  p.Recorder,
+
+		healthCheck:  httpHealthCheck,
+		certificates: certificates.NewManager(p.Client, apiReader, p.Log.WithName("certificates")),
 	}
 	return r
 }
 
 // +kubebuilder:rbac:groups="",resources=pods;configmaps;services;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
@@ -148,6 +265,8 @@ func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;grpcroutes;backendtlspolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/finalizers,verbs=get;update;patch
@@ -178,27 +297,144 @@ func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, nil
 	}
 
+	// the configurations read through configFrom and otelConfigFrom are inlined, so the manifests are built from the
+	// content of the referenced ConfigMaps and Secrets. Only the Secrets managed by the operator are cached, so the
+	// referenced ones are read from the API server.
+	if resolveErr := configsource.Resolve(ctx, r.apiReader, &instance); resolveErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, r.getParams(instance), resolveErr)
+	}
+
 	params := r.getParams(instance)
+	extraConfigMaps, extraErr := r.getExtraConfigMaps(ctx, instance)
+	if extraErr != nil {
+		return ctrl.Result{}, extraErr
+	}
+	params.ExtraConfigMaps = extraConfigMaps
 
 	desiredObjects, buildErr := BuildCollector(params)
 	if buildErr != nil {
 		return ctrl.Result{}, buildErr
 	}
 
+	desiredObjects, canaryRunning, rolloutErr := r.reconcileRollout(ctx, log, &params, desiredObjects)
+	if rolloutErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, rolloutErr)
+	}
+
+	certRenewal, certErr := r.reconcileTargetAllocatorCertificates(ctx, params.OtelCol)
+	if certErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, certErr)
+	}
+
+	recreating, recreateErr := r.reconcileStatefulSetRecreate(ctx, log, &params, desiredObjects)
+	if recreateErr != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, recreateErr)
+	}
+	if recreating {
+		// the objects are reconciled once the orphaned StatefulSet is gone, so that its replacement can be created
+		result, err := collectorStatus.HandleReconcileStatus(ctx, log, params, nil)
+		if err == nil {
+			result.RequeueAfter = statefulSetRecreateRequeueInterval
+		}
+		return result, err
+	}
+
 	err := reconcileDesiredObjectsWPrune(ctx, r.Client, log, params.OtelCol, params.Scheme, desiredObjects, r.findCloudWatchAgentOwnedObjects)
-	return collectorStatus.HandleReconcileStatus(ctx, log, params, err)
+	result, err := collectorStatus.HandleReconcileStatus(ctx, log, params, err)
+	if err == nil && canaryRunning {
+		// the canary health is checked again after a while, since the health endpoint doesn't trigger any event
+		result.RequeueAfter = canaryRequeueInterval
+	}
+	if err == nil && collector.ConfigInSecret(instance) && (result.RequeueAfter == 0 || configSecretRequeueInterval < result.RequeueAfter) {
+		// the referenced Secrets aren't watched, so their changes are picked up periodically
+		result.RequeueAfter = configSecretRequeueInterval
+	}
+	if err == nil && !certRenewal.IsZero() {
+		// the certificates are renewed on the reconciliation following their renewal time
+		if untilRenewal := time.Until(certRenewal); result.RequeueAfter == 0 || untilRenewal < result.RequeueAfter {
+			result.RequeueAfter = max(untilRenewal, time.Second)
+		}
+	}
+	return result, err
+}
+
+// reconcileTargetAllocatorCertificates provisions the TLS certificates of the target allocator of the instance when
+// the operator manages them, returning when they are due for renewal.
+func (r *AmazonCloudWatchAgentReconciler) reconcileTargetAllocatorCertificates(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent) (time.Time, error) {
+	if !r.config.ManageTargetAllocatorCertificates() || !instance.Spec.TargetAllocator.Enabled {
+		return time.Time{}, nil
+	}
+	service := naming.TAService(instance.Name)
+	return r.certificates.Reconcile(ctx, instance.Namespace, []string{
+		service,
+		fmt.Sprintf("%s.%s", service, instance.Namespace),
+		fmt.Sprintf("%s.%s.svc", service, instance.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, instance.Namespace),
+	})
 }
 
 // SetupWithManager tells the manager what our controller is interested in.
 func (r *AmazonCloudWatchAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	builder := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.AmazonCloudWatchAgent{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{}).
-		Owns(&appsv1.StatefulSet{})
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&policyV1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.requestsForExtraConfigMap))
+
+	if r.config.KEDAAvailable() {
+		b = b.Owns(&kedav1alpha1.ScaledObject{})
+	}
+	if r.config.GatewayAPIAvailable() {
+		b = b.Owns(&gatewayv1.HTTPRoute{}).
+			Owns(&gatewayv1.GRPCRoute{})
+	}
+
+	return b.Complete(tracing.NewReconciler("amazoncloudwatchagent", r))
+}
 
-	return builder.Complete(r)
+// getExtraConfigMaps gets the config maps of spec.configmaps, skipping those that don't exist yet.
+func (r *AmazonCloudWatchAgentReconciler) getExtraConfigMaps(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent) ([]corev1.ConfigMap, error) {
+	var configMaps []corev1.ConfigMap
+	for _, spec := range instance.Spec.ConfigMaps {
+		cm := corev1.ConfigMap{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: spec.Name}, &cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get config map %s: %w", spec.Name, err)
+		}
+		configMaps = append(configMaps, cm)
+	}
+	return configMaps, nil
+}
+
+// requestsForExtraConfigMap enqueues the AmazonCloudWatchAgent resources mounting the config map through
+// spec.configmaps or reading their configuration from it, so that their pods are restarted when it changes.
+func (r *AmazonCloudWatchAgentReconciler) requestsForExtraConfigMap(ctx context.Context, cm client.Object) []reconcile.Request {
+	var list v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &list, client.InNamespace(cm.GetNamespace())); err != nil {
+		r.log.Error(err, "unable to list AmazonCloudWatchAgent resources", "ConfigMap", client.ObjectKeyFromObject(cm))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		if configsource.ReferencesConfigMap(item, cm.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+			continue
+		}
+		for _, spec := range item.Spec.ConfigMaps {
+			if spec.Name == cm.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+				break
+			}
+		}
+	}
+	return requests
 }
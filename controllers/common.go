@@ -20,6 +20,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/configsource"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
@@ -28,8 +30,6 @@ import (
 
 const (
 	acceleratedComputeMetrics = "accelerated_compute_metrics"
-	amazonCloudWatchNamespace = "amazon-cloudwatch"
-	amazonCloudWatchAgentName = "cloudwatch-agent"
 )
 
 func isNamespaceScoped(obj client.Object) bool {
@@ -162,8 +162,8 @@ func pruneStaleObjects(ctx context.Context, kubeClient client.Client, logger log
 	return errors.Join(pruneErrs...)
 }
 
-func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client, log logr.Logger) bool {
-	agentResource := getAmazonCloudWatchAgentResource(ctx, c)
+func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client, log logr.Logger, agentKey client.ObjectKey) bool {
+	agentResource := getAmazonCloudWatchAgentResource(ctx, c, agentKey)
 	// missing feature flag means it's on by default
 	featureConfigExists := strings.Contains(agentResource.Spec.Config, acceleratedComputeMetrics)
 	conf, err := adapters.ConfigStructFromJSONString(agentResource.Spec.Config)
@@ -183,13 +183,19 @@ func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client
 	return false
 }
 
-var getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client) v1alpha1.AmazonCloudWatchAgent {
+var getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client, agentKey client.ObjectKey) v1alpha1.AmazonCloudWatchAgent {
 	cr := &v1alpha1.AmazonCloudWatchAgent{}
 
-	_ = c.Get(ctx, client.ObjectKey{
-		Namespace: amazonCloudWatchNamespace,
-		Name:      amazonCloudWatchAgentName,
-	}, cr)
+	if err := c.Get(ctx, agentKey, cr); err == nil {
+		// the generated configuration Secret uses the default entries
+		_ = configsource.ResolveGenerated(ctx, c, config.New(), cr)
+	}
 
 	return *cr
 }
+
+// agentReferenceKey returns the key of the AmazonCloudWatchAgent referenced by ref from a resource in namespace.
+func agentReferenceKey(ref *v1alpha1.AgentReference, namespace string) client.ObjectKey {
+	name, ns := v1alpha1.ResolveAgentReference(ref, namespace)
+	return client.ObjectKey{Namespace: ns, Name: name}
+}
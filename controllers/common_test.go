@@ -8,16 +8,22 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
 func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
 	ctx := context.Background()
-	logger := logf.Log.WithName("unit-tests")
 	testCases := []struct {
 		name     string
 		config   string
@@ -61,7 +67,7 @@ func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client) v1alpha1.AmazonCloudWatchAgent {
+		getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client, agentKey client.ObjectKey) v1alpha1.AmazonCloudWatchAgent {
 			return v1alpha1.AmazonCloudWatchAgent{
 				ObjectMeta: metav1.ObjectMeta{},
 				Spec: v1alpha1.AmazonCloudWatchAgentSpec{
@@ -69,7 +75,109 @@ func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
 				},
 			}
 		}
-		actual := enabledAcceleratedComputeByAgentConfig(ctx, nil, logger)
+		actual := enabledAcceleratedComputeByAgentConfig(ctx, nil, logger, agentReferenceKey(nil, ""))
 		assert.Equal(t, tc.expected, actual)
 	}
 }
+
+func TestAgentReferenceKey(t *testing.T) {
+	assert.Equal(t, client.ObjectKey{Namespace: "amazon-cloudwatch", Name: "cloudwatch-agent"}, agentReferenceKey(nil, "gpu"))
+	assert.Equal(t, client.ObjectKey{Namespace: "gpu", Name: "agent"}, agentReferenceKey(&v1alpha1.AgentReference{Name: "agent"}, "gpu"))
+	assert.Equal(t, client.ObjectKey{Namespace: "other", Name: "agent"}, agentReferenceKey(&v1alpha1.AgentReference{Name: "agent", Namespace: "other"}, "gpu"))
+}
+
+func TestRequestsForAgent(t *testing.T) {
+	params, _ := newTestParams(t, config.New(),
+		&v1alpha1.DcgmExporter{ObjectMeta: metav1.ObjectMeta{Name: "default-ref", Namespace: "amazon-cloudwatch"}},
+		&v1alpha1.DcgmExporter{
+			ObjectMeta: metav1.ObjectMeta{Name: "custom-ref", Namespace: "gpu"},
+			Spec:       v1alpha1.DcgmExporterSpec{AgentRef: &v1alpha1.AgentReference{Name: "agent"}},
+		},
+		&v1alpha1.NeuronMonitor{ObjectMeta: metav1.ObjectMeta{Name: "default-ref", Namespace: "amazon-cloudwatch"}},
+	)
+	dcgmReconciler := NewDcgmExporterReconciler(params)
+	neuronReconciler := NewNeuronMonitorReconciler(params)
+
+	defaultAgent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"}}
+	customAgent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "gpu"}}
+	unrelatedAgent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"}}
+
+	ctx := context.Background()
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "default-ref"}}},
+		dcgmReconciler.requestsForAgent(ctx, defaultAgent))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "gpu", Name: "custom-ref"}}},
+		dcgmReconciler.requestsForAgent(ctx, customAgent))
+	assert.Empty(t, dcgmReconciler.requestsForAgent(ctx, unrelatedAgent))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "default-ref"}}},
+		neuronReconciler.requestsForAgent(ctx, defaultAgent))
+	assert.Empty(t, neuronReconciler.requestsForAgent(ctx, customAgent))
+}
+
+func TestRequestsForExtraConfigMap(t *testing.T) {
+	r := newTestReconciler(t,
+		&v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "mounting", Namespace: "amazon-cloudwatch"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{ConfigMaps: []v1alpha1.ConfigMapsSpec{{Name: "extra", MountPath: "/etc/extra"}}},
+		},
+		&v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "amazon-cloudwatch"}},
+		&v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "mounting", Namespace: "other"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{ConfigMaps: []v1alpha1.ConfigMapsSpec{{Name: "extra", MountPath: "/etc/extra"}}},
+		},
+	)
+
+	ctx := context.Background()
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "mounting"}}},
+		r.requestsForExtraConfigMap(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "extra", Namespace: "amazon-cloudwatch"}}))
+	assert.Empty(t, r.requestsForExtraConfigMap(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "amazon-cloudwatch"}}))
+}
+
+func TestRequestsForConfigSource(t *testing.T) {
+	r := newTestReconciler(t,
+		&v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "from-configmap", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{OtelConfigFrom: &v1alpha1.ConfigSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "otel"}, Key: "config"},
+			}},
+		},
+		&v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "from-secret", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{ConfigFrom: &v1alpha1.ConfigSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+			}},
+		},
+	)
+
+	ctx := context.Background()
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "from-configmap"}}},
+		r.requestsForExtraConfigMap(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "otel", Namespace: "amazon-cloudwatch"}}))
+	assert.Empty(t, r.requestsForExtraConfigMap(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"}}))
+}
+
+func TestFindOwnedObjectsSkipsHPAsManagedByKEDA(t *testing.T) {
+	instance := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", UID: "agent-uid"},
+	}
+	labels := manifestutils.SelectorLabelsForAllOperatorManaged(instance.ObjectMeta)
+	owned := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{
+		Name: "agent", Namespace: "amazon-cloudwatch", UID: "owned-uid", Labels: labels,
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "cloudwatch.aws.amazon.com/v1alpha1", Kind: "AmazonCloudWatchAgent",
+			Name: "agent", UID: "agent-uid", Controller: ptr.To(true),
+		}},
+	}}
+	// KEDA copies the labels of the ScaledObject to its HorizontalPodAutoscaler
+	managedByKEDA := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{
+		Name: "keda-hpa-agent", Namespace: "amazon-cloudwatch", UID: "keda-uid", Labels: labels,
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "keda.sh/v1alpha1", Kind: "ScaledObject",
+			Name: "agent", UID: "scaledobject-uid", Controller: ptr.To(true),
+		}},
+	}}
+	r := newTestReconciler(t, owned, managedByKEDA)
+
+	objs, err := r.findCloudWatchAgentOwnedObjects(context.Background(), instance)
+	require.NoError(t, err)
+	assert.Contains(t, objs, types.UID("owned-uid"))
+	assert.NotContains(t, objs, types.UID("keda-uid"))
+}
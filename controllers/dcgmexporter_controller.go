@@ -15,13 +15,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/nodediscovery"
 	dcgmexporterStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
 )
 
 // DcgmExporterReconciler reconciles a DcgmExporter object.
@@ -68,6 +75,8 @@ func NewDcgmExporterReconciler(p Params) *DcgmExporterReconciler {
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=dcgmexporters,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=dcgmexporters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=dcgmexporters/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 
 // Reconcile the current state of an OpenTelemetry collector resource with the desired state.
 func (r *DcgmExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -90,12 +99,20 @@ func (r *DcgmExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	params := r.getParams(instance)
+	if nodediscovery.Enabled(instance.Spec.NodeDiscovery) {
+		discovery, err := nodediscovery.Discover(ctx, r.Client, instance.Spec.NodeDiscovery, nodediscovery.DcgmExporterDefaults)
+		if err != nil {
+			log.Error(err, "unable to discover the nodes with accelerators")
+			return ctrl.Result{}, err
+		}
+		params.NodeDiscovery = discovery
+	}
 	desiredObjects, buildErr := BuildDcgmExporter(params)
 	if buildErr != nil {
 		return ctrl.Result{}, buildErr
 	}
 
-	if !enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log) {
+	if !enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log, agentReferenceKey(instance.Spec.AgentRef, instance.Namespace)) {
 		log.Info("enhanced_container_insights or accelerated_compute_metrics is disabled")
 		for _, obj := range desiredObjects {
 			if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
@@ -128,13 +145,58 @@ func BuildDcgmExporter(params manifests.Params) ([]client.Object, error) {
 
 // SetupWithManager tells the manager what our controller is interested in.
 func (r *DcgmExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	builder := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.DcgmExporter{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
-		Owns(&appsv1.DaemonSet{})
+		Owns(&appsv1.DaemonSet{}).
+		Watches(&v1alpha1.AmazonCloudWatchAgent{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForAgent),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNode),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return nodediscovery.NodeChanged(e.ObjectOld.(*corev1.Node), e.ObjectNew.(*corev1.Node))
+				},
+			}))
+
+	return b.Complete(tracing.NewReconciler("dcgmexporter", r))
+}
+
+// requestsForAgent enqueues the DcgmExporter resources referencing the AmazonCloudWatchAgent, whose configuration
+// decides whether they are deployed.
+func (r *DcgmExporterReconciler) requestsForAgent(ctx context.Context, agent client.Object) []reconcile.Request {
+	var list v1alpha1.DcgmExporterList
+	if err := r.List(ctx, &list); err != nil {
+		r.log.Error(err, "unable to list DcgmExporter resources", "AmazonCloudWatchAgent", client.ObjectKeyFromObject(agent))
+		return nil
+	}
+	agentKey := client.ObjectKeyFromObject(agent)
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		if agentReferenceKey(item.Spec.AgentRef, item.Namespace) == agentKey {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+	}
+	return requests
+}
 
-	return builder.Complete(r)
+// requestsForNode enqueues the DcgmExporter resources discovering their nodes, since the node may have become eligible or
+// ineligible.
+func (r *DcgmExporterReconciler) requestsForNode(ctx context.Context, _ client.Object) []reconcile.Request {
+	var list v1alpha1.DcgmExporterList
+	if err := r.List(ctx, &list); err != nil {
+		r.log.Error(err, "unable to list DcgmExporter resources")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		if nodediscovery.Enabled(item.Spec.NodeDiscovery) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+	}
+	return requests
 }
@@ -0,0 +1,432 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	defaultCanaryNodePercentage   = 10
+	defaultCanaryBakeTime         = 5 * time.Minute
+	defaultCanaryProgressDeadline = 15 * time.Minute
+	defaultCanaryHealthCheckPath  = "/"
+
+	// canaryRequeueInterval is how often a running canary is checked, since the health endpoint doesn't trigger
+	// any reconciliation.
+	canaryRequeueInterval = 15 * time.Second
+	healthCheckTimeout    = 5 * time.Second
+	// podTemplateGenerationLabel is set by the daemonset controller to the generation of the template of the pod.
+	podTemplateGenerationLabel = "pod-template-generation"
+
+	reasonCanaryStarted    = "CanaryStarted"
+	reasonCanaryPromoted   = "CanaryPromoted"
+	reasonCanaryRolledBack = "CanaryRolledBack"
+)
+
+// healthChecker probes the health endpoint at the url, returning an error when it is not healthy.
+type healthChecker func(ctx context.Context, url string) error
+
+func httpHealthCheck(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// canarySettings are the settings of a canary rollout with their defaults applied.
+type canarySettings struct {
+	nodePercentage   int32
+	bakeTime         time.Duration
+	progressDeadline time.Duration
+	healthCheck      *v1alpha1.CanaryHealthCheck
+}
+
+func newCanarySettings(strategy *v1alpha1.RolloutStrategy) canarySettings {
+	settings := canarySettings{
+		nodePercentage:   defaultCanaryNodePercentage,
+		bakeTime:         defaultCanaryBakeTime,
+		progressDeadline: defaultCanaryProgressDeadline,
+	}
+	canary := strategy.Canary
+	if canary == nil {
+		return settings
+	}
+	if canary.NodePercentage != nil {
+		settings.nodePercentage = *canary.NodePercentage
+	}
+	if canary.BakeTime != nil {
+		settings.bakeTime = canary.BakeTime.Duration
+	}
+	if canary.ProgressDeadline != nil {
+		settings.progressDeadline = canary.ProgressDeadline.Duration
+	}
+	if canary.HealthCheck != nil {
+		settings.healthCheck = canary.HealthCheck.DeepCopy()
+		if settings.healthCheck.Path == "" {
+			settings.healthCheck.Path = defaultCanaryHealthCheckPath
+		}
+	}
+	return settings
+}
+
+// reconcileRollout canaries the configuration changes of the instance when its rollout strategy is Canary. While the
+// canary runs, the main daemonset and config map are held at their live state and the canary objects are added to
+// the desired objects. It sets the rollout status on the params and returns whether the canary must be checked again.
+func (r *AmazonCloudWatchAgentReconciler) reconcileRollout(ctx context.Context, log logr.Logger, params *manifests.Params, desired []client.Object) ([]client.Object, bool, error) {
+	instance := params.OtelCol
+	if !collector.CanaryRolloutEnabled(instance) {
+		params.Rollout = nil
+		return desired, false, r.unlabelCanaryNodes(ctx, instance, nil)
+	}
+
+	status := &v1alpha1.RolloutStatus{}
+	if instance.Status.Rollout != nil {
+		status = instance.Status.Rollout.DeepCopy()
+	}
+	configHash := collector.ConfigHash(instance)
+
+	liveDaemonSet := &appsv1.DaemonSet{}
+	var liveConfig client.Object = &corev1.ConfigMap{}
+	if collector.ConfigInSecret(instance) {
+		liveConfig = &corev1.Secret{}
+	}
+	live, err := r.getLive(ctx, instance, liveDaemonSet, liveConfig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case !live || status.StableConfigHash == "" || !collector.ExcludesCanaryNodes(liveDaemonSet, instance):
+		// nothing running on the nodes is canary aware yet, so the configuration is applied directly
+		params.Rollout = stableRollout(configHash, "")
+		return desired, false, r.unlabelCanaryNodes(ctx, instance, nil)
+
+	case configHash == status.StableConfigHash:
+		message := status.Message
+		if status.Phase == v1alpha1.RolloutPhaseCanary {
+			message = "canary aborted, the configuration was reverted to the stable configuration"
+		}
+		params.Rollout = stableRollout(configHash, message)
+		return desired, false, r.unlabelCanaryNodes(ctx, instance, nil)
+
+	case status.Phase == v1alpha1.RolloutPhaseRolledBack && configHash == status.CanaryConfigHash:
+		// the configuration failed its canary, keep the stable configuration until it changes
+		params.Rollout = status
+		return holdStable(desired, liveDaemonSet, liveConfig), false, r.unlabelCanaryNodes(ctx, instance, nil)
+	}
+
+	settings := newCanarySettings(instance.Spec.RolloutStrategy)
+	if status.Phase != v1alpha1.RolloutPhaseCanary || status.CanaryConfigHash != configHash {
+		nodes, pickErr := r.pickCanaryNodes(ctx, liveDaemonSet, configHash, settings.nodePercentage)
+		if pickErr != nil {
+			return nil, false, pickErr
+		}
+		now := metav1.Now()
+		status = &v1alpha1.RolloutStatus{
+			Phase:            v1alpha1.RolloutPhaseCanary,
+			StableConfigHash: status.StableConfigHash,
+			CanaryConfigHash: configHash,
+			CanaryNodes:      nodes,
+			CanaryStartTime:  &now,
+			Message:          fmt.Sprintf("canary started on %d nodes", len(nodes)),
+		}
+		log.Info("starting canary rollout", "nodes", nodes)
+		params.Recorder.Event(&instance, corev1.EventTypeNormal, reasonCanaryStarted, status.Message)
+	}
+	if err = r.labelCanaryNodes(ctx, instance, status.CanaryNodes); err != nil {
+		return nil, false, err
+	}
+
+	healthy, failure, err := r.canaryHealth(ctx, instance, settings)
+	if err != nil {
+		return nil, false, err
+	}
+	now := metav1.Now()
+	switch {
+	case failure != "":
+		status.Message = "canary rolled back: " + failure
+	case !healthy:
+		status.HealthySince = nil
+		if now.Sub(status.CanaryStartTime.Time) >= settings.progressDeadline {
+			failure = fmt.Sprintf("canary did not become healthy within %s", settings.progressDeadline)
+			status.Message = "canary rolled back: " + failure
+		}
+	case status.HealthySince == nil:
+		status.HealthySince = &now
+	}
+
+	if failure != "" {
+		log.Info("rolling back canary", "reason", failure)
+		params.Recorder.Event(&instance, corev1.EventTypeWarning, reasonCanaryRolledBack, status.Message)
+		status.Phase = v1alpha1.RolloutPhaseRolledBack
+		status.CanaryNodes = nil
+		status.HealthySince = nil
+		params.Rollout = status
+		return holdStable(desired, liveDaemonSet, liveConfig), false, r.unlabelCanaryNodes(ctx, instance, nil)
+	}
+
+	if status.HealthySince != nil && now.Sub(status.HealthySince.Time) >= settings.bakeTime {
+		message := fmt.Sprintf("canary promoted after staying healthy for %s", settings.bakeTime)
+		log.Info("promoting canary")
+		params.Recorder.Event(&instance, corev1.EventTypeNormal, reasonCanaryPromoted, message)
+		params.Rollout = stableRollout(configHash, message)
+		return desired, false, r.unlabelCanaryNodes(ctx, instance, nil)
+	}
+
+	canaryConfig, err := collector.CanaryConfig(*params)
+	if err != nil {
+		return nil, false, err
+	}
+	params.Rollout = status
+	return append(holdStable(desired, liveDaemonSet, liveConfig), canaryConfig, collector.CanaryDaemonSet(*params)), true, nil
+}
+
+func stableRollout(configHash, message string) *v1alpha1.RolloutStatus {
+	return &v1alpha1.RolloutStatus{
+		Phase:            v1alpha1.RolloutPhaseStable,
+		StableConfigHash: configHash,
+		Message:          message,
+	}
+}
+
+// getLive gets the main daemonset and config map, or secret, of the instance, returning whether both exist.
+func (r *AmazonCloudWatchAgentReconciler) getLive(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent, ds *appsv1.DaemonSet, config client.Object) (bool, error) {
+	configName := naming.ConfigMap(instance.Name)
+	if collector.ConfigInSecret(instance) {
+		configName = naming.ConfigSecret(instance.Name)
+	}
+	for _, obj := range []struct {
+		name   string
+		object client.Object
+	}{
+		{name: naming.Collector(instance.Name), object: ds},
+		{name: configName, object: config},
+	} {
+		if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: obj.name}, obj.object); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// holdStable replaces the main daemonset and config map, or secret, of the desired objects with their live state.
+func holdStable(desired []client.Object, ds *appsv1.DaemonSet, config client.Object) []client.Object {
+	held := make([]client.Object, 0, len(desired))
+	for _, obj := range desired {
+		switch {
+		case isObject(obj, ds):
+			held = append(held, ds.DeepCopy())
+		case isObject(obj, config):
+			held = append(held, config.DeepCopyObject().(client.Object))
+		default:
+			held = append(held, obj)
+		}
+	}
+	return held
+}
+
+func isObject(obj client.Object, live client.Object) bool {
+	switch obj.(type) {
+	case *appsv1.DaemonSet:
+		if _, ok := live.(*appsv1.DaemonSet); !ok {
+			return false
+		}
+	case *corev1.ConfigMap:
+		if _, ok := live.(*corev1.ConfigMap); !ok {
+			return false
+		}
+	case *corev1.Secret:
+		if _, ok := live.(*corev1.Secret); !ok {
+			return false
+		}
+	default:
+		return false
+	}
+	return obj.GetName() == live.GetName() && obj.GetNamespace() == live.GetNamespace()
+}
+
+// pickCanaryNodes picks the percentage of the nodes running a pod of the DaemonSet of the instance, so that only nodes
+// the DaemonSet schedules on, given its node selector, affinity and tolerations, are picked. The nodes are ordered by
+// a hash of their name and the configuration, so that consecutive canaries don't always pick the same nodes.
+func (r *AmazonCloudWatchAgentReconciler) pickCanaryNodes(ctx context.Context, ds *appsv1.DaemonSet, configHash string, percentage int32) ([]string, error) {
+	pods := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, pods, client.InNamespace(ds.Namespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels)); err != nil {
+		return nil, fmt.Errorf("failed to list the pods for the canary: %w", err)
+	}
+	// the selector of the DaemonSet matches the pods of the canary DaemonSet as well, whose nodes stay eligible
+	nodes := map[string]bool{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" && pod.DeletionTimestamp == nil {
+			nodes[pod.Spec.NodeName] = true
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	order := make(map[string]uint64, len(nodes))
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(name + configHash))
+		order[name] = h.Sum64()
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return order[names[i]] < order[names[j]]
+	})
+	count := int(math.Ceil(float64(len(names)) * float64(percentage) / 100))
+	if count < 1 {
+		count = 1
+	}
+	if count > len(names) {
+		count = len(names)
+	}
+	picked := names[:count]
+	sort.Strings(picked)
+	return picked, nil
+}
+
+// labelCanaryNodes sets the canary label of the instance on the nodes, and removes it from every other node.
+func (r *AmazonCloudWatchAgentReconciler) labelCanaryNodes(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent, nodeNames []string) error {
+	key := collector.CanaryNodeLabel(instance)
+	for _, name := range nodeNames {
+		node := &corev1.Node{}
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if _, ok := node.Labels[key]; ok {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[key] = ""
+		if err := r.Patch(ctx, node, patch); err != nil {
+			return fmt.Errorf("failed to label canary node %s: %w", name, err)
+		}
+	}
+	return r.unlabelCanaryNodes(ctx, instance, nodeNames)
+}
+
+// unlabelCanaryNodes removes the canary label of the instance from the nodes, except from the nodes to keep.
+func (r *AmazonCloudWatchAgentReconciler) unlabelCanaryNodes(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent, keep []string) error {
+	key := collector.CanaryNodeLabel(instance)
+	selector, err := labels.Parse(key)
+	if err != nil {
+		return err
+	}
+	nodes := &corev1.NodeList{}
+	if err = r.List(ctx, nodes, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return fmt.Errorf("failed to list canary nodes: %w", err)
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[name] = true
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if kept[node.Name] {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		delete(node.Labels, key)
+		if err = r.Patch(ctx, node, patch); err != nil {
+			return fmt.Errorf("failed to unlabel canary node %s: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// canaryHealth returns whether every canary pod is ready and healthy. A failure reason is returned when the canary
+// must be rolled back right away, such as when a canary container restarts.
+func (r *AmazonCloudWatchAgentReconciler) canaryHealth(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent, settings canarySettings) (bool, string, error) {
+	ds := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: naming.CanaryCollector(instance.Name)}, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels)); err != nil {
+		return false, "", fmt.Errorf("failed to list canary pods: %w", err)
+	}
+	// pods of a previous canary may still be terminating, only the pods of the current template are checked
+	generation := strconv.FormatInt(ds.Generation, 10)
+	current := make([]corev1.Pod, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Labels[podTemplateGenerationLabel] == generation {
+			current = append(current, pod)
+		}
+	}
+	for _, pod := range current {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.RestartCount > 0 {
+				return false, fmt.Sprintf("container %s of pod %s restarted", status.Name, pod.Name), nil
+			}
+		}
+	}
+
+	if ds.Status.ObservedGeneration < ds.Generation ||
+		ds.Status.DesiredNumberScheduled == 0 ||
+		ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled ||
+		ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
+		return false, "", nil
+	}
+	if settings.healthCheck == nil {
+		return true, "", nil
+	}
+	for _, pod := range current {
+		if pod.Status.PodIP == "" {
+			return false, "", nil
+		}
+		url := fmt.Sprintf("http://%s%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(settings.healthCheck.Port))), settings.healthCheck.Path)
+		if err := r.healthCheck(ctx, url); err != nil {
+			r.log.V(2).Info("canary pod is not healthy", "pod", pod.Name, "error", err.Error())
+			return false, "", nil
+		}
+	}
+	return true, "", nil
+}
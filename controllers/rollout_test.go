@@ -0,0 +1,282 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+const stableConfig = `{"agent":{"debug":false}}`
+
+func rolloutInstance(config string, status *v1alpha1.RolloutStatus) v1alpha1.AmazonCloudWatchAgent {
+	return v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:   v1alpha1.ModeDaemonSet,
+			Config: config,
+			RolloutStrategy: &v1alpha1.RolloutStrategy{
+				Type: v1alpha1.RolloutStrategyCanary,
+				Canary: &v1alpha1.CanaryRollout{
+					HealthCheck: &v1alpha1.CanaryHealthCheck{Port: 13133},
+				},
+			},
+		},
+		Status: v1alpha1.AmazonCloudWatchAgentStatus{Rollout: status},
+	}
+}
+
+// stableRolloutObjects returns ten nodes running a pod of the stable DaemonSet, and the live objects of the stable
+// configuration.
+func stableRolloutObjects(t *testing.T) []client.Object {
+	stable := newManifestParams(rolloutInstance(stableConfig, nil))
+	configmaps, err := collector.ConfigMaps(stable)
+	require.NoError(t, err)
+	ds := collector.DaemonSet(stable)
+	objs := []client.Object{ds, configmaps[0]}
+	for i := 0; i < 10; i++ {
+		objs = append(objs, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + strconv.Itoa(i)}}, daemonSetPod(ds, i))
+	}
+	return objs
+}
+
+func daemonSetPod(ds *appsv1.DaemonSet, node int) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: ds.Name + "-" + strconv.Itoa(node), Namespace: ds.Namespace, Labels: ds.Spec.Selector.MatchLabels},
+		Spec:       corev1.PodSpec{NodeName: "node-" + strconv.Itoa(node)},
+	}
+}
+
+func canaryNodes(t *testing.T, r *AmazonCloudWatchAgentReconciler, instance v1alpha1.AmazonCloudWatchAgent) []string {
+	nodes := &corev1.NodeList{}
+	require.NoError(t, r.List(context.Background(), nodes, client.HasLabels{collector.CanaryNodeLabel(instance)}))
+	var names []string
+	for _, node := range nodes.Items {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func findDaemonSet(objs []client.Object, name string) *appsv1.DaemonSet {
+	for _, obj := range objs {
+		if ds, ok := obj.(*appsv1.DaemonSet); ok && ds.Name == name {
+			return ds
+		}
+	}
+	return nil
+}
+
+func readyCanary(instance v1alpha1.AmazonCloudWatchAgent, restarts int32) []client.Object {
+	ds := collector.CanaryDaemonSet(newManifestParams(instance))
+	ds.Generation = 1
+	ds.Status = appsv1.DaemonSetStatus{
+		ObservedGeneration:     1,
+		DesiredNumberScheduled: 1,
+		UpdatedNumberScheduled: 1,
+		NumberReady:            1,
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "agent-canary-1",
+			Namespace: instance.Namespace,
+			Labels:    map[string]string{podTemplateGenerationLabel: "1"},
+		},
+		Status: corev1.PodStatus{
+			PodIP:             "10.0.0.1",
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "otc-container", RestartCount: restarts}},
+		},
+	}
+	for k, v := range ds.Spec.Selector.MatchLabels {
+		pod.Labels[k] = v
+	}
+	return []client.Object{ds, pod}
+}
+
+func TestReconcileRolloutAppliesStableConfig(t *testing.T) {
+	r := newTestReconciler(t, stableRolloutObjects(t)...)
+	params := newManifestParams(rolloutInstance(stableConfig, nil))
+
+	desired, requeue, err := r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params)})
+	require.NoError(t, err)
+	assert.False(t, requeue)
+	assert.Len(t, desired, 1)
+	assert.Equal(t, v1alpha1.RolloutPhaseStable, params.Rollout.Phase)
+	assert.Equal(t, collector.ConfigHash(params.OtelCol), params.Rollout.StableConfigHash)
+}
+
+func TestReconcileRolloutStartsCanary(t *testing.T) {
+	r := newTestReconciler(t, stableRolloutObjects(t)...)
+	stableHash := collector.ConfigHash(rolloutInstance(stableConfig, nil))
+	instance := rolloutInstance(`{"agent":{"debug":true}}`, &v1alpha1.RolloutStatus{
+		Phase:            v1alpha1.RolloutPhaseStable,
+		StableConfigHash: stableHash,
+	})
+	params := newManifestParams(instance)
+	configmaps, err := collector.ConfigMaps(params)
+	require.NoError(t, err)
+
+	desired, requeue, err := r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params), configmaps[0]})
+	require.NoError(t, err)
+	assert.True(t, requeue)
+	assert.Equal(t, v1alpha1.RolloutPhaseCanary, params.Rollout.Phase)
+	assert.Equal(t, stableHash, params.Rollout.StableConfigHash)
+	assert.Equal(t, collector.ConfigHash(instance), params.Rollout.CanaryConfigHash)
+	assert.Len(t, params.Rollout.CanaryNodes, 1)
+	assert.Equal(t, params.Rollout.CanaryNodes, canaryNodes(t, r, instance))
+
+	// the main objects are held at their live state, and the canary objects are added
+	require.Len(t, desired, 4)
+	main := findDaemonSet(desired, "agent")
+	require.NotNil(t, main)
+	assert.NotEmpty(t, main.ResourceVersion)
+	assert.NotNil(t, findDaemonSet(desired, "agent-canary"))
+	held := desired[1].(*corev1.ConfigMap)
+	assert.Contains(t, held.Data["cwagentconfig.json"], `"debug":false`)
+}
+
+func TestReconcileRolloutPromotesCanary(t *testing.T) {
+	stableHash := collector.ConfigHash(rolloutInstance(stableConfig, nil))
+	instance := rolloutInstance(`{"agent":{"debug":true}}`, nil)
+	start := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	healthySince := metav1.NewTime(time.Now().Add(-6 * time.Minute))
+	instance.Status.Rollout = &v1alpha1.RolloutStatus{
+		Phase:            v1alpha1.RolloutPhaseCanary,
+		StableConfigHash: stableHash,
+		CanaryConfigHash: collector.ConfigHash(instance),
+		CanaryNodes:      []string{"node-3"},
+		CanaryStartTime:  &start,
+		HealthySince:     &healthySince,
+	}
+	r := newTestReconciler(t, append(stableRolloutObjects(t), readyCanary(instance, 0)...)...)
+	var probed string
+	r.healthCheck = func(_ context.Context, url string) error {
+		probed = url
+		return nil
+	}
+	params := newManifestParams(instance)
+
+	desired, requeue, err := r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params)})
+	require.NoError(t, err)
+	assert.False(t, requeue)
+	assert.Equal(t, "http://10.0.0.1:13133/", probed)
+	assert.Equal(t, v1alpha1.RolloutPhaseStable, params.Rollout.Phase)
+	assert.Equal(t, collector.ConfigHash(instance), params.Rollout.StableConfigHash)
+	assert.Empty(t, canaryNodes(t, r, instance))
+	assert.Empty(t, findDaemonSet(desired, "agent").ResourceVersion)
+}
+
+func TestReconcileRolloutBakesCanary(t *testing.T) {
+	instance := rolloutInstance(`{"agent":{"debug":true}}`, nil)
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	instance.Status.Rollout = &v1alpha1.RolloutStatus{
+		Phase:            v1alpha1.RolloutPhaseCanary,
+		StableConfigHash: collector.ConfigHash(rolloutInstance(stableConfig, nil)),
+		CanaryConfigHash: collector.ConfigHash(instance),
+		CanaryNodes:      []string{"node-3"},
+		CanaryStartTime:  &start,
+	}
+	r := newTestReconciler(t, append(stableRolloutObjects(t), readyCanary(instance, 0)...)...)
+	params := newManifestParams(instance)
+
+	_, requeue, err := r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params)})
+	require.NoError(t, err)
+	assert.True(t, requeue)
+	assert.Equal(t, v1alpha1.RolloutPhaseCanary, params.Rollout.Phase)
+	assert.NotNil(t, params.Rollout.HealthySince)
+
+	// a failing health endpoint restarts the bake time
+	r.healthCheck = func(context.Context, string) error { return errors.New("unhealthy") }
+	params = newManifestParams(instance)
+	_, requeue, err = r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params)})
+	require.NoError(t, err)
+	assert.True(t, requeue)
+	assert.Nil(t, params.Rollout.HealthySince)
+}
+
+func TestReconcileRolloutRollsBackCanary(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		restarts int32
+		started  time.Duration
+		healthy  bool
+	}{
+		{name: "restarted container", restarts: 1, started: time.Minute, healthy: true},
+		{name: "progress deadline exceeded", started: time.Hour},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stableHash := collector.ConfigHash(rolloutInstance(stableConfig, nil))
+			instance := rolloutInstance(`{"agent":{"debug":true}}`, nil)
+			start := metav1.NewTime(time.Now().Add(-tc.started))
+			instance.Status.Rollout = &v1alpha1.RolloutStatus{
+				Phase:            v1alpha1.RolloutPhaseCanary,
+				StableConfigHash: stableHash,
+				CanaryConfigHash: collector.ConfigHash(instance),
+				CanaryNodes:      []string{"node-3"},
+				CanaryStartTime:  &start,
+			}
+			r := newTestReconciler(t, append(stableRolloutObjects(t), readyCanary(instance, tc.restarts)...)...)
+			if !tc.healthy {
+				r.healthCheck = func(context.Context, string) error { return errors.New("unhealthy") }
+			}
+			params := newManifestParams(instance)
+
+			desired, requeue, err := r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params)})
+			require.NoError(t, err)
+			assert.False(t, requeue)
+			assert.Equal(t, v1alpha1.RolloutPhaseRolledBack, params.Rollout.Phase)
+			assert.Equal(t, stableHash, params.Rollout.StableConfigHash)
+			assert.Empty(t, canaryNodes(t, r, instance))
+			assert.NotEmpty(t, findDaemonSet(desired, "agent").ResourceVersion)
+			assert.Nil(t, findDaemonSet(desired, "agent-canary"))
+
+			// the rolled back configuration stays held until it changes
+			instance.Status.Rollout = params.Rollout
+			params = newManifestParams(instance)
+			desired, requeue, err = r.reconcileRollout(context.Background(), r.log, &params, []client.Object{collector.DaemonSet(params)})
+			require.NoError(t, err)
+			assert.False(t, requeue)
+			assert.Equal(t, v1alpha1.RolloutPhaseRolledBack, params.Rollout.Phase)
+			assert.NotEmpty(t, findDaemonSet(desired, "agent").ResourceVersion)
+		})
+	}
+}
+
+func TestPickCanaryNodes(t *testing.T) {
+	stable := newManifestParams(rolloutInstance(stableConfig, nil))
+	ds := collector.DaemonSet(stable)
+	r := newTestReconciler(t, stableRolloutObjects(t)...)
+
+	nodes, err := r.pickCanaryNodes(context.Background(), ds, "hash", 25)
+	require.NoError(t, err)
+	assert.Len(t, nodes, 3)
+	again, err := r.pickCanaryNodes(context.Background(), ds, "hash", 25)
+	require.NoError(t, err)
+	assert.Equal(t, nodes, again)
+
+	// nodes without a pod of the DaemonSet, e.g. tainted or outside its affinity, are never picked
+	r = newTestReconciler(t, append(stableRolloutObjects(t), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "tainted"}})...)
+	nodes, err = r.pickCanaryNodes(context.Background(), ds, "hash", 100)
+	require.NoError(t, err)
+	assert.Len(t, nodes, 10)
+	assert.NotContains(t, nodes, "tainted")
+
+	ds = ds.DeepCopy()
+	ds.Spec.Selector.MatchLabels = map[string]string{"missing": "label"}
+	nodes, err = r.pickCanaryNodes(context.Background(), ds, "hash", 25)
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+}
@@ -0,0 +1,380 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/configsource"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/sidecar"
+)
+
+const (
+	// sidecarRestartedAtAnnotation is set on the pod template of the workloads restarted to replace their stale
+	// sidecars, like the restarts of the auto instrumentation.
+	sidecarRestartedAtAnnotation = "cloudwatch.aws.amazon.com/restartedAt"
+
+	reasonStaleSidecar     = "StaleSidecar"
+	reasonSidecarRestarted = "SidecarRestarted"
+
+	// sidecarSourceIndex indexes the cached pods by the AmazonCloudWatchAgent their sidecar was injected from.
+	sidecarSourceIndex = "sidecar.source"
+)
+
+// SidecarReconciler detects the pods running a sidecar injected from an AmazonCloudWatchAgent which was deleted,
+// changed or switched out of sidecar mode since, and reports the workloads they belong to with Events and the
+// SidecarsUpToDate condition. When enabled, it restarts the workloads, so that their pods are recreated with the
+// current sidecar, or without one.
+type SidecarReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	log      logr.Logger
+	config   config.Config
+	// apiReader reads the owners of the pods, so that the ReplicaSets and Jobs of the cluster aren't cached
+	apiReader client.Reader
+	owners    *owners.Resolver
+
+	// reported are the workloads last reported with stale sidecars, by AmazonCloudWatchAgent, so that the Events are
+	// only emitted when they change rather than on every pod event
+	mu       sync.Mutex
+	reported map[types.NamespacedName]string
+}
+
+// NewSidecarReconciler creates a new reconciler for the pods with an injected sidecar. The APIReader of the params is
+// required, unlike for the other reconcilers.
+func NewSidecarReconciler(p Params) *SidecarReconciler {
+	return &SidecarReconciler{
+		Client:    p.Client,
+		log:       p.Log,
+		config:    p.Config,
+		recorder:  p.Recorder,
+		apiReader: p.APIReader,
+		owners:    owners.NewResolver(p.Log, p.APIReader),
+		reported:  map[types.NamespacedName]string{},
+	}
+}
+
+// staleWorkload is a workload with pods running a stale sidecar. The object is the Deployment, StatefulSet or
+// DaemonSet restarted to recreate them, or the pod itself when it doesn't belong to one of those.
+type staleWorkload struct {
+	kind string
+	obj  client.Object
+	pods int
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/status,verbs=get;update;patch
+
+// Reconcile checks the sidecars injected from the AmazonCloudWatchAgent of the request, which may have been deleted.
+func (r *SidecarReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("AmazonCloudWatchAgent", req.NamespacedName)
+
+	var instance *v1alpha1.AmazonCloudWatchAgent
+	found := &v1alpha1.AmazonCloudWatchAgent{}
+	if err := r.Get(ctx, req.NamespacedName, found); err == nil {
+		// the sidecars are checked once the deletion completes
+		if found.GetDeletionTimestamp() != nil {
+			return ctrl.Result{}, nil
+		}
+		instance = found
+	} else if !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	pods, err := r.injectedPods(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var reason string
+	var stale []corev1.Pod
+	switch {
+	case instance == nil:
+		reason = "was deleted"
+		stale = pods
+	case instance.Spec.Mode != v1alpha1.ModeSidecar:
+		reason = "is no longer in sidecar mode"
+		stale = pods
+	default:
+		// only the changes of the spec affecting the injected sidecar make it stale, not the ones of the autoscaler or
+		// the target allocator for instance
+		reason = "changed since"
+		hash, err := r.sidecarHash(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		for _, pod := range pods {
+			if pod.Annotations[sidecar.SourceHashAnnotation] != hash {
+				stale = append(stale, pod)
+			}
+		}
+	}
+
+	workloads, err := r.staleWorkloads(ctx, stale)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var names []string
+	for _, workload := range workloads {
+		names = append(names, fmt.Sprintf("%s %s", workload.kind, client.ObjectKeyFromObject(workload.obj)))
+	}
+	report := r.staleSetChanged(req.NamespacedName, names)
+	for _, workload := range workloads {
+		if report {
+			message := fmt.Sprintf("%d pods run a sidecar injected from AmazonCloudWatchAgent %s, which %s", workload.pods, req.NamespacedName, reason)
+			r.recorder.Event(workload.obj, corev1.EventTypeWarning, reasonStaleSidecar, message)
+		}
+		if !r.config.RestartStaleSidecars() {
+			continue
+		}
+		restarted, err := r.restart(ctx, workload.obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if restarted {
+			log.Info("restarted the workload to replace its stale sidecars", "kind", workload.kind, "workload", client.ObjectKeyFromObject(workload.obj))
+			r.recorder.Event(workload.obj, corev1.EventTypeNormal, reasonSidecarRestarted, fmt.Sprintf("restarted to replace the sidecar injected from AmazonCloudWatchAgent %s", req.NamespacedName))
+		}
+	}
+
+	if instance == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.updateCondition(ctx, instance, len(pods), names)
+}
+
+// sidecarHash returns the hash of the sidecar the AmazonCloudWatchAgent injects, resolving its configuration like the
+// pod mutator does.
+func (r *SidecarReconciler) sidecarHash(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent) (string, error) {
+	resolved := instance.DeepCopy()
+	if err := configsource.ResolveGenerated(ctx, r.Client, r.config, resolved); err != nil {
+		return "", err
+	}
+	return sidecar.Hash(r.config, *resolved)
+}
+
+// staleSetChanged records the workloads with stale sidecars of the AmazonCloudWatchAgent, and tells whether they
+// changed since the last reconciliation.
+func (r *SidecarReconciler) staleSetChanged(source types.NamespacedName, names []string) bool {
+	stale := strings.Join(names, ", ")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reported[source] == stale {
+		return false
+	}
+	if stale == "" {
+		delete(r.reported, source)
+	} else {
+		r.reported[source] = stale
+	}
+	return true
+}
+
+// injectedPods returns the running pods with a sidecar injected from the AmazonCloudWatchAgent, in any namespace. The
+// cache only holds the pods with an injected sidecar, indexed by their source.
+func (r *SidecarReconciler) injectedPods(ctx context.Context, source types.NamespacedName) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.MatchingFields{sidecarSourceIndex: source.String()}); err != nil {
+		return nil, fmt.Errorf("failed to list the pods with an injected sidecar: %w", err)
+	}
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil || !sidecar.ExistsIn(pod) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// staleWorkloads groups the pods by the workload they belong to, sorted by kind and name.
+func (r *SidecarReconciler) staleWorkloads(ctx context.Context, pods []corev1.Pod) ([]*staleWorkload, error) {
+	workloads := map[string]*staleWorkload{}
+	for i := range pods {
+		pod := &pods[i]
+		podOwners := r.owners.Resolve(ctx, pod.Namespace, pod.OwnerReferences)
+		var kind string
+		var obj client.Object
+		switch {
+		case podOwners.Deployment != nil:
+			kind, obj = "Deployment", &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: podOwners.Deployment.Name}}
+		case podOwners.StatefulSet != nil:
+			kind, obj = "StatefulSet", &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: podOwners.StatefulSet.Name}}
+		case podOwners.DaemonSet != nil:
+			kind, obj = "DaemonSet", &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: podOwners.DaemonSet.Name}}
+		default:
+			kind, obj = "Pod", pod
+		}
+		key := kind + "/" + client.ObjectKeyFromObject(obj).String()
+		if workload, ok := workloads[key]; ok {
+			workload.pods++
+			continue
+		}
+		if kind != "Pod" {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); apierrors.IsNotFound(err) {
+				// the workload is being deleted along with its pods
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		workloads[key] = &staleWorkload{kind: kind, obj: obj, pods: 1}
+	}
+
+	keys := make([]string, 0, len(workloads))
+	for key := range workloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]*staleWorkload, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, workloads[key])
+	}
+	return sorted, nil
+}
+
+// restart sets the restartedAt annotation on the pod template of the workload, so that its pods are recreated. A
+// workload which is paused or being rolled out already isn't restarted again, nor a lone pod.
+func (r *SidecarReconciler) restart(ctx context.Context, obj client.Object) (bool, error) {
+	var template *corev1.PodTemplateSpec
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if o.Spec.Replicas != nil {
+			replicas = *o.Spec.Replicas
+		}
+		if o.Spec.Paused || o.Status.ObservedGeneration < o.Generation || o.Status.UpdatedReplicas < replicas || o.Status.Replicas > o.Status.UpdatedReplicas {
+			return false, nil
+		}
+		template = &o.Spec.Template
+	case *appsv1.StatefulSet:
+		if o.Status.ObservedGeneration < o.Generation || o.Status.UpdateRevision != o.Status.CurrentRevision {
+			return false, nil
+		}
+		template = &o.Spec.Template
+	case *appsv1.DaemonSet:
+		if o.Status.ObservedGeneration < o.Generation || o.Status.UpdatedNumberScheduled < o.Status.DesiredNumberScheduled {
+			return false, nil
+		}
+		template = &o.Spec.Template
+	default:
+		return false, nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[sidecarRestartedAtAnnotation] = time.Now().Format(time.RFC3339)
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
+// updateCondition sets the SidecarsUpToDate condition of the AmazonCloudWatchAgent, when it is in sidecar mode or
+// sidecars were injected from it.
+func (r *SidecarReconciler) updateCondition(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent, injected int, stale []string) error {
+	changed := instance.DeepCopy()
+	switch {
+	case len(stale) > 0:
+		meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.ConditionTypeSidecarsUpToDate,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: instance.Generation,
+			Reason:             v1alpha1.ConditionReasonSidecarsStale,
+			Message:            fmt.Sprintf("stale sidecars in %s", strings.Join(stale, ", ")),
+		})
+	case instance.Spec.Mode == v1alpha1.ModeSidecar || injected > 0:
+		meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.ConditionTypeSidecarsUpToDate,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: instance.Generation,
+			Reason:             v1alpha1.ConditionReasonSidecarsUpToDate,
+			Message:            fmt.Sprintf("%d pods run the current sidecar", injected),
+		})
+	default:
+		meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeSidecarsUpToDate)
+	}
+	if apiequality.Semantic.DeepEqual(instance.Status.Conditions, changed.Status.Conditions) {
+		return nil
+	}
+	// the status is also patched by the AmazonCloudWatchAgent reconciler, a conflict is retried
+	patch := client.MergeFromWithOptions(instance, client.MergeFromWithOptimisticLock{})
+	return r.Status().Patch(ctx, changed, patch)
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *SidecarReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.apiReader == nil {
+		return errors.New("the sidecar reconciler requires an API reader")
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, sidecarSourceIndex, indexSidecarSource); err != nil {
+		return err
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("sidecar").
+		For(&v1alpha1.AmazonCloudWatchAgent{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(requestForSidecarSource),
+			builder.WithPredicates(sidecarPodPredicate))
+
+	return b.Complete(tracing.NewReconciler("sidecar", r))
+}
+
+// sidecarPodPredicate selects the pods with an injected sidecar when they're created or deleted, or when their labels
+// or annotations change, rather than on every status update.
+var sidecarPodPredicate = predicate.And(
+	predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[sidecar.SourceAnnotation]
+		return ok
+	}),
+	predicate.Or[client.Object](predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}),
+)
+
+// indexSidecarSource indexes the pod by the AmazonCloudWatchAgent its sidecar was injected from.
+func indexSidecarSource(obj client.Object) []string {
+	if source, ok := obj.GetAnnotations()[sidecar.SourceAnnotation]; ok {
+		return []string{source}
+	}
+	return nil
+}
+
+// requestForSidecarSource enqueues the AmazonCloudWatchAgent the sidecar of the pod was injected from.
+func requestForSidecarSource(_ context.Context, pod client.Object) []reconcile.Request {
+	namespace, name, ok := strings.Cut(pod.GetAnnotations()[sidecar.SourceAnnotation], "/")
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
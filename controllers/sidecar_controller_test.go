@@ -0,0 +1,222 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/sidecar"
+)
+
+var sidecarSource = types.NamespacedName{Namespace: "agents", Name: "agent"}
+
+func sidecarAgent(generation int64) *v1alpha1.AmazonCloudWatchAgent {
+	return &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: sidecarSource.Name, Namespace: sidecarSource.Namespace, Generation: generation},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeSidecar, Config: `{}`},
+	}
+}
+
+// sidecarWorkload returns a Deployment done rolling out, its ReplicaSet and a pod with a sidecar injected from the
+// given hash.
+func sidecarWorkload(hash string) []client.Object {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "apps", UID: "deploy-uid", Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1},
+	}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "my-app-5d8f", Namespace: "apps", UID: "rs-uid",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "my-app", UID: "deploy-uid", Controller: ptr.To(true)}},
+	}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-app-5d8f-x2k4", Namespace: "apps",
+			Labels: map[string]string{sidecar.InjectedLabel: "agents.agent"},
+			Annotations: map[string]string{
+				sidecar.SourceAnnotation:     sidecarSource.String(),
+				sidecar.SourceHashAnnotation: hash,
+			},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "my-app-5d8f", UID: "rs-uid", Controller: ptr.To(true)}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}, {Name: "otc-container"}}},
+	}
+	return []client.Object{deployment, replicaSet, pod}
+}
+
+// currentSidecarHash returns the hash of the sidecar injected from the AmazonCloudWatchAgent of sidecarAgent.
+func currentSidecarHash(t *testing.T) string {
+	hash, err := sidecar.Hash(config.New(), *sidecarAgent(1))
+	require.NoError(t, err)
+	return hash
+}
+
+func newSidecarReconciler(t *testing.T, restart bool, objs ...client.Object) (*SidecarReconciler, *record.FakeRecorder) {
+	params, recorder := newTestParams(t, config.New(config.WithRestartStaleSidecars(restart)), objs...)
+	return NewSidecarReconciler(params), recorder
+}
+
+func sidecarsCondition(t *testing.T, r *SidecarReconciler) *metav1.Condition {
+	instance := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, r.Get(context.Background(), sidecarSource, instance))
+	return meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionTypeSidecarsUpToDate)
+}
+
+func restartedAt(t *testing.T, r *SidecarReconciler) string {
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "apps", Name: "my-app"}, deployment))
+	return deployment.Spec.Template.Annotations[sidecarRestartedAtAnnotation]
+}
+
+func TestSidecarReconcileUpToDate(t *testing.T) {
+	r, recorder := newSidecarReconciler(t, true, append(sidecarWorkload(currentSidecarHash(t)), sidecarAgent(2))...)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+
+	condition := sidecarsCondition(t, r)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "1 pods run the current sidecar", condition.Message)
+	assert.Empty(t, recorder.Events)
+	assert.Empty(t, restartedAt(t, r))
+}
+
+func TestSidecarReconcileIgnoresChangesOutsideTheSidecar(t *testing.T) {
+	agent := sidecarAgent(2)
+	agent.Spec.Autoscaler = &v1alpha1.AutoscalerSpec{MaxReplicas: ptr.To(int32(3))}
+	r, recorder := newSidecarReconciler(t, true, append(sidecarWorkload(currentSidecarHash(t)), agent)...)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+
+	condition := sidecarsCondition(t, r)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Empty(t, recorder.Events)
+	assert.Empty(t, restartedAt(t, r))
+}
+
+func TestSidecarReconcileChanged(t *testing.T) {
+	r, recorder := newSidecarReconciler(t, false, append(sidecarWorkload("stale"), sidecarAgent(2))...)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+
+	condition := sidecarsCondition(t, r)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1alpha1.ConditionReasonSidecarsStale, condition.Reason)
+	assert.Equal(t, "stale sidecars in Deployment apps/my-app", condition.Message)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning StaleSidecar 1 pods run a sidecar injected from AmazonCloudWatchAgent agents/agent, which changed since", <-recorder.Events)
+	// the workload is only restarted when enabled
+	assert.Empty(t, restartedAt(t, r))
+}
+
+func TestSidecarReconcileReportsStaleSetChanges(t *testing.T) {
+	objs := append(sidecarWorkload("stale"), sidecarAgent(2))
+	r, recorder := newSidecarReconciler(t, false, objs...)
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+		require.NoError(t, err)
+	}
+	// the stale workloads are reported once while they don't change
+	require.Len(t, recorder.Events, 1)
+	<-recorder.Events
+
+	// and again once they're up to date and stale again
+	pod := objs[2].(*corev1.Pod)
+	pod.Annotations[sidecar.SourceHashAnnotation] = currentSidecarHash(t)
+	require.NoError(t, r.Update(context.Background(), pod))
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Events)
+
+	pod.Annotations[sidecar.SourceHashAnnotation] = "stale"
+	require.NoError(t, r.Update(context.Background(), pod))
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestSidecarReconcileReadsOwnersWithAPIReader(t *testing.T) {
+	objs := sidecarWorkload("stale")
+	replicaSet := objs[1]
+	// the ReplicaSets aren't cached, only the API reader has them
+	params, recorder := newTestParams(t, config.New(), objs[0], objs[2])
+	params.APIReader = fake.NewClientBuilder().WithScheme(params.Scheme).WithObjects(replicaSet).Build()
+	r := NewSidecarReconciler(params)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning StaleSidecar")
+	assert.Equal(t, "Deployment apps/my-app", r.reported[sidecarSource])
+}
+
+func TestSidecarReconcileDeletedRestartsWorkload(t *testing.T) {
+	r, recorder := newSidecarReconciler(t, true, sidecarWorkload("stale")...)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.Events, 2)
+	assert.Equal(t, "Warning StaleSidecar 1 pods run a sidecar injected from AmazonCloudWatchAgent agents/agent, which was deleted", <-recorder.Events)
+	assert.Equal(t, "Normal SidecarRestarted restarted to replace the sidecar injected from AmazonCloudWatchAgent agents/agent", <-recorder.Events)
+	assert.NotEmpty(t, restartedAt(t, r))
+}
+
+func TestSidecarReconcileSkipsRollingOutWorkload(t *testing.T) {
+	objs := sidecarWorkload("stale")
+	// the Deployment is rolling out its new pods already
+	objs[0].(*appsv1.Deployment).Status.UpdatedReplicas = 0
+	r, recorder := newSidecarReconciler(t, true, objs...)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: sidecarSource})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Empty(t, restartedAt(t, r))
+}
+
+func TestSidecarPodPredicate(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{sidecar.SourceAnnotation: "agents/agent"}}}
+	assert.True(t, sidecarPodPredicate.Create(event.CreateEvent{Object: pod}))
+	assert.True(t, sidecarPodPredicate.Delete(event.DeleteEvent{Object: pod}))
+	assert.False(t, sidecarPodPredicate.Create(event.CreateEvent{Object: &corev1.Pod{}}))
+
+	running := pod.DeepCopy()
+	running.Status.Phase = corev1.PodRunning
+	assert.False(t, sidecarPodPredicate.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: running}))
+
+	changed := pod.DeepCopy()
+	changed.Annotations[sidecar.SourceHashAnnotation] = "stale"
+	assert.True(t, sidecarPodPredicate.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: changed}))
+}
+
+func TestRequestForSidecarSource(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{sidecar.SourceAnnotation: "agents/agent"}}}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: sidecarSource}}, requestForSidecarSource(context.Background(), pod))
+	assert.Empty(t, requestForSidecarSource(context.Background(), &corev1.Pod{}))
+}
@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+const (
+	// statefulSetRecreateRequeueInterval is how often the deletion of an orphaned StatefulSet is checked.
+	statefulSetRecreateRequeueInterval = 5 * time.Second
+
+	reasonStatefulSetOrphaned  = "StatefulSetOrphaned"
+	reasonStatefulSetRecreated = "StatefulSetRecreated"
+)
+
+// reconcileStatefulSetRecreate deletes the StatefulSets with an immutable field change while orphaning their pods,
+// when the recreate policy of the instance is OrphanAndRecreate. The pods are relabeled to match the selector of the
+// desired StatefulSet, so that it takes them over along with their persistent volume claims once recreated. It sets
+// the recreation condition on the params and returns whether the reconciliation must wait for the deletion.
+func (r *AmazonCloudWatchAgentReconciler) reconcileStatefulSetRecreate(ctx context.Context, log logr.Logger, params *manifests.Params, desired []client.Object) (bool, error) {
+	instance := params.OtelCol
+	if instance.Spec.Mode != v1alpha1.ModeStatefulSet || instance.Spec.StatefulSetRecreatePolicy != v1alpha1.StatefulSetRecreatePolicyOrphanAndRecreate {
+		return false, nil
+	}
+
+	waiting := false
+	for _, obj := range desired {
+		desiredSts, ok := obj.(*appsv1.StatefulSet)
+		if !ok {
+			continue
+		}
+
+		existing := &appsv1.StatefulSet{}
+		err := r.Get(ctx, client.ObjectKeyFromObject(desiredSts), existing)
+		if apierrors.IsNotFound(err) {
+			// the StatefulSet orphaned on a previous reconciliation is gone, it is created along with the other objects
+			if meta.IsStatusConditionPresentAndEqual(instance.Status.Conditions, v1alpha1.ConditionTypeStatefulSetRecreated, metav1.ConditionFalse) {
+				message := fmt.Sprintf("StatefulSet %s recreated, taking over the existing pods", desiredSts.Name)
+				params.Recorder.Event(&instance, corev1.EventTypeNormal, reasonStatefulSetRecreated, message)
+				params.Conditions = append(params.Conditions, recreateCondition(instance, metav1.ConditionTrue, v1alpha1.ConditionReasonStatefulSetRecreated, message))
+			}
+			continue
+		} else if err != nil {
+			return false, err
+		}
+
+		if existing.DeletionTimestamp != nil {
+			log.V(1).Info("waiting for the orphaned StatefulSet to be deleted", "statefulset", existing.Name)
+			waiting = true
+			continue
+		}
+
+		hasChange, field := manifests.HasImmutableFieldChange(existing, desiredSts)
+		if !hasChange {
+			continue
+		}
+
+		pods, err := r.podsControlledBy(ctx, existing)
+		if err != nil {
+			return false, err
+		}
+		// the pods are relabeled first, the labels of the new selector are ignored by the existing StatefulSet. Once it
+		// is deleted, the pods left with its selector only couldn't be told apart anymore, and would clash with the
+		// ones of its replacement.
+		if err := r.adoptPods(ctx, pods, desiredSts); err != nil {
+			return false, err
+		}
+		log.Info("detected immutable field change, deleting the StatefulSet and orphaning its pods", "statefulset", existing.Name, "field", field)
+		if err := r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+
+		message := fmt.Sprintf("%s of StatefulSet %s changed, deleted it and orphaned %d pods to be taken over by its replacement", field, existing.Name, len(pods))
+		params.Recorder.Event(&instance, corev1.EventTypeNormal, reasonStatefulSetOrphaned, message)
+		params.Conditions = append(params.Conditions, recreateCondition(instance, metav1.ConditionFalse, v1alpha1.ConditionReasonStatefulSetRecreating, message))
+		waiting = true
+	}
+	return waiting, nil
+}
+
+// podsControlledBy returns the pods matching the selector of the StatefulSet which it controls.
+func (r *AmazonCloudWatchAgentReconciler) podsControlledBy(ctx context.Context, sts *appsv1.StatefulSet) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	podList := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, podList, client.InNamespace(sts.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list the pods of StatefulSet %s: %w", sts.Name, err)
+	}
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if metav1.IsControlledBy(&pod, sts) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// adoptPods adds the match labels of the selector of the StatefulSet to the pods, so that it takes them over.
+func (r *AmazonCloudWatchAgentReconciler) adoptPods(ctx context.Context, pods []corev1.Pod, sts *appsv1.StatefulSet) error {
+	if sts.Spec.Selector == nil {
+		return nil
+	}
+	for i := range pods {
+		pod := &pods[i]
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		for k, v := range sts.Spec.Selector.MatchLabels {
+			pod.Labels[k] = v
+		}
+		if err := r.Patch(ctx, pod, patch); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to relabel pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+func recreateCondition(instance v1alpha1.AmazonCloudWatchAgent, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               v1alpha1.ConditionTypeStatefulSetRecreated,
+		Status:             status,
+		ObservedGeneration: instance.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+}
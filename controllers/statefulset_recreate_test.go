@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func recreateInstance(policy v1alpha1.StatefulSetRecreatePolicy, conditions ...metav1.Condition) v1alpha1.AmazonCloudWatchAgent {
+	return v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:                      v1alpha1.ModeStatefulSet,
+			StatefulSetRecreatePolicy: policy,
+		},
+		Status: v1alpha1.AmazonCloudWatchAgentStatus{Conditions: conditions},
+	}
+}
+
+func recreateStatefulSet(selector map[string]string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "agent",
+			Namespace:         "default",
+			UID:               "sts-uid",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+	}
+}
+
+func TestReconcileStatefulSetRecreateOrphansPods(t *testing.T) {
+	existing := recreateStatefulSet(map[string]string{"app": "agent"})
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "agent-0",
+		Namespace: "default",
+		Labels:    map[string]string{"app": "agent"},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "StatefulSet", Name: "agent", UID: existing.UID, Controller: ptr.To(true),
+		}},
+	}}
+	r := newTestReconciler(t, existing, pod)
+	params := newManifestParams(recreateInstance(v1alpha1.StatefulSetRecreatePolicyOrphanAndRecreate))
+	desired := recreateStatefulSet(map[string]string{"app": "agent", "component": "collector"})
+
+	waiting, err := r.reconcileStatefulSetRecreate(context.Background(), r.log, &params, []client.Object{desired})
+	require.NoError(t, err)
+	assert.True(t, waiting)
+
+	err = r.Get(context.Background(), client.ObjectKeyFromObject(existing), &appsv1.StatefulSet{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	adopted := &corev1.Pod{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(pod), adopted))
+	assert.Equal(t, map[string]string{"app": "agent", "component": "collector"}, adopted.Labels)
+
+	require.Len(t, params.Conditions, 1)
+	assert.Equal(t, v1alpha1.ConditionTypeStatefulSetRecreated, params.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, params.Conditions[0].Status)
+	assert.Equal(t, v1alpha1.ConditionReasonStatefulSetRecreating, params.Conditions[0].Reason)
+
+	// the replacement is created on the next reconciliation
+	params = newManifestParams(recreateInstance(v1alpha1.StatefulSetRecreatePolicyOrphanAndRecreate, params.Conditions[0]))
+	waiting, err = r.reconcileStatefulSetRecreate(context.Background(), r.log, &params, []client.Object{desired})
+	require.NoError(t, err)
+	assert.False(t, waiting)
+	require.Len(t, params.Conditions, 1)
+	assert.Equal(t, metav1.ConditionTrue, params.Conditions[0].Status)
+	assert.Equal(t, v1alpha1.ConditionReasonStatefulSetRecreated, params.Conditions[0].Reason)
+}
+
+func TestReconcileStatefulSetRecreateRelabelsBeforeDeleting(t *testing.T) {
+	existing := recreateStatefulSet(map[string]string{"app": "agent"})
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "agent-0",
+		Namespace: "default",
+		Labels:    map[string]string{"app": "agent"},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "StatefulSet", Name: "agent", UID: existing.UID, Controller: ptr.To(true),
+		}},
+	}}
+	params, _ := newTestParams(t, config.New(), existing, pod)
+	failPatch := true
+	params.Client = interceptor.NewClient(params.Client.(client.WithWatch), interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if failPatch {
+				return errors.New("conflict")
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	})
+	r := NewReconciler(params)
+	manifestParams := newManifestParams(recreateInstance(v1alpha1.StatefulSetRecreatePolicyOrphanAndRecreate))
+	desired := recreateStatefulSet(map[string]string{"app": "agent", "component": "collector"})
+
+	// the StatefulSet is kept while its pods can't be relabeled
+	_, err := r.reconcileStatefulSetRecreate(context.Background(), r.log, &manifestParams, []client.Object{desired})
+	require.Error(t, err)
+	assert.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(existing), &appsv1.StatefulSet{}))
+
+	// and deleted once they are, on the next reconciliation
+	failPatch = false
+	waiting, err := r.reconcileStatefulSetRecreate(context.Background(), r.log, &manifestParams, []client.Object{desired})
+	require.NoError(t, err)
+	assert.True(t, waiting)
+	assert.True(t, apierrors.IsNotFound(r.Get(context.Background(), client.ObjectKeyFromObject(existing), &appsv1.StatefulSet{})))
+	adopted := &corev1.Pod{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(pod), adopted))
+	assert.Equal(t, map[string]string{"app": "agent", "component": "collector"}, adopted.Labels)
+}
+
+func TestReconcileStatefulSetRecreateSkipsUnchanged(t *testing.T) {
+	existing := recreateStatefulSet(map[string]string{"app": "agent"})
+	r := newTestReconciler(t, existing)
+	params := newManifestParams(recreateInstance(v1alpha1.StatefulSetRecreatePolicyOrphanAndRecreate))
+
+	waiting, err := r.reconcileStatefulSetRecreate(context.Background(), r.log, &params, []client.Object{recreateStatefulSet(map[string]string{"app": "agent"})})
+	require.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Empty(t, params.Conditions)
+	assert.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(existing), &appsv1.StatefulSet{}))
+}
+
+func TestReconcileStatefulSetRecreateDefaultPolicy(t *testing.T) {
+	existing := recreateStatefulSet(map[string]string{"app": "agent"})
+	r := newTestReconciler(t, existing)
+	params := newManifestParams(recreateInstance(""))
+
+	waiting, err := r.reconcileStatefulSetRecreate(context.Background(), r.log, &params, []client.Object{recreateStatefulSet(map[string]string{"app": "other"})})
+	require.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Empty(t, params.Conditions)
+	// the StatefulSet is deleted along with its pods by the regular reconciliation instead
+	assert.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(existing), &appsv1.StatefulSet{}))
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+var logger = logf.Log.WithName("unit-tests")
+
+// newTestParams returns the Params of a controller over a fake client holding the objects, and the recorder of its
+// events.
+func newTestParams(t *testing.T, cfg config.Config, objs ...client.Object) (Params, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	recorder := record.NewFakeRecorder(10)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.AmazonCloudWatchAgent{}).
+		WithIndex(&corev1.Pod{}, sidecarSourceIndex, indexSidecarSource).Build()
+	return Params{
+		Client:    c,
+		APIReader: c,
+		Recorder:  recorder,
+		Scheme:    scheme,
+		Log:       logger,
+		Config:    cfg,
+	}, recorder
+}
+
+// newTestReconciler returns an AmazonCloudWatchAgentReconciler over a fake client holding the objects, whose canaries
+// are healthy.
+func newTestReconciler(t *testing.T, objs ...client.Object) *AmazonCloudWatchAgentReconciler {
+	params, _ := newTestParams(t, config.New(), objs...)
+	r := NewReconciler(params)
+	r.healthCheck = func(context.Context, string) error { return nil }
+	return r
+}
+
+// newManifestParams returns the params building the manifests of the instance.
+func newManifestParams(instance v1alpha1.AmazonCloudWatchAgent) manifests.Params {
+	return manifests.Params{
+		Config:   config.New(),
+		OtelCol:  instance,
+		Log:      logger,
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
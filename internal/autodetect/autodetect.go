@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package autodetect detects the optional APIs served by the cluster.
+package autodetect
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	kedav1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/internal/keda/v1alpha1"
+)
+
+// GatewayAPIAvailable returns whether the cluster serves the HTTPRoute and GRPCRoute resources of the Gateway API.
+func GatewayAPIAvailable(dcl discovery.DiscoveryInterface) (bool, error) {
+	return resourcesServed(dcl, gatewayv1.GroupVersion.String(), "httproutes", "grpcroutes")
+}
+
+// KEDAAvailable returns whether the cluster serves the ScaledObject resource of KEDA.
+func KEDAAvailable(dcl discovery.DiscoveryInterface) (bool, error) {
+	return resourcesServed(dcl, kedav1alpha1.GroupVersion.String(), "scaledobjects")
+}
+
+// nativeSidecarMinVersion is the first Kubernetes version with the SidecarContainers feature gate enabled by default.
+var nativeSidecarMinVersion = version.MajorMinor(1, 29)
+
+// NativeSidecarSupported returns whether the API server supports native sidecar containers, detected from its version.
+func NativeSidecarSupported(dcl discovery.DiscoveryInterface) (bool, error) {
+	info, err := dcl.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return false, err
+	}
+	return serverVersion.AtLeast(nativeSidecarMinVersion), nil
+}
+
+// resourcesServed returns whether the cluster serves all the resources of the group version.
+func resourcesServed(dcl discovery.DiscoveryInterface, groupVersion string, names ...string) (bool, error) {
+	resources, err := dcl.ServerResourcesForGroupVersion(groupVersion)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	served := map[string]bool{}
+	for _, resource := range resources.APIResources {
+		served[resource.Name] = true
+	}
+	for _, name := range names {
+		if !served[name] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,86 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package autodetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGatewayAPIAvailable(t *testing.T) {
+	for _, tt := range []struct {
+		desc      string
+		resources []*metav1.APIResourceList
+		expected  bool
+	}{
+		{
+			desc:     "gateway api not installed",
+			expected: false,
+		},
+		{
+			desc: "routes served",
+			resources: []*metav1.APIResourceList{{
+				GroupVersion: "gateway.networking.k8s.io/v1",
+				APIResources: []metav1.APIResource{{Name: "gateways"}, {Name: "httproutes"}, {Name: "grpcroutes"}},
+			}},
+			expected: true,
+		},
+		{
+			desc: "grpc routes not served",
+			resources: []*metav1.APIResourceList{{
+				GroupVersion: "gateway.networking.k8s.io/v1",
+				APIResources: []metav1.APIResource{{Name: "gateways"}, {Name: "httproutes"}},
+			}},
+			expected: false,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			dcl := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: tt.resources}}
+
+			available, err := GatewayAPIAvailable(dcl)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, available)
+		})
+	}
+}
+
+func TestKEDAAvailable(t *testing.T) {
+	dcl := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	available, err := KEDAAvailable(dcl)
+	require.NoError(t, err)
+	assert.False(t, available)
+
+	dcl.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "keda.sh/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "scaledobjects"}, {Name: "triggerauthentications"}},
+	}}
+	available, err = KEDAAvailable(dcl)
+	require.NoError(t, err)
+	assert.True(t, available)
+}
+
+func TestNativeSidecarSupported(t *testing.T) {
+	for _, tt := range []struct {
+		gitVersion string
+		expected   bool
+	}{
+		{gitVersion: "v1.28.9", expected: false},
+		{gitVersion: "v1.29.0", expected: true},
+		{gitVersion: "v1.30.4-eks-a737599", expected: true},
+	} {
+		t.Run(tt.gitVersion, func(t *testing.T) {
+			dcl := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}, FakedServerVersion: &version.Info{GitVersion: tt.gitVersion}}
+
+			supported, err := NativeSidecarSupported(dcl)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, supported)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certificates provisions and rotates the TLS certificates securing the connections between the collectors
+// and the target allocator.
+package certificates
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	certificatePEMType = "CERTIFICATE"
+	privateKeyPEMType  = "PRIVATE KEY"
+
+	// renewalFraction is the fraction of the lifetime of a certificate after which it is renewed.
+	renewalFraction = 2.0 / 3.0
+)
+
+// KeyPair is a certificate along with its private key.
+type KeyPair struct {
+	Cert    *x509.Certificate
+	Key     crypto.Signer
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// NewCA generates a self-signed CA valid for the given duration from now.
+func NewCA(commonName string, validity time.Duration, now time.Time) (*KeyPair, error) {
+	template, err := newTemplate(commonName, validity, now)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	return sign(template, nil)
+}
+
+// Issue generates a certificate signed by the CA, valid for the given duration from now and never beyond the CA.
+func (ca *KeyPair) Issue(commonName string, dnsNames []string, usage x509.ExtKeyUsage, validity time.Duration, now time.Time) (*KeyPair, error) {
+	template, err := newTemplate(commonName, validity, now)
+	if err != nil {
+		return nil, err
+	}
+	if template.NotAfter.After(ca.Cert.NotAfter) {
+		template.NotAfter = ca.Cert.NotAfter
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	return sign(template, ca)
+}
+
+// ParseKeyPair parses the PEM encoded certificate and private key.
+func ParseKeyPair(certPEM, keyPEM []byte) (*KeyPair, error) {
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != privateKeyPEMType {
+		return nil, errors.New("no private key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("the private key can't sign")
+	}
+	return &KeyPair{Cert: cert, Key: signer, CertPEM: certPEM, KeyPEM: keyPEM}, nil
+}
+
+// ParseCertificate parses the first PEM encoded certificate.
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != certificatePEMType {
+		return nil, errors.New("no certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// RenewalTime returns when the certificate is due for renewal, once two thirds of its lifetime have elapsed.
+func RenewalTime(cert *x509.Certificate) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * renewalFraction))
+}
+
+// Bundle concatenates the PEM encoded certificates.
+func Bundle(certPEMs ...[]byte) []byte {
+	var buf bytes.Buffer
+	for _, certPEM := range certPEMs {
+		buf.Write(certPEM)
+	}
+	return buf.Bytes()
+}
+
+func newTemplate(commonName string, validity time.Duration, now time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the serial number: %w", err)
+	}
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		// tolerate the clock skew between the operator and the peers validating the certificate
+		NotBefore: now.Add(-5 * time.Minute),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+// sign generates a key and signs the template with the key of the CA, or self-signs it when the CA is nil.
+func sign(template *x509.Certificate, ca *KeyPair) (*KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the private key: %w", err)
+	}
+	parent, signer := template, crypto.Signer(key)
+	if ca != nil {
+		parent, signer = ca.Cert, ca.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the private key: %w", err)
+	}
+	return &KeyPair{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: keyDER}),
+	}, nil
+}
@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssue(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCA("ca", time.Hour, now)
+	require.NoError(t, err)
+	assert.True(t, ca.Cert.IsCA)
+
+	cert, err := ca.Issue("server", []string{"ta.default.svc"}, x509.ExtKeyUsageServerAuth, 2*time.Hour, now)
+	require.NoError(t, err)
+	assert.NoError(t, cert.Cert.CheckSignatureFrom(ca.Cert))
+	assert.Equal(t, []string{"ta.default.svc"}, cert.Cert.DNSNames)
+	// the certificate doesn't outlive its CA
+	assert.Equal(t, ca.Cert.NotAfter, cert.Cert.NotAfter)
+
+	_, err = tls.X509KeyPair(cert.CertPEM, cert.KeyPEM)
+	assert.NoError(t, err)
+
+	parsed, err := ParseKeyPair(cert.CertPEM, cert.KeyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, cert.Cert.SerialNumber, parsed.Cert.SerialNumber)
+
+	_, err = ParseKeyPair(cert.KeyPEM, cert.CertPEM)
+	assert.Error(t, err)
+}
+
+func TestRenewalTime(t *testing.T) {
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(3 * time.Hour)}
+	assert.Equal(t, notBefore.Add(2*time.Hour), RenewalTime(cert))
+}
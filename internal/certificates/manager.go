@@ -0,0 +1,241 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
+)
+
+const (
+	// CAValidity is how long the generated CA is valid.
+	CAValidity = 5 * 365 * 24 * time.Hour
+	// CertificateValidity is how long the certificates issued by the CA are valid.
+	CertificateValidity = 365 * 24 * time.Hour
+
+	// CACertKey is the secret key of the PEM encoded CA bundle trusted by the collectors and the target allocator.
+	CACertKey = "ca.crt"
+	// CAKeyKey is the secret key of the PEM encoded private key of the CA.
+	CAKeyKey = "ca.key"
+	// PreviousCACertKey is the secret key of the CA replaced by the last rotation, trusted until it expires.
+	PreviousCACertKey = "previous-ca.crt"
+
+	caCommonName     = "amazon-cloudwatch-agent-target-allocator-ca"
+	serverCommonName = "amazon-cloudwatch-agent-target-allocator"
+	clientCommonName = "amazon-cloudwatch-agent"
+)
+
+var secretLabels = map[string]string{
+	"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+	"app.kubernetes.io/component":  "amazon-cloudwatch-agent-target-allocator",
+}
+
+// Manager provisions the CA, the serving certificate of the target allocator and the client certificate of the
+// collectors in the secrets mounted by the target allocator, and rotates them before they expire. Secrets which
+// already exist without the operator labels are left untouched, so that certificates provided by cert-manager or
+// Helm keep working.
+type Manager struct {
+	client client.Client
+	// apiReader looks up the secrets missing from the cache of the client, which only holds those managed by the
+	// operator.
+	apiReader client.Reader
+	log       logr.Logger
+	now       func() time.Time
+}
+
+// NewManager creates a new certificate manager.
+func NewManager(cl client.Client, apiReader client.Reader, log logr.Logger) *Manager {
+	return &Manager{client: cl, apiReader: apiReader, log: log, now: time.Now}
+}
+
+// Reconcile provisions the certificates in the namespace, making sure the serving certificate is valid for the given
+// DNS names. It returns when the earliest certificate is due for renewal, or the zero time when the operator doesn't
+// manage any.
+func (m *Manager) Reconcile(ctx context.Context, namespace string, dnsNames []string) (time.Time, error) {
+	ca, bundle, err := m.reconcileCA(ctx, namespace)
+	if err != nil || ca == nil {
+		return time.Time{}, err
+	}
+	renewal := RenewalTime(ca.Cert)
+
+	serverRenewal, err := m.reconcileCertificate(ctx, namespace, naming.TAServerCertSecret(), ca, bundle, serverCommonName, dnsNames, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return time.Time{}, err
+	}
+	clientRenewal, err := m.reconcileCertificate(ctx, namespace, naming.TAClientCertSecret(), ca, bundle, clientCommonName, nil, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, t := range []time.Time{serverRenewal, clientRenewal} {
+		if !t.IsZero() && t.Before(renewal) {
+			renewal = t
+		}
+	}
+	return renewal, nil
+}
+
+// reconcileCA returns the CA along with the bundle of the CAs to trust, generating the CA when it is missing and
+// rotating it when it is due for renewal. It returns a nil CA when the secret isn't managed by the operator.
+func (m *Manager) reconcileCA(ctx context.Context, namespace string) (*KeyPair, []byte, error) {
+	secret, found, err := m.getManagedSecret(ctx, namespace, naming.TACASecret())
+	if err != nil || secret == nil {
+		return nil, nil, err
+	}
+
+	now := m.now()
+	var ca *KeyPair
+	var previous []byte
+	if found {
+		ca, err = ParseKeyPair(secret.Data[CACertKey], secret.Data[CAKeyKey])
+		if err != nil {
+			m.log.Info("the target allocator CA can't be parsed, generating a new one", "error", err.Error())
+		}
+		if prev, parseErr := ParseCertificate(secret.Data[PreviousCACertKey]); parseErr == nil && now.Before(prev.NotAfter) {
+			previous = secret.Data[PreviousCACertKey]
+		}
+	}
+	if ca == nil || !now.Before(RenewalTime(ca.Cert)) {
+		if ca != nil {
+			// the replaced CA is trusted until it expires, so that the certificates it issued remain valid while
+			// they are reissued
+			previous = ca.CertPEM
+		}
+		ca, err = NewCA(caCommonName, CAValidity, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		m.log.Info("issued the target allocator CA", "namespace", namespace, "expiry", ca.Cert.NotAfter)
+	}
+
+	data := map[string][]byte{CACertKey: ca.CertPEM, CAKeyKey: ca.KeyPEM}
+	if previous != nil {
+		data[PreviousCACertKey] = previous
+	}
+	if err := m.writeSecret(ctx, secret, found, corev1.SecretTypeOpaque, data); err != nil {
+		return nil, nil, err
+	}
+	operatormetrics.RecordCertificateExpiry(namespace, secret.Name, ca.Cert.NotAfter)
+	return ca, Bundle(ca.CertPEM, previous), nil
+}
+
+// reconcileCertificate issues the certificate stored in the secret when it is missing, due for renewal, not issued by
+// the CA or not valid for all the DNS names. The DNS names of the existing certificate are kept, since the target
+// allocators of all the instances of the namespace share the secret. It returns when the certificate is due for
+// renewal, or the zero time when the secret isn't managed by the operator.
+func (m *Manager) reconcileCertificate(ctx context.Context, namespace, name string, ca *KeyPair, bundle []byte,
+	commonName string, dnsNames []string, usage x509.ExtKeyUsage) (time.Time, error) {
+	secret, found, err := m.getManagedSecret(ctx, namespace, name)
+	if err != nil || secret == nil {
+		return time.Time{}, err
+	}
+
+	var cert *KeyPair
+	if found {
+		cert, _ = ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	}
+	if cert != nil {
+		for _, dnsName := range cert.Cert.DNSNames {
+			if !slices.Contains(dnsNames, dnsName) {
+				dnsNames = append(dnsNames, dnsName)
+			}
+		}
+	}
+	if m.needsIssuing(cert, ca, dnsNames) {
+		cert, err = ca.Issue(commonName, dnsNames, usage, CertificateValidity, m.now())
+		if err != nil {
+			return time.Time{}, err
+		}
+		m.log.Info("issued the target allocator certificate", "namespace", namespace, "secret", name, "expiry", cert.Cert.NotAfter)
+	}
+
+	data := map[string][]byte{
+		corev1.TLSCertKey:       cert.CertPEM,
+		corev1.TLSPrivateKeyKey: cert.KeyPEM,
+		CACertKey:               bundle,
+	}
+	if err := m.writeSecret(ctx, secret, found, corev1.SecretTypeTLS, data); err != nil {
+		return time.Time{}, err
+	}
+	operatormetrics.RecordCertificateExpiry(namespace, name, cert.Cert.NotAfter)
+	return RenewalTime(cert.Cert), nil
+}
+
+func (m *Manager) needsIssuing(cert, ca *KeyPair, dnsNames []string) bool {
+	if cert == nil || !m.now().Before(RenewalTime(cert.Cert)) {
+		return true
+	}
+	if cert.Cert.CheckSignatureFrom(ca.Cert) != nil {
+		return true
+	}
+	return len(dnsNames) != len(cert.Cert.DNSNames)
+}
+
+// getManagedSecret returns the secret and whether it exists. The returned secret is nil when it exists without the
+// operator labels.
+func (m *Manager) getManagedSecret(ctx context.Context, namespace, name string) (*corev1.Secret, bool, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: name}
+	err := m.client.Get(ctx, key, secret)
+	if apierrors.IsNotFound(err) {
+		// secrets without the operator labels aren't cached
+		err = m.apiReader.Get(ctx, key, secret)
+	}
+	if apierrors.IsNotFound(err) {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: maps.Clone(secretLabels)}}, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed to get secret %s: %w", name, err)
+	}
+	for k, v := range secretLabels {
+		if secret.Labels[k] != v {
+			m.log.V(1).Info("the secret isn't managed by the operator, skipping", "namespace", namespace, "secret", name)
+			return nil, true, nil
+		}
+	}
+	return secret, true, nil
+}
+
+func (m *Manager) writeSecret(ctx context.Context, secret *corev1.Secret, found bool, secretType corev1.SecretType, data map[string][]byte) error {
+	if !found {
+		secret.Type = secretType
+		secret.Data = data
+		if err := m.client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("failed to create secret %s: %w", secret.Name, err)
+		}
+		return nil
+	}
+	if secretDataEqual(secret.Data, data) {
+		return nil
+	}
+	secret.Data = data
+	if err := m.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update secret %s: %w", secret.Name, err)
+	}
+	return nil
+}
+
+func secretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !bytes.Equal(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
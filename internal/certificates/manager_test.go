@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func newTestManager(objs ...client.Object) *Manager {
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	return NewManager(c, c, logf.Log.WithName("unit-tests"))
+}
+
+func getSecret(t *testing.T, m *Manager, name string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, m.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, secret))
+	return secret
+}
+
+func getKeyPair(t *testing.T, m *Manager, name string) *KeyPair {
+	secret := getSecret(t, m, name)
+	keyPair, err := ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	require.NoError(t, err)
+	return keyPair
+}
+
+func TestReconcileProvisionsCertificates(t *testing.T) {
+	m := newTestManager()
+	renewal, err := m.Reconcile(context.Background(), "default", []string{"agent-target-allocator.default.svc"})
+	require.NoError(t, err)
+
+	caSecret := getSecret(t, m, naming.TACASecret())
+	ca, err := ParseKeyPair(caSecret.Data[CACertKey], caSecret.Data[CAKeyKey])
+	require.NoError(t, err)
+
+	server := getKeyPair(t, m, naming.TAServerCertSecret())
+	assert.NoError(t, server.Cert.CheckSignatureFrom(ca.Cert))
+	assert.Equal(t, []string{"agent-target-allocator.default.svc"}, server.Cert.DNSNames)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, server.Cert.ExtKeyUsage)
+	assert.Equal(t, corev1.SecretTypeTLS, getSecret(t, m, naming.TAServerCertSecret()).Type)
+
+	clientSecret := getSecret(t, m, naming.TAClientCertSecret())
+	clientCert := getKeyPair(t, m, naming.TAClientCertSecret())
+	assert.NoError(t, clientCert.Cert.CheckSignatureFrom(ca.Cert))
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, clientCert.Cert.ExtKeyUsage)
+	assert.Equal(t, ca.CertPEM, clientSecret.Data[CACertKey])
+
+	assert.Equal(t, RenewalTime(server.Cert), renewal)
+}
+
+func TestReconcileKeepsValidCertificates(t *testing.T) {
+	m := newTestManager()
+	_, err := m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+	server := getKeyPair(t, m, naming.TAServerCertSecret())
+
+	_, err = m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+	assert.Equal(t, server.CertPEM, getKeyPair(t, m, naming.TAServerCertSecret()).CertPEM)
+
+	// the serving certificate is reissued for the target allocator of another instance, keeping the existing names
+	_, err = m.Reconcile(context.Background(), "default", []string{"b.default.svc"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a.default.svc", "b.default.svc"}, getKeyPair(t, m, naming.TAServerCertSecret()).Cert.DNSNames)
+}
+
+func TestReconcileRotatesCertificates(t *testing.T) {
+	m := newTestManager()
+	_, err := m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+	oldCA := getSecret(t, m, naming.TACASecret()).Data[CACertKey]
+	server := getKeyPair(t, m, naming.TAServerCertSecret())
+
+	// past the renewal of the certificates, the CA is still valid
+	m.now = func() time.Time { return RenewalTime(server.Cert).Add(time.Minute) }
+	_, err = m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+	assert.NotEqual(t, server.CertPEM, getKeyPair(t, m, naming.TAServerCertSecret()).CertPEM)
+	assert.Equal(t, oldCA, getSecret(t, m, naming.TACASecret()).Data[CACertKey])
+
+	// past the renewal of the CA, the previous CA stays trusted
+	ca, err := ParseCertificate(oldCA)
+	require.NoError(t, err)
+	m.now = func() time.Time { return RenewalTime(ca).Add(time.Minute) }
+	_, err = m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+	caSecret := getSecret(t, m, naming.TACASecret())
+	assert.NotEqual(t, oldCA, caSecret.Data[CACertKey])
+	assert.Equal(t, oldCA, caSecret.Data[PreviousCACertKey])
+	assert.Equal(t, Bundle(caSecret.Data[CACertKey], oldCA), getSecret(t, m, naming.TAClientCertSecret()).Data[CACertKey])
+
+	newCA, err := ParseCertificate(caSecret.Data[CACertKey])
+	require.NoError(t, err)
+	assert.NoError(t, getKeyPair(t, m, naming.TAServerCertSecret()).Cert.CheckSignatureFrom(newCA))
+}
+
+func TestReconcileSkipsUnmanagedSecrets(t *testing.T) {
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: naming.TAServerCertSecret(), Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	m := newTestManager(unmanaged)
+	_, err := m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+
+	assert.Equal(t, unmanaged.Data, getSecret(t, m, naming.TAServerCertSecret()).Data)
+	getKeyPair(t, m, naming.TAClientCertSecret())
+}
+
+func TestReconcileSkipsUncachedSecrets(t *testing.T) {
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: naming.TAServerCertSecret(), Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	// the cache only holds the secrets managed by the operator
+	m := newTestManager()
+	m.apiReader = fake.NewClientBuilder().WithObjects(unmanaged).Build()
+	_, err := m.Reconcile(context.Background(), "default", []string{"a.default.svc"})
+	require.NoError(t, err)
+
+	err = m.client.Get(context.Background(), client.ObjectKeyFromObject(unmanaged), &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err), "the unmanaged secret must not be replaced")
+	getKeyPair(t, m, naming.TAClientCertSecret())
+}
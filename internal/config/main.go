@@ -37,6 +37,11 @@ type Config struct {
 	targetAllocatorConfigMapEntry       string
 	prometheusConfigMapEntry            string
 	labelsFilter                        []string
+	gatewayAPIAvailable                 bool
+	kedaAvailable                       bool
+	nativeSidecarSupported              bool
+	manageTACertificates                bool
+	restartStaleSidecars                bool
 }
 
 // New constructs a new configuration based on the given options.
@@ -72,6 +77,11 @@ func New(opts ...Option) Config {
 		targetAllocatorConfigMapEntry:       o.targetAllocatorConfigMapEntry,
 		prometheusConfigMapEntry:            o.prometheusConfigMapEntry,
 		labelsFilter:                        o.labelsFilter,
+		gatewayAPIAvailable:                 o.gatewayAPIAvailable,
+		kedaAvailable:                       o.kedaAvailable,
+		nativeSidecarSupported:              o.nativeSidecarSupported,
+		manageTACertificates:                o.manageTACertificates,
+		restartStaleSidecars:                o.restartStaleSidecars,
 	}
 }
 
@@ -135,6 +145,34 @@ func (c *Config) NeuronMonitorImage() string {
 	return c.neuronMonitorImage
 }
 
+// GatewayAPIAvailable returns whether the cluster serves the Gateway API HTTPRoute and GRPCRoute resources.
+func (c *Config) GatewayAPIAvailable() bool {
+	return c.gatewayAPIAvailable
+}
+
+// KEDAAvailable returns whether the cluster serves the KEDA ScaledObject resource.
+func (c *Config) KEDAAvailable() bool {
+	return c.kedaAvailable
+}
+
+// NativeSidecarSupported returns whether the API server supports native sidecar containers, init containers with
+// restartPolicy Always.
+func (c *Config) NativeSidecarSupported() bool {
+	return c.nativeSidecarSupported
+}
+
+// ManageTargetAllocatorCertificates returns whether the operator provisions and rotates the TLS certificates of the
+// target allocator.
+func (c *Config) ManageTargetAllocatorCertificates() bool {
+	return c.manageTACertificates
+}
+
+// RestartStaleSidecars returns whether the operator restarts the workloads whose pods run a sidecar injected from an
+// AmazonCloudWatchAgent which was deleted or changed since.
+func (c *Config) RestartStaleSidecars() bool {
+	return c.restartStaleSidecars
+}
+
 // TargetAllocatorImage represents the flag to override the OpenTelemetry TargetAllocator container image.
 func (c *Config) TargetAllocatorImage() string {
 	return c.targetAllocatorImage
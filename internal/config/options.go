@@ -34,6 +34,11 @@ type options struct {
 	targetAllocatorConfigMapEntry       string
 	prometheusConfigMapEntry            string
 	labelsFilter                        []string
+	gatewayAPIAvailable                 bool
+	kedaAvailable                       bool
+	nativeSidecarSupported              bool
+	manageTACertificates                bool
+	restartStaleSidecars                bool
 }
 
 func WithCollectorImage(s string) Option {
@@ -132,6 +137,42 @@ func WithTargetAllocatorImage(s string) Option {
 	}
 }
 
+// WithGatewayAPIAvailable tells whether the cluster serves the Gateway API routes.
+func WithGatewayAPIAvailable(available bool) Option {
+	return func(o *options) {
+		o.gatewayAPIAvailable = available
+	}
+}
+
+// WithManageTargetAllocatorCertificates tells whether the operator provisions the TLS certificates of the target
+// allocator.
+func WithManageTargetAllocatorCertificates(manage bool) Option {
+	return func(o *options) {
+		o.manageTACertificates = manage
+	}
+}
+
+// WithRestartStaleSidecars tells whether the operator restarts the workloads whose pods run a stale sidecar.
+func WithRestartStaleSidecars(restart bool) Option {
+	return func(o *options) {
+		o.restartStaleSidecars = restart
+	}
+}
+
+// WithKEDAAvailable tells whether the cluster serves the KEDA ScaledObjects.
+func WithKEDAAvailable(available bool) Option {
+	return func(o *options) {
+		o.kedaAvailable = available
+	}
+}
+
+// WithNativeSidecarSupported tells whether the API server supports native sidecar containers.
+func WithNativeSidecarSupported(supported bool) Option {
+	return func(o *options) {
+		o.nativeSidecarSupported = supported
+	}
+}
+
 func WithLabelFilters(labelFilters []string) Option {
 	return func(o *options) {
 
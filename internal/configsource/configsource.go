@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package configsource resolves the agent configurations read from ConfigMaps and Secrets through spec.configFrom
+// and spec.otelConfigFrom, and merges the structured configuration of spec.agent.
+package configsource
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// Resolve sets spec.config and spec.otelConfig of the instance to the content referenced by spec.configFrom and
+// spec.otelConfigFrom, and merges spec.agent into spec.config, so that the manifests are built from the resolved
+// content.
+func Resolve(ctx context.Context, c client.Reader, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	return resolve(instance, func(source *v1alpha1.ConfigSource, _ bool) (string, error) {
+		return Get(ctx, c, instance.Namespace, source)
+	})
+}
+
+// ResolveGenerated resolves the instance like Resolve, except that the configurations read from Secrets are taken
+// from the Secret the operator generates for the instance. Only the Secrets managed by the operator are cached, so
+// resolving an instance on pod admission doesn't call the API server. The generated Secret holds the configuration
+// of the last reconciliation of the instance.
+func ResolveGenerated(ctx context.Context, c client.Reader, cfg config.Config, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	var generated *corev1.Secret
+	return resolve(instance, func(source *v1alpha1.ConfigSource, otel bool) (string, error) {
+		if !source.IsSecret() {
+			return Get(ctx, c, instance.Namespace, source)
+		}
+		if generated == nil {
+			secret := &corev1.Secret{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: naming.ConfigSecret(instance.Name)}, secret); err != nil {
+				return "", fmt.Errorf("failed to get the generated configuration: %w", err)
+			}
+			generated = secret
+		}
+		if otel {
+			return string(generated.Data[cfg.OtelCollectorConfigMapEntry()]), nil
+		}
+		return string(generated.Data[cfg.CollectorConfigMapEntry()]), nil
+	})
+}
+
+// resolve resolves the instance, get returning the content of the source of spec.configFrom, or of
+// spec.otelConfigFrom when otel is set.
+func resolve(instance *v1alpha1.AmazonCloudWatchAgent, get func(source *v1alpha1.ConfigSource, otel bool) (string, error)) error {
+	if instance.Spec.ConfigFrom != nil {
+		content, err := get(instance.Spec.ConfigFrom, false)
+		if err != nil {
+			return fmt.Errorf("failed to resolve configFrom: %w", err)
+		}
+		instance.Spec.Config = content
+	}
+	if instance.Spec.OtelConfigFrom != nil {
+		content, err := get(instance.Spec.OtelConfigFrom, true)
+		if err != nil {
+			return fmt.Errorf("failed to resolve otelConfigFrom: %w", err)
+		}
+		instance.Spec.OtelConfig = content
+	}
+	if instance.Spec.Agent != nil {
+		merged, err := instance.Spec.Agent.MergeInto(instance.Spec.Config)
+		if err != nil {
+			return fmt.Errorf("failed to merge agent: %w", err)
+		}
+		instance.Spec.Config = merged
+	}
+	return nil
+}
+
+// Get returns the content of the ConfigMap or Secret key referenced by the source. A missing optional reference
+// resolves to an empty content.
+func Get(ctx context.Context, c client.Reader, namespace string, source *v1alpha1.ConfigSource) (string, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, cm); err != nil {
+			if apierrors.IsNotFound(err) && isOptional(ref.Optional) {
+				return "", nil
+			}
+			return "", err
+		}
+		if content, ok := cm.Data[ref.Key]; ok {
+			return content, nil
+		}
+		if content, ok := cm.BinaryData[ref.Key]; ok {
+			return string(content), nil
+		}
+		if isOptional(ref.Optional) {
+			return "", nil
+		}
+		return "", fmt.Errorf("key %s not found in ConfigMap %s/%s", ref.Key, namespace, ref.Name)
+
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) && isOptional(ref.Optional) {
+				return "", nil
+			}
+			return "", err
+		}
+		if content, ok := secret.Data[ref.Key]; ok {
+			return string(content), nil
+		}
+		if isOptional(ref.Optional) {
+			return "", nil
+		}
+		return "", fmt.Errorf("key %s not found in Secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return "", nil
+}
+
+// ReferencesConfigMap returns whether the instance reads a configuration from the ConfigMap.
+func ReferencesConfigMap(instance v1alpha1.AmazonCloudWatchAgent, name string) bool {
+	for _, source := range []*v1alpha1.ConfigSource{instance.Spec.ConfigFrom, instance.Spec.OtelConfigFrom} {
+		if source != nil && source.ConfigMapKeyRef != nil && source.ConfigMapKeyRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
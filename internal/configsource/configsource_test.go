@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package configsource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestResolve(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "otel", Namespace: "amazon-cloudwatch"},
+			Data:       map[string]string{"config": "receivers: {}"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Data:       map[string][]byte{"config": []byte(`{"agent":{}}`)},
+		},
+	).Build()
+	instance := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ConfigFrom: &v1alpha1.ConfigSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+			},
+			OtelConfigFrom: &v1alpha1.ConfigSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "otel"}, Key: "config"},
+			},
+		},
+	}
+
+	require.NoError(t, Resolve(context.Background(), c, &instance))
+	assert.Equal(t, `{"agent":{}}`, instance.Spec.Config)
+	assert.Equal(t, "receivers: {}", instance.Spec.OtelConfig)
+	assert.True(t, ReferencesConfigMap(instance, "otel"))
+	assert.False(t, ReferencesConfigMap(instance, "agent"))
+}
+
+func TestResolveGenerated(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "otel", Namespace: "amazon-cloudwatch"},
+			Data:       map[string]string{"config": "receivers: {}"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "amazon-cloudwatch"},
+			Data:       map[string][]byte{"cwagentconfig.json": []byte(`{"agent":{}}`)},
+		},
+	).Build()
+	instance := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			// the referenced Secret isn't cached, the generated one is read instead
+			ConfigFrom: &v1alpha1.ConfigSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+			},
+			OtelConfigFrom: &v1alpha1.ConfigSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "otel"}, Key: "config"},
+			},
+		},
+	}
+
+	require.NoError(t, ResolveGenerated(context.Background(), c, config.New(), &instance))
+	assert.Equal(t, `{"agent":{}}`, instance.Spec.Config)
+	assert.Equal(t, "receivers: {}", instance.Spec.OtelConfig)
+
+	notGenerated := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "amazon-cloudwatch"},
+		Spec:       instance.Spec,
+	}
+	assert.Error(t, ResolveGenerated(context.Background(), c, config.New(), &notGenerated))
+}
+
+func TestResolveAgent(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Data:       map[string]string{"config": `{"agent":{"region":"us-west-2"}}`},
+	}).Build()
+	instance := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ConfigFrom: &v1alpha1.ConfigSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+			},
+			Agent: &v1alpha1.AgentConfig{
+				Agent: &v1alpha1.AnyConfig{Object: map[string]interface{}{"debug": true}},
+			},
+		},
+	}
+
+	require.NoError(t, Resolve(context.Background(), c, &instance))
+	assert.JSONEq(t, `{"agent":{"region":"us-west-2","debug":true}}`, instance.Spec.Config)
+}
+
+func TestGet(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Data:       map[string]string{"config": "{}"},
+	}).Build()
+
+	for _, tt := range []struct {
+		name    string
+		ref     corev1.ConfigMapKeySelector
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "existing key",
+			ref:  corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+			want: "{}",
+		},
+		{
+			name:    "missing key",
+			ref:     corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "other"},
+			wantErr: true,
+		},
+		{
+			name:    "missing config map",
+			ref:     corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "config"},
+			wantErr: true,
+		},
+		{
+			name: "missing optional config map",
+			ref:  corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "config", Optional: ptr.To(true)},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := tt.ref
+			content, err := Get(context.Background(), c, "amazon-cloudwatch", &v1alpha1.ConfigSource{ConfigMapKeyRef: &ref})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, content)
+		})
+	}
+}
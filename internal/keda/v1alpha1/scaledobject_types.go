@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScaledObject is the KEDA resource scaling a workload on the metrics of its triggers. Only the fields set by the
+// operator are defined.
+// +kubebuilder:object:root=true
+type ScaledObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScaledObjectSpec `json:"spec"`
+}
+
+// ScaledObjectSpec is the specification of a ScaledObject.
+type ScaledObjectSpec struct {
+	ScaleTargetRef  *ScaleTarget    `json:"scaleTargetRef"`
+	PollingInterval *int32          `json:"pollingInterval,omitempty"`
+	MinReplicaCount *int32          `json:"minReplicaCount,omitempty"`
+	MaxReplicaCount *int32          `json:"maxReplicaCount,omitempty"`
+	Advanced        *AdvancedConfig `json:"advanced,omitempty"`
+	Triggers        []ScaleTriggers `json:"triggers"`
+}
+
+// ScaleTarget references the scaled resource, which must serve the scale subresource.
+type ScaleTarget struct {
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+}
+
+// AdvancedConfig tunes the HorizontalPodAutoscaler KEDA creates for the ScaledObject.
+type AdvancedConfig struct {
+	HorizontalPodAutoscalerConfig *HorizontalPodAutoscalerConfig `json:"horizontalPodAutoscalerConfig,omitempty"`
+}
+
+// HorizontalPodAutoscalerConfig holds the fields of the HorizontalPodAutoscaler KEDA creates.
+type HorizontalPodAutoscalerConfig struct {
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// ScaleTriggers is a scaler and the metric it returns.
+type ScaleTriggers struct {
+	Type       string                         `json:"type"`
+	Name       string                         `json:"name,omitempty"`
+	Metadata   map[string]string              `json:"metadata"`
+	MetricType autoscalingv2.MetricTargetType `json:"metricType,omitempty"`
+}
+
+// ScaledObjectList is a list of ScaledObjects.
+// +kubebuilder:object:root=true
+type ScaledObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ScaledObject `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScaledObject{}, &ScaledObjectList{})
+}
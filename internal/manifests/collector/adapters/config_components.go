@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// supportedComponent is an OpenTelemetry component included in the CloudWatch agent distribution.
+type supportedComponent struct {
+	Name string `json:"name"`
+	// Since is the first agent version including the component, empty when all the supported versions include it.
+	Since string `json:"since,omitempty"`
+}
+
+// agentComponents is the manifest of the OpenTelemetry components, by section, that the CloudWatch agent distribution
+// includes. Its $comment holds the agent version it describes, which must match the cloudwatch-agent entry of
+// versions.txt.
+//
+//go:embed components/cloudwatch-agent.json
+var agentComponents []byte
+
+// supportedComponents are the components of the manifest, by type.
+var supportedComponents = mustParseComponents(agentComponents)
+
+func mustParseComponents(manifest []byte) map[ComponentType][]supportedComponent {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(manifest, &sections); err != nil {
+		panic(fmt.Sprintf("invalid cloudwatch agent component manifest: %v", err))
+	}
+	components := map[ComponentType][]supportedComponent{}
+	for componentType, section := range componentSections {
+		if sections[section] == nil {
+			continue
+		}
+		var list []supportedComponent
+		if err := json.Unmarshal(sections[section], &list); err != nil {
+			panic(fmt.Sprintf("invalid %s of the cloudwatch agent component manifest: %v", section, err))
+		}
+		components[componentType] = list
+	}
+	return components
+}
+
+var agentVersionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
+
+// SupportedComponents returns the names of the components of the type included in the given agent version. An
+// unknown version, like the one of an image pinned by digest, is assumed to be the latest one.
+func SupportedComponents(componentType ComponentType, agentVersion string) []string {
+	var names []string
+	for _, component := range supportedComponents[componentType] {
+		if component.Since == "" || !versionLess(agentVersion, component.Since) {
+			names = append(names, component.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AgentVersionFromImage returns the tag of the agent image, which is its version, or an empty string for an image
+// without tag or pinned by digest.
+func AgentVersionFromImage(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// versionLess returns whether the agent version a is older than b, and false when a can't be parsed.
+func versionLess(a, b string) bool {
+	va, vb := agentVersionRegex.FindStringSubmatch(a), agentVersionRegex.FindStringSubmatch(b)
+	if va == nil || vb == nil {
+		return false
+	}
+	for i := 1; i < len(va); i++ {
+		na, _ := strconv.Atoi(va[i])
+		nb, _ := strconv.Atoi(vb[i])
+		if na != nb {
+			return na < nb
+		}
+	}
+	return false
+}
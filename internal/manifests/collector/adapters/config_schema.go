@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// agentConfigSchema is the JSON schema of the CloudWatch agent configuration. Its $comment holds the agent version
+// it describes, which must match the cloudwatch-agent entry of versions.txt.
+//
+//go:embed schema/cloudwatch-agent.json
+var agentConfigSchema string
+
+const agentConfigSchemaURL = "cloudwatch-agent.json"
+
+var compileAgentConfigSchema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.ExtractAnnotations = true
+	if err := compiler.AddResource(agentConfigSchemaURL, strings.NewReader(agentConfigSchema)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(agentConfigSchemaURL)
+})
+
+// ValidateConfigSchema validates the CloudWatch agent JSON configuration against the agent's JSON schema. The
+// returned error lists the path of every invalid field, and the warnings the deprecated keys set by the configuration.
+func ValidateConfigSchema(configStr string) ([]string, error) {
+	schema, err := compileAgentConfigSchema()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't compile the cloudwatch agent json schema: %w", err)
+	}
+	var config interface{}
+	decoder := json.NewDecoder(strings.NewReader(configStr))
+	decoder.UseNumber()
+	if err = decoder.Decode(&config); err != nil {
+		return nil, ErrInvalidJSON
+	}
+
+	if err = schema.Validate(config); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return nil, errors.New(strings.Join(schemaViolations(validationErr), "; "))
+	}
+
+	var warnings []string
+	for _, key := range deprecatedKeys(schema, config, "") {
+		warnings = append(warnings, fmt.Sprintf("the config key '%s' is deprecated: %s", key.path, key.description))
+	}
+	return warnings, nil
+}
+
+// schemaViolations returns the leaf errors of the validation error, prefixed with the path of the invalid field.
+func schemaViolations(err *jsonschema.ValidationError) []string {
+	seen := map[string]bool{}
+	var violations []string
+	var walk func(*jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			violation := fmt.Sprintf("%s: %s", fieldPath(e.InstanceLocation), e.Message)
+			if !seen[violation] {
+				seen[violation] = true
+				violations = append(violations, violation)
+			}
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(err)
+	sort.Strings(violations)
+	return violations
+}
+
+// fieldPath turns the JSON pointer of a field into a dotted path.
+func fieldPath(pointer string) string {
+	if pointer == "" {
+		return "(root)"
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+type deprecatedKey struct {
+	path        string
+	description string
+}
+
+// deprecatedKeys walks the configuration along the schema and returns the keys set by the configuration that the
+// schema marks as deprecated.
+func deprecatedKeys(schema *jsonschema.Schema, value interface{}, path string) []deprecatedKey {
+	for schema != nil && schema.Ref != nil {
+		schema = schema.Ref
+	}
+	object, ok := value.(map[string]interface{})
+	if schema == nil || !ok {
+		return nil
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []deprecatedKey
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			continue
+		}
+		propertyPath := name
+		if path != "" {
+			propertyPath = path + "." + name
+		}
+		if property.Deprecated {
+			keys = append(keys, deprecatedKey{path: propertyPath, description: property.Description})
+		}
+		keys = append(keys, deprecatedKeys(property, object[name], propertyPath)...)
+	}
+	return keys
+}
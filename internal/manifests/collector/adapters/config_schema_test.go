@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigSchema(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           string
+		expectedErr      []string
+		expectedWarnings []string
+	}{
+		{
+			name:   "empty config",
+			config: `{}`,
+		},
+		{
+			name: "valid config",
+			config: `{
+				"agent": {"region": "us-west-2", "debug": true, "metrics_collection_interval": 60},
+				"metrics": {"metrics_collected": {"statsd": {"service_address": ":8125"}, "collectd": {"service_address": "udp://127.0.0.1:25826"}, "cpu": {}}},
+				"logs": {"metrics_collected": {"kubernetes": {"enhanced_container_insights": true}, "application_signals": {}}},
+				"traces": {"traces_collected": {"xray": {"bind_address": "0.0.0.0:2000", "tcp_proxy": {"bind_address": "0.0.0.0:2000"}}, "otlp": {"grpc_endpoint": "0.0.0.0:4317"}}}
+			}`,
+		},
+		{
+			name:        "invalid json",
+			config:      `{"agent":`,
+			expectedErr: []string{ErrInvalidJSON.Error()},
+		},
+		{
+			name:        "unknown section",
+			config:      `{"agent": {}, "trace": {}}`,
+			expectedErr: []string{"(root): additionalProperties 'trace' not allowed"},
+		},
+		{
+			name:        "unknown key in a section",
+			config:      `{"logs": {"metrics_collected": {"kubernetes": {}, "emf_processor": {}}}}`,
+			expectedErr: []string{"logs.metrics_collected: additionalProperties 'emf_processor' not allowed"},
+		},
+		{
+			name:   "agent keys",
+			config: `{"agent": {"quiet": true, "flush_interval": 5}, "logs": {"metrics_collected": {"prometheus": {"emf_processor": {}}}}}`,
+		},
+		{
+			name:   "addresses with an optional host or scheme",
+			config: `{"metrics": {"metrics_collected": {"collectd": {"service_address": ":25936"}, "statsd": {"service_address": "udp://127.0.0.1:8125"}}}}`,
+		},
+		{
+			name:   "wrong types",
+			config: `{"agent": {"debug": "true", "metrics_collection_interval": "60s"}}`,
+			expectedErr: []string{
+				"agent.debug: expected boolean, but got string",
+				"agent.metrics_collection_interval: expected integer, but got string",
+			},
+		},
+		{
+			name:        "invalid endpoint",
+			config:      `{"traces": {"traces_collected": {"otlp": {"grpc_endpoint": "0.0.0.0"}}}}`,
+			expectedErr: []string{"traces.traces_collected.otlp.grpc_endpoint: does not match pattern"},
+		},
+		{
+			name:        "invalid endpoint override",
+			config:      `{"logs": {"endpoint_override": "ftp://logs.us-west-2.amazonaws.com"}}`,
+			expectedErr: []string{"logs.endpoint_override: does not match pattern"},
+		},
+		{
+			name:   "deprecated keys",
+			config: `{"logs": {"metrics_collected": {"app_signals": {}}}, "traces": {"traces_collected": {"app_signals": {}}}}`,
+			expectedWarnings: []string{
+				"the config key 'logs.metrics_collected.app_signals' is deprecated: use application_signals instead",
+				"the config key 'traces.traces_collected.app_signals' is deprecated: use application_signals instead",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := ValidateConfigSchema(tt.config)
+			if len(tt.expectedErr) > 0 {
+				require.Error(t, err)
+				for _, expected := range tt.expectedErr {
+					assert.Contains(t, err.Error(), expected)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestValidateConfigSchemaTestResources(t *testing.T) {
+	files, err := filepath.Glob("../test-resources/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			config, err := os.ReadFile(file)
+			require.NoError(t, err)
+			_, err = ValidateConfigSchema(string(config))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfigSchemaMatchesAgentVersion(t *testing.T) {
+	versions, err := os.ReadFile("../../../../versions.txt")
+	require.NoError(t, err)
+	var agentVersion string
+	for _, line := range strings.Split(string(versions), "\n") {
+		if strings.HasPrefix(line, "cloudwatch-agent=") {
+			agentVersion = strings.TrimSpace(line)
+		}
+	}
+	require.NotEmpty(t, agentVersion)
+
+	var schema struct {
+		Comment string `json:"$comment"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(agentConfigSchema), &schema))
+	assert.Equal(t, agentVersion, schema.Comment, "the agent config schema must be updated along with the agent version")
+}
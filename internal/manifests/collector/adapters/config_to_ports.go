@@ -22,10 +22,14 @@ type ComponentType int
 
 const (
 	ComponentTypeReceiver ComponentType = iota
+	ComponentTypeExporter
+	ComponentTypeProcessor
+	ComponentTypeExtension
+	ComponentTypeConnector
 )
 
 func (c ComponentType) String() string {
-	return [...]string{"receiver", "exporter"}[c]
+	return [...]string{"receiver", "exporter", "processor", "extension", "connector"}[c]
 }
 
 // ConfigToMetricsPort gets the port number for the metrics endpoint from the collector config if it has been set.
@@ -3,7 +3,113 @@
 
 package adapters
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var componentSections = map[ComponentType]string{
+	ComponentTypeReceiver:  "receivers",
+	ComponentTypeProcessor: "processors",
+	ComponentTypeExporter:  "exporters",
+	ComponentTypeExtension: "extensions",
+	ComponentTypeConnector: "connectors",
+}
+
+// ValidateComponents checks that every component referenced by the service of the OTel configuration is defined, and
+// that every defined component is included in the given CloudWatch agent version.
+func ValidateComponents(config map[interface{}]interface{}, agentVersion string) error {
+	defined := map[ComponentType]map[string]bool{}
+	for componentType, section := range componentSections {
+		defined[componentType] = map[string]bool{}
+		if config[section] == nil {
+			continue
+		}
+		components, ok := config[section].(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("the %s section must be a map", section)
+		}
+		for id := range components {
+			defined[componentType][fmt.Sprint(id)] = true
+		}
+	}
+
+	if err := validateReferences(config, defined); err != nil {
+		return err
+	}
+
+	for _, componentType := range []ComponentType{ComponentTypeReceiver, ComponentTypeProcessor, ComponentTypeExporter, ComponentTypeExtension, ComponentTypeConnector} {
+		supported := SupportedComponents(componentType, agentVersion)
+		for _, id := range sortedKeys(defined[componentType]) {
+			name, _, _ := strings.Cut(id, "/")
+			if !slices.Contains(supported, name) {
+				return unsupportedComponentError(componentType, name, agentVersion, supported)
+			}
+		}
+	}
+	return nil
+}
+
+// validateReferences checks that the components referenced by the pipelines and the extensions of the service are
+// defined. Connectors are referenced as receivers and exporters.
+func validateReferences(config map[interface{}]interface{}, defined map[ComponentType]map[string]bool) error {
+	service, _ := config["service"].(map[interface{}]interface{})
+	for _, id := range toStrings(service["extensions"]) {
+		if !defined[ComponentTypeExtension][id] {
+			return fmt.Errorf("the service references the extension %q, which is not defined", id)
+		}
+	}
+	pipelines, _ := service["pipelines"].(map[interface{}]interface{})
+	names := make([]string, 0, len(pipelines))
+	for name := range pipelines {
+		names = append(names, fmt.Sprint(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pipeline, _ := pipelines[name].(map[interface{}]interface{})
+		for _, componentType := range []ComponentType{ComponentTypeReceiver, ComponentTypeProcessor, ComponentTypeExporter} {
+			for _, id := range toStrings(pipeline[componentSections[componentType]]) {
+				connector := componentType != ComponentTypeProcessor && defined[ComponentTypeConnector][id]
+				if !defined[componentType][id] && !connector {
+					return fmt.Errorf("the pipeline %q references the %s %q, which is not defined", name, componentType, id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func unsupportedComponentError(componentType ComponentType, name, agentVersion string, supported []string) error {
+	agent := "the CloudWatch agent"
+	if agentVersion != "" {
+		agent = fmt.Sprintf("the CloudWatch agent %s", agentVersion)
+	}
+	list := "none"
+	if len(supported) > 0 {
+		list = strings.Join(supported, ", ")
+	}
+	return fmt.Errorf("the %s %q is not supported by %s, the supported %ss are: %s", componentType, name, agent, componentType, list)
+}
+
+func toStrings(value interface{}) []string {
+	list, _ := value.([]interface{})
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		strs = append(strs, fmt.Sprint(item))
+	}
+	return strs
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
 
 // Following Otel Doc: Configuring a receiver does not enable it. The receivers are enabled via pipelines within the service section.
 // getEnabledComponents returns all enabled components as a true flag set. If it can't find any receiver, it will return a nil interface.
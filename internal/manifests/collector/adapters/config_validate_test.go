@@ -4,6 +4,9 @@
 package adapters
 
 import (
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -90,3 +93,147 @@ service:
 	check := getEnabledComponents(config, ComponentTypeReceiver)
 	require.Empty(t, check)
 }
+
+func TestValidateComponents(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		agentVersion string
+		expectedErr  string
+	}{
+		{
+			name: "valid config",
+			config: `
+receivers:
+  otlp:
+  otlp/2:
+processors:
+  batch/traces:
+exporters:
+  awsxray:
+extensions:
+  health_check:
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp, otlp/2]
+      processors: [batch/traces]
+      exporters: [awsxray]
+`,
+		},
+		{
+			name: "undefined receiver",
+			config: `
+exporters:
+  awsxray:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [awsxray]
+`,
+			expectedErr: `the pipeline "traces" references the receiver "otlp", which is not defined`,
+		},
+		{
+			name: "undefined extension",
+			config: `
+service:
+  extensions: [health_check]
+`,
+			expectedErr: `the service references the extension "health_check", which is not defined`,
+		},
+		{
+			name: "unsupported receiver",
+			config: `
+receivers:
+  hostmetrics:
+`,
+			expectedErr: `the receiver "hostmetrics" is not supported by the CloudWatch agent, the supported receivers are: awscontainerinsightreceiver, awsecscontainermetrics,`,
+		},
+		{
+			name: "connector",
+			config: `
+connectors:
+  spanmetrics:
+receivers:
+  otlp:
+exporters:
+  awsemf:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [spanmetrics]
+    metrics:
+      receivers: [spanmetrics]
+      exporters: [awsemf]
+`,
+		},
+		{
+			name: "unsupported connector",
+			config: `
+connectors:
+  servicegraph:
+`,
+			expectedErr: `the connector "servicegraph" is not supported by the CloudWatch agent, the supported connectors are: count, forward, routing, spanmetrics`,
+		},
+		{
+			name: "receiver of the agent distribution",
+			config: `
+receivers:
+  syslog:
+  fluentforward:
+  splunk_hec:
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ConfigFromString(tt.config)
+			require.NoError(t, err)
+
+			err = ValidateComponents(config, tt.agentVersion)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestSupportedComponentsSince(t *testing.T) {
+	components := supportedComponents
+	t.Cleanup(func() { supportedComponents = components })
+	supportedComponents = mustParseComponents([]byte(`{"processors": [{"name": "batch"}, {"name": "awsentity", "since": "1.300049.0"}]}`))
+
+	require.Equal(t, []string{"batch"}, SupportedComponents(ComponentTypeProcessor, "1.300045.0b810"))
+	require.Equal(t, []string{"awsentity", "batch"}, SupportedComponents(ComponentTypeProcessor, "1.300049.1b929"))
+	// an unknown version is assumed to be the latest one
+	require.Equal(t, []string{"awsentity", "batch"}, SupportedComponents(ComponentTypeProcessor, ""))
+}
+
+func TestComponentManifestMatchesAgentVersion(t *testing.T) {
+	versions, err := os.ReadFile("../../../../versions.txt")
+	require.NoError(t, err)
+	var agentVersion string
+	for _, line := range strings.Split(string(versions), "\n") {
+		if strings.HasPrefix(line, "cloudwatch-agent=") {
+			agentVersion = strings.TrimSpace(line)
+		}
+	}
+	require.NotEmpty(t, agentVersion)
+
+	var manifest struct {
+		Comment string `json:"$comment"`
+	}
+	require.NoError(t, json.Unmarshal(agentComponents, &manifest))
+	require.Equal(t, agentVersion, manifest.Comment, "the component manifest must be updated along with the agent version")
+}
+
+func TestAgentVersionFromImage(t *testing.T) {
+	require.Equal(t, "1.300045.0b810", AgentVersionFromImage("public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300045.0b810"))
+	require.Equal(t, "", AgentVersionFromImage("localhost:5000/cloudwatch-agent"))
+	require.Equal(t, "", AgentVersionFromImage("cloudwatch-agent@sha256:0123456789abcdef"))
+}
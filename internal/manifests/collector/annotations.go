@@ -7,9 +7,16 @@ import (
 	"crypto/sha256"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
+// configSHAAnnotation is set on the objects of the AmazonCloudWatchAgent to the hash of its configuration.
+const configSHAAnnotation = "amazon-cloudwatch-agent-operator-config/sha256"
+
 // Annotations return the annotations for AmazonCloudWatchAgent pod.
 func Annotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	// new map every time, so that we don't touch the instance's annotations
@@ -28,7 +35,7 @@ func Annotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	}
 
 	// make sure sha256 for configMap is always calculated
-	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
+	annotations[configSHAAnnotation] = getConfigMapSHA(instance.Spec.Config)
 
 	return annotations
 }
@@ -43,16 +50,14 @@ func PodAnnotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 		podAnnotations[k] = v
 	}
 
-	// propagating annotations from metadata.annotations
+	// propagating annotations from metadata.annotations, except for the hash of the config: the pod templates carry
+	// the config checksum instead, which covers every config mounted by the pods
 	for kMeta, vMeta := range Annotations(instance) {
-		if _, found := podAnnotations[kMeta]; !found {
+		if _, found := podAnnotations[kMeta]; !found && kMeta != configSHAAnnotation {
 			podAnnotations[kMeta] = vMeta
 		}
 	}
 
-	// make sure sha256 for configMap is always calculated
-	podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
-
 	return podAnnotations
 }
 
@@ -60,3 +65,23 @@ func getConfigMapSHA(config string) string {
 	h := sha256.Sum256([]byte(config))
 	return fmt.Sprintf("%x", h)
 }
+
+// configChecksum returns the checksum of the agent, OTel and Prometheus configurations and of the config maps of
+// spec.configmaps, which are all mounted by the pods.
+func configChecksum(params manifests.Params) string {
+	configMaps, err := ConfigMaps(params)
+	if err != nil {
+		params.Log.V(2).Info("failed to build the config maps for the config checksum", "err", err)
+	}
+	secret, err := ConfigSecret(params)
+	if err != nil {
+		params.Log.V(2).Info("failed to build the config secret for the config checksum", "err", err)
+	}
+	if secret != nil {
+		configMaps = append(configMaps, &corev1.ConfigMap{ObjectMeta: secret.ObjectMeta, BinaryData: secret.Data})
+	}
+	for i := range params.ExtraConfigMaps {
+		configMaps = append(configMaps, &params.ExtraConfigMaps[i])
+	}
+	return manifestutils.ConfigChecksum(configMaps...)
+}
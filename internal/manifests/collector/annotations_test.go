@@ -4,12 +4,15 @@
 package collector
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
 func TestDefaultAnnotations(t *testing.T) {
@@ -30,8 +33,8 @@ func TestDefaultAnnotations(t *testing.T) {
 
 	//verify
 	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", annotations["amazon-cloudwatch-agent-operator-config/sha256"])
-	//verify propagation from metadata.annotations to spec.template.spec.metadata.annotations
-	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"])
+	//verify the pod templates carry the config checksum instead
+	assert.NotContains(t, podAnnotations, "amazon-cloudwatch-agent-operator-config/sha256")
 }
 
 func TestUserAnnotations(t *testing.T) {
@@ -55,7 +58,7 @@ func TestUserAnnotations(t *testing.T) {
 
 	//verify
 	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", annotations["amazon-cloudwatch-agent-operator-config/sha256"])
-	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.NotContains(t, podAnnotations, "amazon-cloudwatch-agent-operator-config/sha256")
 }
 
 func TestAnnotationsPropagateDown(t *testing.T) {
@@ -79,3 +82,27 @@ func TestAnnotationsPropagateDown(t *testing.T) {
 	assert.Equal(t, "mycomponent", podAnnotations["myapp"])
 	assert.Equal(t, "pod_annotation_value", podAnnotations["pod_annotation"])
 }
+
+func TestConfigChecksum(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	checksum := DaemonSet(params).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation]
+	assert.NotEmpty(t, checksum)
+	assert.Equal(t, checksum, Deployment(params).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation])
+	assert.Equal(t, checksum, StatefulSet(params).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation])
+
+	// a change of the mounted extra config maps restarts the pods
+	params.ExtraConfigMaps = []corev1.ConfigMap{{
+		ObjectMeta: metav1.ObjectMeta{Name: "extra"},
+		Data:       map[string]string{"key": "value"},
+	}}
+	extraChecksum := DaemonSet(params).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation]
+	assert.NotEqual(t, checksum, extraChecksum)
+	params.ExtraConfigMaps[0].Data["key"] = "changed"
+	assert.NotEqual(t, extraChecksum, DaemonSet(params).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation])
+
+	// so does a change of the OTel configuration
+	otelParams := otelConfigParams()
+	otelChecksum := DaemonSet(otelParams).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation]
+	otelParams.OtelCol.Spec.OtelConfig = strings.Replace(otelParams.OtelCol.Spec.OtelConfig, "10s", "20s", 1)
+	assert.NotEqual(t, otelChecksum, DaemonSet(otelParams).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation])
+}
@@ -33,6 +33,7 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	}
 	manifestFactories = append(manifestFactories, []manifests.K8sManifestFactory{
 		manifests.FactoryWithoutError(HorizontalPodAutoscaler),
+		manifests.FactoryWithoutError(ScaledObject),
 		manifests.FactoryWithoutError(ServiceAccount),
 		manifests.Factory(Service),
 		manifests.Factory(HeadlessService),
@@ -61,6 +62,13 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	for _, configmap := range configmaps {
 		resourceManifests = append(resourceManifests, configmap)
 	}
+	secret, err := ConfigSecret(params)
+	if err != nil {
+		return nil, err
+	}
+	if secret != nil {
+		resourceManifests = append(resourceManifests, secret)
+	}
 	routes, err := Routes(params)
 	if err != nil {
 		return nil, err
@@ -69,5 +77,10 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	for _, route := range routes {
 		resourceManifests = append(resourceManifests, route)
 	}
+	gatewayRoutes, err := GatewayRoutes(params)
+	if err != nil {
+		return nil, err
+	}
+	resourceManifests = append(resourceManifests, gatewayRoutes...)
 	return resourceManifests, nil
 }
@@ -10,46 +10,41 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 )
 
+// ConfigInSecret returns whether the configuration of the instance is stored in a Secret instead of a ConfigMap,
+// which is the case when it is read from a Secret.
+func ConfigInSecret(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.ConfigFrom.IsSecret() || instance.Spec.OtelConfigFrom.IsSecret()
+}
+
 func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 	var configmaps []*corev1.ConfigMap
 
-	name := naming.ConfigMap(params.OtelCol.Name)
-	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
-
-	replacedConf, err := ReplaceConfig(params.OtelCol)
-	if err != nil {
-		params.Log.V(2).Info("failed to update config: ", "err", err)
-		return nil, err
-	}
+	if !ConfigInSecret(params.OtelCol) {
+		name := naming.ConfigMap(params.OtelCol.Name)
+		labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
 
-	sourceDataMap := map[string]string{
-		params.Config.CollectorConfigMapEntry(): replacedConf,
-	}
-
-	if params.OtelCol.Spec.OtelConfig != "" {
-		replacedOtelConfig, err := ReplaceOtelConfig(params.OtelCol)
+		sourceDataMap, err := configData(params)
 		if err != nil {
-			params.Log.V(2).Info("failed to update otel config: ", "err", err)
 			return nil, err
 		}
-		sourceDataMap[params.Config.OtelCollectorConfigMapEntry()] = replacedOtelConfig
-	}
 
-	configmaps = append(configmaps, &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
-			Namespace:   params.OtelCol.Namespace,
-			Labels:      labels,
-			Annotations: params.OtelCol.Annotations,
-		},
-		Data: sourceDataMap,
-	})
+		configmaps = append(configmaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   params.OtelCol.Namespace,
+				Labels:      labels,
+				Annotations: params.OtelCol.Annotations,
+			},
+			Data: sourceDataMap,
+		})
+	}
 
 	if !params.OtelCol.Spec.Prometheus.IsEmpty() {
 		promName := naming.PrometheusConfigMap(params.OtelCol.Name)
@@ -95,3 +90,54 @@ func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 
 	return configmaps, nil
 }
+
+// ConfigSecret builds the Secret holding the configuration of the instance when it is read from a Secret, so that
+// its content is never stored in a ConfigMap. It returns nil otherwise.
+func ConfigSecret(params manifests.Params) (*corev1.Secret, error) {
+	if !ConfigInSecret(params.OtelCol) {
+		return nil, nil
+	}
+	name := naming.ConfigSecret(params.OtelCol.Name)
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	sourceDataMap, err := configData(params)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(sourceDataMap))
+	for k, v := range sourceDataMap {
+		data[k] = []byte(v)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   params.OtelCol.Namespace,
+			Labels:      labels,
+			Annotations: params.OtelCol.Annotations,
+		},
+		Data: data,
+	}, nil
+}
+
+// configData returns the agent and OTel configurations mounted by the pods.
+func configData(params manifests.Params) (map[string]string, error) {
+	replacedConf, err := ReplaceConfig(params.OtelCol)
+	if err != nil {
+		params.Log.V(2).Info("failed to update config: ", "err", err)
+		return nil, err
+	}
+
+	sourceDataMap := map[string]string{
+		params.Config.CollectorConfigMapEntry(): replacedConf,
+	}
+
+	if params.OtelCol.Spec.OtelConfig != "" {
+		replacedOtelConfig, err := ReplaceOtelConfig(params.OtelCol)
+		if err != nil {
+			params.Log.V(2).Info("failed to update otel config: ", "err", err)
+			return nil, err
+		}
+		sourceDataMap[params.Config.OtelCollectorConfigMapEntry()] = replacedOtelConfig
+	}
+	return sourceDataMap, nil
+}
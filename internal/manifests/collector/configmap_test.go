@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	colfeaturegate "go.opentelemetry.io/collector/featuregate"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -326,3 +327,29 @@ service:
 		assert.YAMLEq(t, expectedData["cwagentotelconfig.yaml"], actual[0].Data["cwagentotelconfig.yaml"])
 	})
 }
+
+func TestDesiredConfigSecret(t *testing.T) {
+	t.Run("should not return a secret for an inline config", func(t *testing.T) {
+		secret, err := ConfigSecret(deploymentParams())
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("should return the config in a secret when it is read from a secret", func(t *testing.T) {
+		param := deploymentParams()
+		param.OtelCol.Spec.ConfigFrom = &v1alpha1.ConfigSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+		}
+
+		secret, err := ConfigSecret(param)
+		assert.NoError(t, err)
+		assert.Equal(t, "test-config", secret.Name)
+		assert.Contains(t, secret.Data, "cwagentconfig.json")
+		// the operator only caches the secrets it manages
+		assert.Equal(t, "amazon-cloudwatch-agent-operator", secret.Labels["app.kubernetes.io/managed-by"])
+
+		configmaps, err := ConfigMaps(param)
+		assert.NoError(t, err)
+		assert.Empty(t, configmaps)
+	})
+}
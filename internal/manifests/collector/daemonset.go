@@ -20,6 +20,11 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 
 	annotations := Annotations(params.OtelCol)
 	podAnnotations := PodAnnotations(params.OtelCol)
+	podAnnotations[manifestutils.ConfigChecksumAnnotation] = configChecksum(params)
+	affinity := params.OtelCol.Spec.Affinity
+	if CanaryRolloutEnabled(params.OtelCol) {
+		affinity = excludeCanaryNodes(affinity, params.OtelCol)
+	}
 	return &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        naming.Collector(params.OtelCol.Name),
@@ -47,7 +52,7 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 					DNSPolicy:          getDNSPolicy(params.OtelCol),
 					SecurityContext:    params.OtelCol.Spec.PodSecurityContext,
 					PriorityClassName:  params.OtelCol.Spec.PriorityClassName,
-					Affinity:           params.OtelCol.Spec.Affinity,
+					Affinity:           affinity,
 				},
 			},
 			UpdateStrategy: params.OtelCol.Spec.UpdateStrategy,
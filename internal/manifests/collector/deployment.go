@@ -20,6 +20,7 @@ func Deployment(params manifests.Params) *appsv1.Deployment {
 
 	annotations := Annotations(params.OtelCol)
 	podAnnotations := PodAnnotations(params.OtelCol)
+	podAnnotations[manifestutils.ConfigChecksumAnnotation] = configChecksum(params)
 
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
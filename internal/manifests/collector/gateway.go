@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// GatewayRoutes builds the Gateway API routes exposing the receiver ports of the instance: an HTTPRoute for each
+// port with the http app protocol and a GRPCRoute for each port with the grpc app protocol. A BackendTLSPolicy is
+// added for the service when the gateway connects to the collector over TLS.
+func GatewayRoutes(params manifests.Params) ([]client.Object, error) {
+	if params.OtelCol.Spec.Ingress.Type != v1alpha1.IngressTypeGateway || params.OtelCol.Spec.Ingress.Gateway == nil {
+		return nil, nil
+	}
+
+	if params.OtelCol.Spec.Mode == v1alpha1.ModeSidecar {
+		params.Log.V(3).Info("ingress settings are not supported in sidecar mode")
+		return nil, nil
+	}
+
+	if !params.Config.GatewayAPIAvailable() {
+		params.Log.V(1).Info(
+			"the Gateway API is not served by the cluster, skipping ingress",
+			"instance.name", params.OtelCol.Name,
+			"instance.namespace", params.OtelCol.Namespace,
+		)
+		return nil, nil
+	}
+
+	ports, err := servicePortsFromCfg(params.Log, params.OtelCol)
+
+	// if we have no ports, we don't need a route
+	if len(ports) == 0 || err != nil {
+		params.Log.V(1).Info(
+			"the instance's configuration didn't yield any ports to open, skipping ingress",
+			"instance.name", params.OtelCol.Name,
+			"instance.namespace", params.OtelCol.Namespace,
+		)
+		return nil, err
+	}
+
+	gateway := params.OtelCol.Spec.Ingress.Gateway
+	parentRef := gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gateway.ParentRef.Name)}
+	if gateway.ParentRef.Namespace != "" {
+		namespace := gatewayv1.Namespace(gateway.ParentRef.Namespace)
+		parentRef.Namespace = &namespace
+	}
+	if gateway.ParentRef.SectionName != "" {
+		sectionName := gatewayv1.SectionName(gateway.ParentRef.SectionName)
+		parentRef.SectionName = &sectionName
+	}
+
+	var routes []client.Object
+	for _, p := range ports {
+		if p.AppProtocol == nil {
+			continue
+		}
+		portName := naming.PortName(p.Name, p.Port)
+		var hostnames []gatewayv1.Hostname
+		if params.OtelCol.Spec.Ingress.Hostname != "" {
+			hostnames = []gatewayv1.Hostname{gatewayv1.Hostname(fmt.Sprintf("%s.%s", portName, params.OtelCol.Spec.Ingress.Hostname))}
+		}
+		backendRef := gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(naming.Service(params.OtelCol.Name)),
+				Port: &p.Port,
+			},
+		}
+		meta := gatewayObjectMeta(params, naming.Route(params.OtelCol.Name, p.Name))
+		spec := gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef}}
+
+		switch *p.AppProtocol {
+		case "grpc":
+			routes = append(routes, &gatewayv1.GRPCRoute{
+				ObjectMeta: meta,
+				Spec: gatewayv1.GRPCRouteSpec{
+					CommonRouteSpec: spec,
+					Hostnames:       hostnames,
+					Rules:           []gatewayv1.GRPCRouteRule{{BackendRefs: []gatewayv1.GRPCBackendRef{{BackendRef: backendRef}}}},
+				},
+			})
+		case "http":
+			routes = append(routes, &gatewayv1.HTTPRoute{
+				ObjectMeta: meta,
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: spec,
+					Hostnames:       hostnames,
+					Rules:           []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: backendRef}}}},
+				},
+			})
+		}
+	}
+
+	if len(routes) > 0 && gateway.BackendTLS != nil {
+		routes = append(routes, backendTLSPolicy(params, gateway.BackendTLS))
+	}
+	return routes, nil
+}
+
+// backendTLSPolicy builds the policy making the gateway validate the certificate served by the collector service.
+func backendTLSPolicy(params manifests.Params, backendTLS *v1alpha1.GatewayBackendTLS) *gatewayv1.BackendTLSPolicy {
+	validation := gatewayv1.BackendTLSPolicyValidation{Hostname: gatewayv1.PreciseHostname(backendTLS.Hostname)}
+	if len(backendTLS.CACertificateRefs) == 0 {
+		system := gatewayv1.WellKnownCACertificatesSystem
+		validation.WellKnownCACertificates = &system
+	}
+	for _, ref := range backendTLS.CACertificateRefs {
+		validation.CACertificateRefs = append(validation.CACertificateRefs, gatewayv1.LocalObjectReference{
+			Group: gatewayv1.Group(corev1.GroupName),
+			Kind:  "ConfigMap",
+			Name:  gatewayv1.ObjectName(ref.Name),
+		})
+	}
+	return &gatewayv1.BackendTLSPolicy{
+		ObjectMeta: gatewayObjectMeta(params, naming.BackendTLSPolicy(params.OtelCol.Name)),
+		Spec: gatewayv1.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1.LocalPolicyTargetReference{
+					Group: gatewayv1.Group(corev1.GroupName),
+					Kind:  "Service",
+					Name:  gatewayv1.ObjectName(naming.Service(params.OtelCol.Name)),
+				},
+			}},
+			Validation: validation,
+		},
+	}
+}
+
+// gatewayObjectMeta labels the Gateway API objects like the other objects of the instance, so that they are pruned
+// once the instance stops exposing them.
+func gatewayObjectMeta(params manifests.Params, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   params.OtelCol.Namespace,
+		Annotations: params.OtelCol.Spec.Ingress.Annotations,
+		Labels:      manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter()),
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func gatewayParams(gatewayAPIAvailable bool) manifests.Params {
+	return manifests.Params{
+		Config: config.New(config.WithGatewayAPIAvailable(gatewayAPIAvailable)),
+		Log:    logger,
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode: v1alpha1.ModeDeployment,
+				Ports: []corev1.ServicePort{
+					{Name: "otlp-grpc", Port: 4317, AppProtocol: ptr.To("grpc")},
+					{Name: "otlp-http", Port: 4318, AppProtocol: ptr.To("http")},
+					{Name: "statsd", Port: 8125, Protocol: corev1.ProtocolUDP},
+				},
+				Ingress: v1alpha1.Ingress{
+					Type:     v1alpha1.IngressTypeGateway,
+					Hostname: "example.com",
+					Gateway: &v1alpha1.GatewayRoute{
+						ParentRef: v1alpha1.GatewayParentReference{Name: "gateway", Namespace: "infra", SectionName: "https"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGatewayRoutes(t *testing.T) {
+	t.Run("should return nil for other ingress types", func(t *testing.T) {
+		params := gatewayParams(true)
+		params.OtelCol.Spec.Ingress.Type = v1alpha1.IngressTypeRoute
+		actual, err := GatewayRoutes(params)
+		assert.NoError(t, err)
+		assert.Nil(t, actual)
+	})
+
+	t.Run("should return nil when the Gateway API is not available", func(t *testing.T) {
+		actual, err := GatewayRoutes(gatewayParams(false))
+		assert.NoError(t, err)
+		assert.Nil(t, actual)
+	})
+
+	t.Run("should return nil in sidecar mode", func(t *testing.T) {
+		params := gatewayParams(true)
+		params.OtelCol.Spec.Mode = v1alpha1.ModeSidecar
+		actual, err := GatewayRoutes(params)
+		assert.NoError(t, err)
+		assert.Nil(t, actual)
+	})
+
+	t.Run("should return a route per otlp port", func(t *testing.T) {
+		actual, err := GatewayRoutes(gatewayParams(true))
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+
+		parentRefs := []gatewayv1.ParentReference{{
+			Name:        "gateway",
+			Namespace:   ptr.To(gatewayv1.Namespace("infra")),
+			SectionName: ptr.To(gatewayv1.SectionName("https")),
+		}}
+
+		grpcRoute, ok := actual[0].(*gatewayv1.GRPCRoute)
+		require.True(t, ok)
+		assert.Equal(t, naming.Route("agent", "otlp-grpc"), grpcRoute.Name)
+		assert.Equal(t, "default", grpcRoute.Namespace)
+		assert.Subset(t, grpcRoute.Labels, manifestutils.SelectorLabelsForAllOperatorManaged(metav1.ObjectMeta{Name: "agent", Namespace: "default"}))
+		assert.Equal(t, parentRefs, grpcRoute.Spec.ParentRefs)
+		assert.Equal(t, []gatewayv1.Hostname{"otlp-grpc.example.com"}, grpcRoute.Spec.Hostnames)
+		assert.Equal(t, gatewayv1.BackendObjectReference{Name: "agent", Port: ptr.To(gatewayv1.PortNumber(4317))},
+			grpcRoute.Spec.Rules[0].BackendRefs[0].BackendObjectReference)
+
+		httpRoute, ok := actual[1].(*gatewayv1.HTTPRoute)
+		require.True(t, ok)
+		assert.Equal(t, naming.Route("agent", "otlp-http"), httpRoute.Name)
+		assert.Equal(t, parentRefs, httpRoute.Spec.ParentRefs)
+		assert.Equal(t, []gatewayv1.Hostname{"otlp-http.example.com"}, httpRoute.Spec.Hostnames)
+		assert.Equal(t, gatewayv1.BackendObjectReference{Name: "agent", Port: ptr.To(gatewayv1.PortNumber(4318))},
+			httpRoute.Spec.Rules[0].BackendRefs[0].BackendObjectReference)
+	})
+
+	t.Run("should return a backend TLS policy", func(t *testing.T) {
+		params := gatewayParams(true)
+		params.OtelCol.Spec.Ingress.Gateway.BackendTLS = &v1alpha1.GatewayBackendTLS{Hostname: "agent.default.svc"}
+		actual, err := GatewayRoutes(params)
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+
+		policy, ok := actual[2].(*gatewayv1.BackendTLSPolicy)
+		require.True(t, ok)
+		assert.Equal(t, naming.BackendTLSPolicy("agent"), policy.Name)
+		assert.Equal(t, gatewayv1.ObjectName("agent"), policy.Spec.TargetRefs[0].Name)
+		assert.Equal(t, gatewayv1.Kind("Service"), policy.Spec.TargetRefs[0].Kind)
+		assert.Equal(t, gatewayv1.PreciseHostname("agent.default.svc"), policy.Spec.Validation.Hostname)
+		assert.Equal(t, ptr.To(gatewayv1.WellKnownCACertificatesSystem), policy.Spec.Validation.WellKnownCACertificates)
+		assert.Empty(t, policy.Spec.Validation.CACertificateRefs)
+
+		params.OtelCol.Spec.Ingress.Gateway.BackendTLS.CACertificateRefs = []corev1.LocalObjectReference{{Name: "ca"}}
+		actual, err = GatewayRoutes(params)
+		require.NoError(t, err)
+		policy = actual[2].(*gatewayv1.BackendTLSPolicy)
+		assert.Nil(t, policy.Spec.Validation.WellKnownCACertificates)
+		assert.Equal(t, []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: "ca"}}, policy.Spec.Validation.CACertificateRefs)
+	})
+}
@@ -34,6 +34,11 @@ func HorizontalPodAutoscaler(params manifests.Params) client.Object {
 		return nil
 	}
 
+	// the ScaledObject takes care of the scaling, through a HorizontalPodAutoscaler managed by KEDA.
+	if params.OtelCol.Spec.Autoscaler.Mode == v1alpha1.AutoscalerModeKEDA {
+		return nil
+	}
+
 	if params.OtelCol.Spec.Autoscaler.MaxReplicas == nil {
 		params.OtelCol.Spec.Autoscaler.MaxReplicas = params.OtelCol.Spec.MaxReplicas
 	}
@@ -95,13 +100,23 @@ func HorizontalPodAutoscaler(params manifests.Params) client.Object {
 
 	// convert from v1alpha1.MetricSpec into a autoscalingv2.MetricSpec.
 	for _, metric := range params.OtelCol.Spec.Autoscaler.Metrics {
-		if metric.Type == autoscalingv2.PodsMetricSourceType {
-			v2metric := autoscalingv2.MetricSpec{
+		switch metric.Type {
+		case autoscalingv2.PodsMetricSourceType:
+			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, autoscalingv2.MetricSpec{
 				Type: metric.Type,
 				Pods: metric.Pods,
-			}
-			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, v2metric)
-		} // pod metrics
+			})
+		case autoscalingv2.ObjectMetricSourceType:
+			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, autoscalingv2.MetricSpec{
+				Type:   metric.Type,
+				Object: metric.Object,
+			})
+		case autoscalingv2.ExternalMetricSourceType:
+			autoscaler.Spec.Metrics = append(autoscaler.Spec.Metrics, autoscalingv2.MetricSpec{
+				Type:     metric.Type,
+				External: metric.External,
+			})
+		}
 	}
 	result = &autoscaler
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -94,3 +95,42 @@ func TestHPA(t *testing.T) {
 	}
 
 }
+
+func TestHPACustomMetrics(t *testing.T) {
+	maxReplicas := int32(5)
+	objectMetric := &autoscalingv2.ObjectMetricSource{
+		DescribedObject: autoscalingv2.CrossVersionObjectReference{Kind: "Service", Name: "my-instance-target-allocator-service"},
+		Metric:          autoscalingv2.MetricIdentifier{Name: "cloudwatch_agent_allocator_targets_per_collector"},
+		Target:          autoscalingv2.MetricTarget{Type: autoscalingv2.ValueMetricType, Value: resource.NewQuantity(100, resource.DecimalSI)},
+	}
+	externalMetric := &autoscalingv2.ExternalMetricSource{
+		Metric: autoscalingv2.MetricIdentifier{Name: "exporter_queue_size"},
+		Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: resource.NewQuantity(1000, resource.DecimalSI)},
+	}
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logger,
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-instance"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Autoscaler: &v1alpha1.AutoscalerSpec{
+					MaxReplicas: &maxReplicas,
+					Metrics: []v1alpha1.MetricSpec{
+						{Type: autoscalingv2.ObjectMetricSourceType, Object: objectMetric},
+						{Type: autoscalingv2.ExternalMetricSourceType, External: externalMetric},
+					},
+				},
+			},
+		},
+	}
+
+	hpa := HorizontalPodAutoscaler(params).(*autoscalingv2.HorizontalPodAutoscaler)
+	assert.Equal(t, []autoscalingv2.MetricSpec{
+		{Type: autoscalingv2.ObjectMetricSourceType, Object: objectMetric},
+		{Type: autoscalingv2.ExternalMetricSourceType, External: externalMetric},
+	}, hpa.Spec.Metrics)
+
+	// KEDA manages the HorizontalPodAutoscaler in the keda mode
+	params.OtelCol.Spec.Autoscaler.Mode = v1alpha1.AutoscalerModeKEDA
+	assert.Nil(t, HorizontalPodAutoscaler(params))
+}
@@ -65,7 +65,7 @@ func singlePortFromConfigEndpoint(logger logr.Logger, name string, config map[in
 	switch {
 	// tcplog and udplog receivers hold the endpoint
 	// value in `listen_address` field
-	case name == "tcplog" || name == "udplog":
+	case receiverType(name) == "tcplog" || receiverType(name) == "udplog":
 		endpoint = getAddressFromConfig(logger, name, listenAddressKey, config)
 
 	// ignore the receiver as it holds the field key endpoint, and it
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package receiver
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser"
+)
+
+const parserNameCarbon = "__carbon"
+
+// NewCarbonReceiverParser builds a new parser for Carbon receivers, from the contrib repository. They listen on TCP
+// unless their transport is udp.
+func NewCarbonReceiverParser(logger logr.Logger, name string, config map[interface{}]interface{}) parser.ComponentPortParser {
+	protocol := corev1.ProtocolTCP
+	if transport, ok := config["transport"].(string); ok && strings.EqualFold(transport, "udp") {
+		protocol = corev1.ProtocolUDP
+	}
+	return &GenericReceiver{
+		logger:          logger,
+		name:            name,
+		config:          config,
+		defaultPort:     2003,
+		defaultProtocol: protocol,
+		parserName:      parserNameCarbon,
+	}
+}
+
+func init() {
+	Register("carbon", NewCarbonReceiverParser)
+}
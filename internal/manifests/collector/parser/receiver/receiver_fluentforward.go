@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package receiver
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser"
+)
+
+const parserNameFluentForward = "__fluentforward"
+
+// NewFluentForwardReceiverParser builds a new parser for Fluent Forward receivers, from the contrib repository.
+func NewFluentForwardReceiverParser(logger logr.Logger, name string, config map[interface{}]interface{}) parser.ComponentPortParser {
+	return &GenericReceiver{
+		logger:          logger,
+		name:            name,
+		config:          config,
+		defaultPort:     8006,
+		defaultProtocol: corev1.ProtocolTCP,
+		parserName:      parserNameFluentForward,
+	}
+}
+
+func init() {
+	Register("fluentforward", NewFluentForwardReceiverParser)
+}
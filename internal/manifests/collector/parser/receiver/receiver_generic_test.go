@@ -8,10 +8,12 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser/receiver"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 )
 
 var logger = logf.Log.WithName("unit-tests")
@@ -60,6 +62,12 @@ func TestDownstreamParsers(t *testing.T) {
 		// contrib receivers
 		{receiver.NewStatsdReceiverParser, "statsd", "statsd", "__statsd", 8125},
 		{receiver.NewAWSXrayReceiverParser, "awsxray", "awsxray", "__awsxray", 2000},
+		{receiver.NewFluentForwardReceiverParser, "fluentforward", "fluentforward", "__fluentforward", 8006},
+		{receiver.NewCarbonReceiverParser, "carbon", "carbon", "__carbon", 2003},
+		{receiver.NewInfluxdbReceiverParser, "influxdb", "influxdb", "__influxdb", 8086},
+		{receiver.NewSplunkHecReceiverParser, "splunk_hec", "splunk_hec", "__splunk_hec", 8088},
+		{receiver.NewSignalFxReceiverParser, "signalfx", "signalfx", "__signalfx", 9943},
+		{receiver.NewPrometheusRemoteWriteReceiverParser, "prometheusremotewrite", "prometheusremotewrite", "__prometheusremotewrite", 9090},
 	} {
 		t.Run(tt.receiverName, func(t *testing.T) {
 			t.Run("builds successfully", func(t *testing.T) {
@@ -81,7 +89,7 @@ func TestDownstreamParsers(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Len(t, ports, 1)
 				assert.EqualValues(t, tt.defaultPort, ports[0].Port)
-				assert.Equal(t, tt.receiverName, ports[0].Name)
+				assert.Equal(t, naming.PortName(tt.receiverName, int32(tt.defaultPort)), ports[0].Name)
 			})
 
 			t.Run("allows port to be overridden", func(t *testing.T) {
@@ -97,8 +105,51 @@ func TestDownstreamParsers(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Len(t, ports, 1)
 				assert.EqualValues(t, 65535, ports[0].Port)
-				assert.Equal(t, tt.receiverName, ports[0].Name)
+				assert.Equal(t, naming.PortName(tt.receiverName, 65535), ports[0].Name)
 			})
 		})
 	}
 }
+
+func TestCarbonReceiverTransport(t *testing.T) {
+	ports, err := receiver.NewCarbonReceiverParser(logger, "carbon", map[interface{}]interface{}{}).Ports()
+	assert.NoError(t, err)
+	assert.Len(t, ports, 1)
+	assert.Equal(t, corev1.ProtocolTCP, ports[0].Protocol)
+
+	ports, err = receiver.NewCarbonReceiverParser(logger, "carbon", map[interface{}]interface{}{
+		"endpoint":  "0.0.0.0:2003",
+		"transport": "udp",
+	}).Ports()
+	assert.NoError(t, err)
+	assert.Len(t, ports, 1)
+	assert.EqualValues(t, 2003, ports[0].Port)
+	assert.Equal(t, corev1.ProtocolUDP, ports[0].Protocol)
+}
+
+func TestLogReceiverParsers(t *testing.T) {
+	for _, tt := range []struct {
+		builder      func(logr.Logger, string, map[interface{}]interface{}) parser.ComponentPortParser
+		receiverName string
+		protocol     corev1.Protocol
+	}{
+		{receiver.NewTCPLogReceiverParser, "tcplog", corev1.ProtocolTCP},
+		{receiver.NewUDPLogReceiverParser, "udplog/custom", corev1.ProtocolUDP},
+	} {
+		t.Run(tt.receiverName, func(t *testing.T) {
+			// no port is exposed without listen_address, which the receivers require
+			ports, err := tt.builder(logger, tt.receiverName, map[interface{}]interface{}{}).Ports()
+			assert.NoError(t, err)
+			assert.Empty(t, ports)
+
+			// the endpoint is held by listen_address
+			ports, err = tt.builder(logger, tt.receiverName, map[interface{}]interface{}{
+				"listen_address": "0.0.0.0:5140",
+			}).Ports()
+			assert.NoError(t, err)
+			assert.Len(t, ports, 1)
+			assert.EqualValues(t, 5140, ports[0].Port)
+			assert.Equal(t, tt.protocol, ports[0].Protocol)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package receiver
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser"
+)
+
+const parserNameInfluxdb = "__influxdb"
+
+// NewInfluxdbReceiverParser builds a new parser for InfluxDB receivers, from the contrib repository.
+func NewInfluxdbReceiverParser(logger logr.Logger, name string, config map[interface{}]interface{}) parser.ComponentPortParser {
+	http := "http"
+	return &GenericReceiver{
+		logger:             logger,
+		name:               name,
+		config:             config,
+		defaultPort:        8086,
+		defaultProtocol:    corev1.ProtocolTCP,
+		defaultAppProtocol: &http,
+		parserName:         parserNameInfluxdb,
+	}
+}
+
+func init() {
+	Register("influxdb", NewInfluxdbReceiverParser)
+}
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package receiver
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const parserNameSyslog = "__syslog"
+
+var _ parser.ComponentPortParser = &SyslogReceiverParser{}
+
+// SyslogReceiverParser parses the configuration for Syslog receivers, which listen on TCP or UDP depending on
+// whether their `tcp` or `udp` section is set.
+type SyslogReceiverParser struct {
+	config map[interface{}]interface{}
+	logger logr.Logger
+	name   string
+}
+
+// NewSyslogReceiverParser builds a new parser for Syslog receivers, from the contrib repository.
+func NewSyslogReceiverParser(logger logr.Logger, name string, config map[interface{}]interface{}) parser.ComponentPortParser {
+	return &SyslogReceiverParser{
+		logger: logger,
+		name:   name,
+		config: config,
+	}
+}
+
+// Ports returns the service port of the protocol the receiver listens on. The listen_address of the protocol is
+// required, so no port is returned without it.
+func (s *SyslogReceiverParser) Ports() ([]corev1.ServicePort, error) {
+	for _, protocol := range []struct {
+		key      string
+		protocol corev1.Protocol
+	}{
+		{key: "tcp", protocol: corev1.ProtocolTCP},
+		{key: "udp", protocol: corev1.ProtocolUDP},
+	} {
+		section, ok := s.config[protocol.key]
+		if !ok {
+			continue
+		}
+		config, _ := section.(map[interface{}]interface{})
+		endpoint, ok := config[listenAddressKey].(string)
+		if !ok {
+			return []corev1.ServicePort{}, nil
+		}
+		port, err := portFromEndpoint(endpoint)
+		if err != nil {
+			s.logger.WithValues(listenAddressKey, endpoint).Error(err, "couldn't parse the endpoint's port")
+			return []corev1.ServicePort{}, nil
+		}
+		return []corev1.ServicePort{{
+			Name:     naming.PortName(s.name, port),
+			Port:     port,
+			Protocol: protocol.protocol,
+		}}, nil
+	}
+	return []corev1.ServicePort{}, nil
+}
+
+// ParserName returns the name of this parser.
+func (s *SyslogReceiverParser) ParserName() string {
+	return parserNameSyslog
+}
+
+func init() {
+	Register("syslog", NewSyslogReceiverParser)
+}
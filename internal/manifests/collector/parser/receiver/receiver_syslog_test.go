@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package receiver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser/receiver"
+)
+
+func TestSyslogSelfRegisters(t *testing.T) {
+	// verify
+	assert.True(t, receiver.IsRegistered("syslog"))
+}
+
+func TestSyslogPorts(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		config   map[interface{}]interface{}
+		expected []corev1.ServicePort
+	}{
+		{
+			desc:     "no listener",
+			config:   map[interface{}]interface{}{},
+			expected: []corev1.ServicePort{},
+		},
+		{
+			desc:     "tcp without listen address",
+			config:   map[interface{}]interface{}{"tcp": nil, "protocol": "rfc5424"},
+			expected: []corev1.ServicePort{},
+		},
+		{
+			desc: "tcp with a listen address",
+			config: map[interface{}]interface{}{
+				"tcp": map[interface{}]interface{}{"listen_address": "0.0.0.0:601"},
+			},
+			expected: []corev1.ServicePort{{Name: "syslog", Port: 601, Protocol: corev1.ProtocolTCP}},
+		},
+		{
+			desc: "udp with a listen address",
+			config: map[interface{}]interface{}{
+				"udp": map[interface{}]interface{}{"listen_address": "0.0.0.0:514"},
+			},
+			expected: []corev1.ServicePort{{Name: "syslog", Port: 514, Protocol: corev1.ProtocolUDP}},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			builder := receiver.NewSyslogReceiverParser(logger, "syslog", tt.config)
+
+			// test
+			ports, err := builder.Ports()
+
+			// verify
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ports)
+			assert.Equal(t, "__syslog", builder.ParserName())
+		})
+	}
+}
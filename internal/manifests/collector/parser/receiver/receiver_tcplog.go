@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package receiver
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/parser"
+)
+
+const parserNameTCPLog = "__tcplog"
+
+// NewTCPLogReceiverParser builds a new parser for TCP log receivers, from the contrib repository. Their
+// listen_address is required, so none is exposed without it.
+func NewTCPLogReceiverParser(logger logr.Logger, name string, config map[interface{}]interface{}) parser.ComponentPortParser {
+	return &GenericReceiver{
+		logger:          logger,
+		name:            name,
+		config:          config,
+		defaultProtocol: corev1.ProtocolTCP,
+		parserName:      parserNameTCPLog,
+	}
+}
+
+func init() {
+	Register("tcplog", NewTCPLogReceiverParser)
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	// canaryNodeLabelPrefix prefixes the label set on the nodes picked to run the configuration being canaried.
+	canaryNodeLabelPrefix = "canary.cloudwatch.aws.amazon.com/"
+	// RolloutLabel tells apart the pods of the canary daemonset from the pods of the main daemonset.
+	RolloutLabel = "cloudwatch.aws.amazon.com/rollout"
+	// RolloutCanary is the value of RolloutLabel on the canary pods.
+	RolloutCanary = "canary"
+)
+
+// CanaryRolloutEnabled returns whether the configuration changes of the instance are canaried.
+func CanaryRolloutEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.Mode == v1alpha1.ModeDaemonSet &&
+		instance.Spec.RolloutStrategy != nil &&
+		instance.Spec.RolloutStrategy.Type == v1alpha1.RolloutStrategyCanary
+}
+
+// CanaryNodeLabel returns the label set on the nodes running the configuration being canaried for the instance.
+func CanaryNodeLabel(instance v1alpha1.AmazonCloudWatchAgent) string {
+	return canaryNodeLabelPrefix + naming.Truncate("%s.%s", 63, instance.Namespace, instance.Name)
+}
+
+// ConfigHash returns the hash of the configuration rolled out by the instance.
+func ConfigHash(instance v1alpha1.AmazonCloudWatchAgent) string {
+	h := sha256.Sum256([]byte(instance.Spec.Config + "\x00" + instance.Spec.OtelConfig))
+	return fmt.Sprintf("%x", h)
+}
+
+// ExcludesCanaryNodes returns whether the daemonset is kept off the canary nodes of the instance, which is the case
+// for the main daemonset once the canary rollout is enabled.
+func ExcludesCanaryNodes(ds *appsv1.DaemonSet, instance v1alpha1.AmazonCloudWatchAgent) bool {
+	affinity := ds.Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return false
+	}
+	key := CanaryNodeLabel(instance)
+	for _, term := range terms {
+		excluded := false
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == key && expr.Operator == corev1.NodeSelectorOpDoesNotExist {
+				excluded = true
+			}
+		}
+		if !excluded {
+			return false
+		}
+	}
+	return true
+}
+
+// CanaryDaemonSet builds the daemonset running the configuration of the instance on its canary nodes.
+func CanaryDaemonSet(params manifests.Params) *appsv1.DaemonSet {
+	ds := DaemonSet(params)
+	ds.Name = naming.CanaryCollector(params.OtelCol.Name)
+	ds.Labels[RolloutLabel] = RolloutCanary
+	ds.Spec.Selector.MatchLabels[RolloutLabel] = RolloutCanary
+	ds.Spec.Template.Labels[RolloutLabel] = RolloutCanary
+	// the canary nodes only run the canary pods, so all of them are replaced at once
+	maxUnavailable := intstr.FromString("100%")
+	ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+	}
+	ds.Spec.Template.Spec.Affinity = requireNodeLabel(params.OtelCol.Spec.Affinity, corev1.NodeSelectorRequirement{
+		Key:      CanaryNodeLabel(params.OtelCol),
+		Operator: corev1.NodeSelectorOpExists,
+	})
+	for i, volume := range ds.Spec.Template.Spec.Volumes {
+		if volume.Name != naming.ConfigMapVolume() {
+			continue
+		}
+		if volume.ConfigMap != nil {
+			ds.Spec.Template.Spec.Volumes[i].ConfigMap.Name = naming.CanaryConfigMap(params.OtelCol.Name)
+		}
+		if volume.Secret != nil {
+			ds.Spec.Template.Spec.Volumes[i].Secret.SecretName = naming.CanaryConfigSecret(params.OtelCol.Name)
+		}
+	}
+	return ds
+}
+
+// CanaryConfig builds the config map, or the secret when the configuration is read from a secret, holding the
+// configuration of the instance for its canary nodes.
+func CanaryConfig(params manifests.Params) (client.Object, error) {
+	if ConfigInSecret(params.OtelCol) {
+		secret, err := ConfigSecret(params)
+		if err != nil {
+			return nil, err
+		}
+		secret.Name = naming.CanaryConfigSecret(params.OtelCol.Name)
+		return secret, nil
+	}
+	configmaps, err := ConfigMaps(params)
+	if err != nil {
+		return nil, err
+	}
+	cm := configmaps[0]
+	cm.Name = naming.CanaryConfigMap(params.OtelCol.Name)
+	return cm, nil
+}
+
+// excludeCanaryNodes keeps the daemonset pods off the canary nodes.
+func excludeCanaryNodes(affinity *corev1.Affinity, instance v1alpha1.AmazonCloudWatchAgent) *corev1.Affinity {
+	return requireNodeLabel(affinity, corev1.NodeSelectorRequirement{
+		Key:      CanaryNodeLabel(instance),
+		Operator: corev1.NodeSelectorOpDoesNotExist,
+	})
+}
+
+// requireNodeLabel adds the requirement to every term of the required node affinity, without touching the
+// instance's affinity.
+func requireNodeLabel(affinity *corev1.Affinity, requirement corev1.NodeSelectorRequirement) *corev1.Affinity {
+	merged := &corev1.Affinity{}
+	if affinity != nil {
+		merged = affinity.DeepCopy()
+	}
+	if merged.NodeAffinity == nil {
+		merged.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	selector := merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+	return merged
+}
@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestDaemonSetExcludesCanaryNodes(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	assert.Nil(t, DaemonSet(params).Spec.Template.Spec.Affinity)
+
+	params.OtelCol.Spec.RolloutStrategy = &v1alpha1.RolloutStrategy{Type: v1alpha1.RolloutStrategyCanary}
+	params.OtelCol.Spec.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "a", Operator: corev1.NodeSelectorOpExists}}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "b", Operator: corev1.NodeSelectorOpExists}}},
+				},
+			},
+		},
+	}
+	ds := DaemonSet(params)
+	assert.True(t, ExcludesCanaryNodes(ds, params.OtelCol))
+	for _, term := range ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		assert.Len(t, term.MatchExpressions, 2)
+	}
+	// the instance's affinity is left untouched
+	assert.Len(t, params.OtelCol.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+}
+
+func TestCanaryDaemonSet(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+	params.OtelCol.Spec.RolloutStrategy = &v1alpha1.RolloutStrategy{Type: v1alpha1.RolloutStrategyCanary}
+
+	ds := CanaryDaemonSet(params)
+	assert.Equal(t, "test-canary", ds.Name)
+	assert.Equal(t, RolloutCanary, ds.Spec.Selector.MatchLabels[RolloutLabel])
+	assert.Equal(t, RolloutCanary, ds.Spec.Template.Labels[RolloutLabel])
+	assert.False(t, ExcludesCanaryNodes(ds, params.OtelCol))
+	assert.Equal(t, []corev1.NodeSelectorRequirement{{
+		Key:      "canary.cloudwatch.aws.amazon.com/default.test",
+		Operator: corev1.NodeSelectorOpExists,
+	}}, ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+	assert.Equal(t, naming.CanaryConfigMap("test"), ds.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+
+	// the main daemonset keeps its selector
+	assert.NotContains(t, DaemonSet(params).Spec.Selector.MatchLabels, RolloutLabel)
+}
+
+func TestCanaryConfig(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeDaemonSet)
+
+	obj, err := CanaryConfig(params)
+	require.NoError(t, err)
+	configmaps, err := ConfigMaps(params)
+	require.NoError(t, err)
+	cm, ok := obj.(*corev1.ConfigMap)
+	require.True(t, ok)
+	assert.Equal(t, "test-canary", cm.Name)
+	assert.Equal(t, configmaps[0].Data, cm.Data)
+
+	params.OtelCol.Spec.ConfigFrom = &v1alpha1.ConfigSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "config"}}
+	obj, err = CanaryConfig(params)
+	require.NoError(t, err)
+	secret, ok := obj.(*corev1.Secret)
+	require.True(t, ok)
+	assert.Equal(t, "test-canary-config", secret.Name)
+	assert.Equal(t, "test-canary-config", CanaryDaemonSet(params).Spec.Template.Spec.Volumes[0].Secret.SecretName)
+}
+
+func TestConfigHash(t *testing.T) {
+	instance := v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: "{}"}}
+	hash := ConfigHash(instance)
+	assert.Equal(t, hash, ConfigHash(instance))
+
+	instance.Spec.OtelConfig = "receivers: {}"
+	assert.NotEqual(t, hash, ConfigHash(instance))
+}
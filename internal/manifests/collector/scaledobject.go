@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	kedav1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/internal/keda/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// targetsPerCollectorMetric is the target allocator metric the ScaledObject scales the collector on.
+const targetsPerCollectorMetric = "cloudwatch_agent_allocator_targets_per_collector"
+
+// ScaledObject builds the KEDA ScaledObject scaling the collector on the number of targets assigned by the target
+// allocator, so that each collector is assigned about spec.autoscaler.keda.targetsPerCollector targets.
+func ScaledObject(params manifests.Params) client.Object {
+	autoscaler := params.OtelCol.Spec.Autoscaler
+	if autoscaler == nil || autoscaler.Mode != v1alpha1.AutoscalerModeKEDA || autoscaler.KEDA == nil {
+		return nil
+	}
+
+	if !params.Config.KEDAAvailable() {
+		params.Log.V(1).Info(
+			"KEDA is not installed in the cluster, skipping autoscaler creation",
+			"instance.name", params.OtelCol.Name,
+			"instance.namespace", params.OtelCol.Namespace,
+		)
+		return nil
+	}
+
+	name := naming.Collector(params.OtelCol.Name)
+	query := autoscaler.KEDA.Query
+	if query == "" {
+		// every target allocator replica reports the targets of every collector, so they are counted once per collector
+		query = fmt.Sprintf(`sum(max by (collector_name) (%s{collector_name=~"%s-[0-9]+"}))`, targetsPerCollectorMetric, regexp.QuoteMeta(name))
+	}
+
+	trigger := kedav1alpha1.ScaleTriggers{
+		Type: "prometheus",
+		Name: targetsPerCollectorMetric,
+		Metadata: map[string]string{
+			"serverAddress": autoscaler.KEDA.ServerAddress,
+			"query":         query,
+			"threshold":     strconv.Itoa(int(autoscaler.KEDA.TargetsPerCollector)),
+		},
+		MetricType: autoscalingv2.AverageValueMetricType,
+	}
+
+	minReplicas := autoscaler.MinReplicas
+	if minReplicas == nil {
+		minReplicas = params.OtelCol.Spec.Replicas
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        naming.ScaledObject(params.OtelCol.Name),
+			Namespace:   params.OtelCol.Namespace,
+			Labels:      manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter()),
+			Annotations: Annotations(params.OtelCol),
+		},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "AmazonCloudWatchAgent",
+				Name:       naming.AmazonCloudWatchAgent(params.OtelCol.Name),
+			},
+			PollingInterval: autoscaler.KEDA.PollingInterval,
+			MinReplicaCount: minReplicas,
+			MaxReplicaCount: autoscaler.MaxReplicas,
+			Triggers:        []kedav1alpha1.ScaleTriggers{trigger},
+		},
+	}
+	if autoscaler.Behavior != nil {
+		scaledObject.Spec.Advanced = &kedav1alpha1.AdvancedConfig{
+			HorizontalPodAutoscalerConfig: &kedav1alpha1.HorizontalPodAutoscalerConfig{Behavior: autoscaler.Behavior},
+		}
+	}
+	return scaledObject
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	kedav1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/internal/keda/v1alpha1"
+)
+
+func TestScaledObject(t *testing.T) {
+	params := paramsWithMode(v1alpha1.ModeStatefulSet)
+	params.Config = config.New(config.WithKEDAAvailable(true))
+	params.OtelCol.Spec.Autoscaler = &v1alpha1.AutoscalerSpec{
+		MinReplicas: ptr.To(int32(2)),
+		MaxReplicas: ptr.To(int32(10)),
+		Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+			ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: ptr.To(int32(600))},
+		},
+	}
+	assert.Nil(t, ScaledObject(params))
+
+	params.OtelCol.Spec.Autoscaler.Mode = v1alpha1.AutoscalerModeKEDA
+	params.OtelCol.Spec.Autoscaler.KEDA = &v1alpha1.KEDAScalerSpec{
+		ServerAddress:       "http://prometheus.monitoring:9090",
+		TargetsPerCollector: 100,
+		PollingInterval:     ptr.To(int32(60)),
+	}
+	scaledObject, ok := ScaledObject(params).(*kedav1alpha1.ScaledObject)
+	require.True(t, ok)
+	assert.Equal(t, "test", scaledObject.Name)
+	assert.Equal(t, "default", scaledObject.Namespace)
+	assert.Equal(t, &kedav1alpha1.ScaleTarget{
+		APIVersion: "cloudwatch.aws.amazon.com/v1alpha1",
+		Kind:       "AmazonCloudWatchAgent",
+		Name:       "test",
+	}, scaledObject.Spec.ScaleTargetRef)
+	assert.Equal(t, ptr.To(int32(2)), scaledObject.Spec.MinReplicaCount)
+	assert.Equal(t, ptr.To(int32(10)), scaledObject.Spec.MaxReplicaCount)
+	assert.Equal(t, ptr.To(int32(60)), scaledObject.Spec.PollingInterval)
+	assert.Equal(t, params.OtelCol.Spec.Autoscaler.Behavior, scaledObject.Spec.Advanced.HorizontalPodAutoscalerConfig.Behavior)
+	assert.Equal(t, []kedav1alpha1.ScaleTriggers{{
+		Type: "prometheus",
+		Name: "cloudwatch_agent_allocator_targets_per_collector",
+		Metadata: map[string]string{
+			"serverAddress": "http://prometheus.monitoring:9090",
+			"query":         `sum(max by (collector_name) (cloudwatch_agent_allocator_targets_per_collector{collector_name=~"test-[0-9]+"}))`,
+			"threshold":     "100",
+		},
+		MetricType: autoscalingv2.AverageValueMetricType,
+	}}, scaledObject.Spec.Triggers)
+
+	params.OtelCol.Spec.Autoscaler.KEDA.Query = "sum(targets)"
+	scaledObject = ScaledObject(params).(*kedav1alpha1.ScaledObject)
+	assert.Equal(t, "sum(targets)", scaledObject.Spec.Triggers[0].Metadata["query"])
+
+	// KEDA is not installed
+	params.Config = config.New()
+	assert.Nil(t, ScaledObject(params))
+}
@@ -20,6 +20,7 @@ func StatefulSet(params manifests.Params) *appsv1.StatefulSet {
 
 	annotations := Annotations(params.OtelCol)
 	podAnnotations := PodAnnotations(params.OtelCol)
+	podAnnotations[manifestutils.ConfigChecksumAnnotation] = configChecksum(params)
 
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
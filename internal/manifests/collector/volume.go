@@ -28,14 +28,23 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 		})
 	}
 
-	volumes := []corev1.Volume{{
-		Name: naming.ConfigMapVolume(),
-		VolumeSource: corev1.VolumeSource{
-			ConfigMap: &corev1.ConfigMapVolumeSource{
-				LocalObjectReference: corev1.LocalObjectReference{Name: naming.ConfigMap(otelcol.Name)},
-				Items:                items,
-			},
+	configVolumeSource := corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: naming.ConfigMap(otelcol.Name)},
+			Items:                items,
 		},
+	}
+	if ConfigInSecret(otelcol) {
+		configVolumeSource = corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: naming.ConfigSecret(otelcol.Name),
+				Items:      items,
+			},
+		}
+	}
+	volumes := []corev1.Volume{{
+		Name:         naming.ConfigMapVolume(),
+		VolumeSource: configVolumeSource,
 	}}
 
 	if !otelcol.Spec.Prometheus.IsEmpty() {
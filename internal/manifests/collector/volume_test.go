@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
@@ -119,3 +120,24 @@ func TestVolumeNoPrometheus(t *testing.T) {
 	// check that it's not the prometheus-config volume, with the config map
 	assert.NotEqual(t, naming.PrometheusConfigMapVolume(), volumes[0].Name)
 }
+
+func TestVolumeConfigFromSecret(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ConfigFrom: &v1alpha1.ConfigSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "agent"}, Key: "config"},
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	volumes := Volumes(cfg, otelcol)
+
+	// verify
+	assert.Len(t, volumes, 1)
+	assert.Nil(t, volumes[0].ConfigMap)
+	assert.Equal(t, naming.ConfigSecret("test"), volumes[0].Secret.SecretName)
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
 // Annotations return the annotations for DcgmExporter pod.
@@ -29,6 +31,19 @@ func Annotations(instance v1alpha1.DcgmExporter) map[string]string {
 	return annotations
 }
 
+// PodAnnotations return the annotations for the DcgmExporter pod template.
+func PodAnnotations(params manifests.Params) map[string]string {
+	annotations := map[string]string{}
+	configMap, err := ConfigMap(params)
+	if err != nil {
+		params.Log.V(2).Info("failed to build the config map for the config checksum", "err", err)
+		return annotations
+	}
+	// make sure the pods are restarted when the content of the configMap changes
+	annotations[manifestutils.ConfigChecksumAnnotation] = manifestutils.ConfigChecksum(configMap)
+	return annotations
+}
+
 func getConfigMapSHA(config string) string {
 	h := sha256.Sum256([]byte(config))
 	return fmt.Sprintf("%x", h)
@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConfigChecksumAnnotation is set on the pod templates to the checksum of the config maps mounted by the pods, so
+// that the pods are restarted when the content of the config maps changes.
+const ConfigChecksumAnnotation = "amazon-cloudwatch-agent-operator-config/checksum"
+
+// ConfigChecksum returns the checksum of the content of the config maps, in the given order. Nil config maps are
+// skipped.
+func ConfigChecksum(configMaps ...*corev1.ConfigMap) string {
+	h := sha256.New()
+	for _, cm := range configMaps {
+		if cm == nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00", cm.Name)
+		keys := make([]string, 0, len(cm.Data))
+		for k := range cm.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s\x00%s\x00", k, cm.Data[k])
+		}
+		keys = keys[:0]
+		for k := range cm.BinaryData {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s\x00", k)
+			h.Write(cm.BinaryData[k])
+			h.Write([]byte{0})
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
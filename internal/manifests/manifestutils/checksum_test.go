@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifestutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigChecksum(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Data:       map[string]string{"a": "1", "b": "2"},
+	}
+	checksum := ConfigChecksum(cm)
+	assert.Equal(t, checksum, ConfigChecksum(cm.DeepCopy()))
+	assert.Equal(t, checksum, ConfigChecksum(cm, nil))
+
+	changed := cm.DeepCopy()
+	changed.Data["b"] = "3"
+	assert.NotEqual(t, checksum, ConfigChecksum(changed))
+
+	// moving a value to another key changes the checksum
+	moved := cm.DeepCopy()
+	moved.Data = map[string]string{"a": "12"}
+	assert.NotEqual(t, checksum, ConfigChecksum(moved))
+
+	binary := cm.DeepCopy()
+	binary.BinaryData = map[string][]byte{"c": {0x1}}
+	assert.NotEqual(t, checksum, ConfigChecksum(binary))
+
+	extra := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "extra"}, Data: map[string]string{"c": "3"}}
+	assert.NotEqual(t, checksum, ConfigChecksum(cm, extra))
+}
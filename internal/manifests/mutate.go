@@ -20,6 +20,9 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	kedav1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/internal/keda/v1alpha1"
 )
 
 var (
@@ -44,6 +47,10 @@ var (
 // - HorizontalPodAutoscaler
 // - Route
 // - Secret
+// - HTTPRoute
+// - GRPCRoute
+// - BackendTLSPolicy
+// - ScaledObject
 // In order for the operator to reconcile other types, they must be added here.
 // The function returned takes no arguments but instead uses the existing and desired inputs here. Existing is expected
 // to be set by the controller-runtime package through a client get call.
@@ -155,6 +162,26 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			wantPr := desired.(*corev1.Secret)
 			mutateSecret(pr, wantPr)
 
+		case *gatewayv1.HTTPRoute:
+			rt := existing.(*gatewayv1.HTTPRoute)
+			wantRt := desired.(*gatewayv1.HTTPRoute)
+			mutateHTTPRoute(rt, wantRt)
+
+		case *gatewayv1.GRPCRoute:
+			rt := existing.(*gatewayv1.GRPCRoute)
+			wantRt := desired.(*gatewayv1.GRPCRoute)
+			mutateGRPCRoute(rt, wantRt)
+
+		case *gatewayv1.BackendTLSPolicy:
+			pol := existing.(*gatewayv1.BackendTLSPolicy)
+			wantPol := desired.(*gatewayv1.BackendTLSPolicy)
+			mutateBackendTLSPolicy(pol, wantPol)
+
+		case *kedav1alpha1.ScaledObject:
+			so := existing.(*kedav1alpha1.ScaledObject)
+			wantSo := desired.(*kedav1alpha1.ScaledObject)
+			mutateScaledObject(so, wantSo)
+
 		default:
 			t := reflect.TypeOf(existing).String()
 			return fmt.Errorf("missing mutate implementation for resource type: %s", t)
@@ -233,6 +260,30 @@ func mutateRoute(existing, desired *routev1.Route) {
 	existing.Spec = desired.Spec
 }
 
+func mutateHTTPRoute(existing, desired *gatewayv1.HTTPRoute) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
+func mutateGRPCRoute(existing, desired *gatewayv1.GRPCRoute) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
+func mutateBackendTLSPolicy(existing, desired *gatewayv1.BackendTLSPolicy) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
+func mutateScaledObject(existing, desired *kedav1alpha1.ScaledObject) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
 func mutateServiceMonitor(existing, desired *monitoringv1.ServiceMonitor) {
 	existing.Annotations = desired.Annotations
 	existing.Labels = desired.Labels
@@ -289,7 +340,7 @@ func mutateDeployment(existing, desired *appsv1.Deployment) error {
 }
 
 func mutateStatefulSet(existing, desired *appsv1.StatefulSet) error {
-	if hasChange, field := hasImmutableFieldChange(existing, desired); hasChange {
+	if hasChange, field := HasImmutableFieldChange(existing, desired); hasChange {
 		return fmt.Errorf("%s is being changed, %w", field, ErrImmutableChange)
 	}
 	// StatefulSet selector is immutable so we set this value only if
@@ -311,7 +362,9 @@ func mutateStatefulSet(existing, desired *appsv1.StatefulSet) error {
 	return nil
 }
 
-func hasImmutableFieldChange(existing, desired *appsv1.StatefulSet) (bool, string) {
+// HasImmutableFieldChange returns whether the desired StatefulSet changes a field of the existing one which can't be
+// updated, along with a description of the field.
+func HasImmutableFieldChange(existing, desired *appsv1.StatefulSet) (bool, string) {
 	if existing.CreationTimestamp.IsZero() {
 		return false, ""
 	}
@@ -10,6 +10,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
 func TestDefaultAnnotations(t *testing.T) {
@@ -51,3 +53,21 @@ func TestUserAnnotations(t *testing.T) {
 	assert.Equal(t, "neuron-monitor", annotations["k8s-app"])
 	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", annotations["amazon-cloudwatch-agent-operator-config/sha256"])
 }
+
+func TestPodAnnotations(t *testing.T) {
+	params := manifests.Params{
+		NeuronExp: v1alpha1.NeuronMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-instance",
+				Namespace: "my-ns",
+			},
+			Spec: v1alpha1.NeuronMonitorSpec{MonitorConfig: "config"},
+		},
+	}
+	checksum := PodAnnotations(params)[manifestutils.ConfigChecksumAnnotation]
+	assert.NotEmpty(t, checksum)
+	assert.Equal(t, checksum, DaemonSet(params).Spec.Template.Annotations[manifestutils.ConfigChecksumAnnotation])
+
+	params.NeuronExp.Spec.MonitorConfig = "changed"
+	assert.NotEqual(t, checksum, PodAnnotations(params)[manifestutils.ConfigChecksumAnnotation])
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/nodediscovery"
 )
 
 // DaemonSet builds the deployment for the given instance.
@@ -34,7 +35,8 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: PodAnnotations(params),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccountName(params.NeuronExp),
@@ -42,7 +44,7 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 					Volumes:            Volumes(params.NeuronExp),
 					Tolerations:        params.NeuronExp.Spec.Tolerations,
 					NodeSelector:       params.NeuronExp.Spec.NodeSelector,
-					Affinity:           params.NeuronExp.Spec.Affinity,
+					Affinity:           nodediscovery.Affinity(params.NeuronExp.Spec.Affinity, params.NodeDiscovery),
 				},
 			},
 		},
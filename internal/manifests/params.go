@@ -5,12 +5,15 @@ package manifests
 
 import (
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/nodediscovery"
 )
 
 // Params holds the reconciliation-specific parameters.
@@ -23,4 +26,13 @@ type Params struct {
 	DcgmExp   v1alpha1.DcgmExporter
 	NeuronExp v1alpha1.NeuronMonitor
 	Config    config.Config
+	// NodeDiscovery holds the nodes found for the DcgmExporter or NeuronMonitor when its node discovery is enabled.
+	NodeDiscovery *nodediscovery.Result
+	// Rollout holds the progress of the canary rollout of the AmazonCloudWatchAgent configuration.
+	Rollout *v1alpha1.RolloutStatus
+	// ExtraConfigMaps are the config maps of the AmazonCloudWatchAgent spec.configmaps, which are part of the config
+	// checksum of its pods.
+	ExtraConfigMaps []corev1.ConfigMap
+	// Conditions are set on the status of the AmazonCloudWatchAgent once its objects are reconciled.
+	Conditions []metav1.Condition
 }
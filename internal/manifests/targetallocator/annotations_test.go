@@ -4,8 +4,6 @@
 package targetallocator
 
 import (
-	"crypto/sha256"
-	"fmt"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -14,6 +12,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
 func TestPodAnnotations(t *testing.T) {
@@ -35,18 +34,15 @@ func TestConfigMapHash(t *testing.T) {
 	}
 	expectedConfigMap, err := ConfigMap(params)
 	require.NoError(t, err)
-	expectedConfig := expectedConfigMap.Data[targetAllocatorFilename]
-	require.NotEmpty(t, expectedConfig)
-	expectedHash := sha256.Sum256([]byte(expectedConfig))
+	require.NotEmpty(t, expectedConfigMap.Data[targetAllocatorFilename])
 	annotations := Annotations(instance, expectedConfigMap)
-	require.Contains(t, annotations, configMapHashAnnotationKey)
-	cmHash := annotations[configMapHashAnnotationKey]
-	assert.Equal(t, fmt.Sprintf("%x", expectedHash), cmHash)
+	require.Contains(t, annotations, manifestutils.ConfigChecksumAnnotation)
+	assert.Equal(t, manifestutils.ConfigChecksum(expectedConfigMap), annotations[manifestutils.ConfigChecksumAnnotation])
 }
 
 func TestInvalidConfigNoHash(t *testing.T) {
 	instance := collectorInstance()
 	instance.Spec.Config = ""
 	annotations := Annotations(instance, nil)
-	require.NotContains(t, annotations, configMapHashAnnotationKey)
+	require.NotContains(t, annotations, manifestutils.ConfigChecksumAnnotation)
 }
@@ -5,8 +5,10 @@ package targetallocator
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -73,6 +75,27 @@ func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
 		taConfig["prometheus_cr"] = prometheusCRConfig
 	}
 
+	if chCfg := params.OtelCol.Spec.TargetAllocator.ConsistentHashing; chCfg != nil {
+		consistentHashingConfig := make(map[interface{}]interface{})
+		if chCfg.PartitionCount != nil {
+			consistentHashingConfig["partition_count"] = *chCfg.PartitionCount
+		}
+		if len(chCfg.LoadFactor) > 0 {
+			loadFactor, err := strconv.ParseFloat(chCfg.LoadFactor, 64)
+			if err != nil {
+				return &corev1.ConfigMap{}, fmt.Errorf("invalid consistent hashing load factor %q: %w", chCfg.LoadFactor, err)
+			}
+			consistentHashingConfig["load_factor"] = loadFactor
+		}
+		if chCfg.DrainTimeout != nil {
+			// the target allocator parses the Prometheus duration format, which has no fractional values like 1.5s
+			consistentHashingConfig["drain_timeout"] = model.Duration(chCfg.DrainTimeout.Duration).String()
+		}
+		if len(consistentHashingConfig) > 0 {
+			taConfig["consistent_hashing"] = consistentHashingConfig
+		}
+	}
+
 	taConfigYAML, err := yaml.Marshal(taConfig)
 	if err != nil {
 		return &corev1.ConfigMap{}, err
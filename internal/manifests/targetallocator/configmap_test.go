@@ -8,9 +8,13 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
@@ -145,5 +149,74 @@ prometheus_cr:
 		assert.Equal(t, expectedData, actual.Data)
 
 	})
+	t.Run("should return expected target allocator config map with consistent hashing tuned", func(t *testing.T) {
+		expectedLables["app.kubernetes.io/component"] = "amazon-cloudwatch-agent-target-allocator"
+		expectedLables["app.kubernetes.io/name"] = "my-instance-target-allocator"
+
+		expectedData := map[string]string{
+			"targetallocator.yaml": `allocation_strategy: consistent-hashing
+config:
+  scrape_configs:
+  - job_name: otel-collector
+    scrape_interval: 10s
+    static_configs:
+    - targets:
+      - 0.0.0.0:8888
+      - 0.0.0.0:9999
+consistent_hashing:
+  drain_timeout: 1m
+  load_factor: 1.25
+  partition_count: 271
+label_selector:
+  app.kubernetes.io/component: amazon-cloudwatch-agent
+  app.kubernetes.io/instance: default.my-instance
+  app.kubernetes.io/managed-by: amazon-cloudwatch-agent-operator
+  app.kubernetes.io/part-of: amazon-cloudwatch-agent
+`,
+		}
+
+		partitionCount := int32(271)
+		collector := collectorInstance()
+		collector.Spec.TargetAllocator.ConsistentHashing = &v1alpha1.AmazonCloudWatchAgentTargetAllocatorConsistentHashing{
+			PartitionCount: &partitionCount,
+			LoadFactor:     "1.25",
+			DrainTimeout:   &metav1.Duration{Duration: time.Minute},
+		}
+		cfg := config.New()
+		params := manifests.Params{
+			OtelCol: collector,
+			Config:  cfg,
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "my-instance-target-allocator", actual.Name)
+		assert.Equal(t, expectedLables, actual.Labels)
+		assert.Equal(t, expectedData, actual.Data)
+
+	})
+
+	t.Run("should write a drain timeout the target allocator parses", func(t *testing.T) {
+		collector := collectorInstance()
+		collector.Spec.TargetAllocator.ConsistentHashing = &v1alpha1.AmazonCloudWatchAgentTargetAllocatorConsistentHashing{
+			DrainTimeout: &metav1.Duration{Duration: 1500 * time.Millisecond},
+		}
+		params := manifests.Params{
+			OtelCol: collector,
+			Config:  config.New(),
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		require.NoError(t, err)
+
+		taConfig := struct {
+			ConsistentHashing struct {
+				DrainTimeout model.Duration `yaml:"drain_timeout"`
+			} `yaml:"consistent_hashing"`
+		}{}
+		require.NoError(t, yaml.Unmarshal([]byte(actual.Data["targetallocator.yaml"]), &taConfig))
+		assert.Equal(t, model.Duration(1500*time.Millisecond), taConfig.ConsistentHashing.DrainTimeout)
+	})
 
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
 var testTolerationValues = []v1.Toleration{
@@ -135,8 +136,8 @@ func TestDeploymentNewDefault(t *testing.T) {
 
 	assert.Len(t, d.Spec.Template.Spec.Containers, 1)
 
-	// should only have the ConfigMap hash annotation
-	assert.Contains(t, d.Spec.Template.Annotations, configMapHashAnnotationKey)
+	// should only have the ConfigMap checksum annotation
+	assert.Contains(t, d.Spec.Template.Annotations, manifestutils.ConfigChecksumAnnotation)
 	assert.Len(t, d.Spec.Template.Annotations, 1)
 
 	// the pod selector should match the pod spec's labels
@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	policyV1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// PodDisruptionBudget builds the pdb for the target allocator deployment. Only consistent-hashing produces the same
+// assignment on every replica, so other strategies do not get a pdb.
+func PodDisruptionBudget(params manifests.Params) *policyV1.PodDisruptionBudget {
+	taSpec := params.OtelCol.Spec.TargetAllocator
+	// defaulting webhook should always set this, but if unset then return nil.
+	if taSpec.PodDisruptionBudget == nil {
+		params.Log.Info("pdb field is unset in Spec, skipping target allocator podDisruptionBudget creation")
+		return nil
+	}
+	if taSpec.AllocationStrategy != "" && taSpec.AllocationStrategy != v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing {
+		params.Log.V(4).Info("current allocation strategy not compatible, skipping target allocator podDisruptionBudget creation", "strategy", taSpec.AllocationStrategy)
+		return nil
+	}
+
+	name := naming.TargetAllocator(params.OtelCol.Name)
+	labels := Labels(params.OtelCol, name)
+
+	return &policyV1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.TAPodDisruptionBudget(params.OtelCol.Name),
+			Namespace: params.OtelCol.Namespace,
+			Labels:    labels,
+		},
+		Spec: policyV1.PodDisruptionBudgetSpec{
+			MinAvailable:   taSpec.PodDisruptionBudget.MinAvailable,
+			MaxUnavailable: taSpec.PodDisruptionBudget.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestPDB(t *testing.T) {
+	tests := []struct {
+		name           string
+		MinAvailable   *intstr.IntOrString
+		MaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name: "MinAvailable-int",
+			MinAvailable: &intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 1,
+			},
+		},
+		{
+			name: "MaxUnavailable-string",
+			MaxUnavailable: &intstr.IntOrString{
+				Type:   intstr.String,
+				StrVal: "10%",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			otelcol := v1alpha1.AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-instance",
+					Namespace: "my-namespace",
+				},
+				Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+					TargetAllocator: v1alpha1.AmazonCloudWatchAgentTargetAllocator{
+						Enabled: true,
+						PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetSpec{
+							MinAvailable:   test.MinAvailable,
+							MaxUnavailable: test.MaxUnavailable,
+						},
+					},
+				},
+			}
+			pdb := PodDisruptionBudget(manifests.Params{
+				Log:     logger,
+				Config:  config.New(),
+				OtelCol: otelcol,
+			})
+
+			assert.Equal(t, "my-instance-target-allocator", pdb.Name)
+			assert.Equal(t, "my-namespace", pdb.Namespace)
+			assert.Equal(t, "my-instance-target-allocator", pdb.Labels["app.kubernetes.io/name"])
+			assert.Equal(t, "amazon-cloudwatch-agent-target-allocator", pdb.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+			assert.NotContains(t, pdb.Spec.Selector.MatchLabels, "app.kubernetes.io/version")
+			assert.Equal(t, test.MinAvailable, pdb.Spec.MinAvailable)
+			assert.Equal(t, test.MaxUnavailable, pdb.Spec.MaxUnavailable)
+		})
+	}
+}
+
+func TestPDBUnset(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			TargetAllocator: v1alpha1.AmazonCloudWatchAgentTargetAllocator{
+				Enabled: true,
+			},
+		},
+	}
+	pdb := PodDisruptionBudget(manifests.Params{
+		Log:     logger,
+		Config:  config.New(),
+		OtelCol: otelcol,
+	})
+	assert.Nil(t, pdb)
+}
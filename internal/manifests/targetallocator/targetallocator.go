@@ -20,6 +20,7 @@ func Build(params manifests.Params) ([]client.Object, error) {
 		manifests.Factory(Deployment),
 		manifests.FactoryWithoutError(ServiceAccount),
 		manifests.FactoryWithoutError(Service),
+		manifests.FactoryWithoutError(PodDisruptionBudget),
 	}
 	for _, factory := range resourceFactories {
 		res, err := factory(params)
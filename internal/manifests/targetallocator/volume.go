@@ -30,7 +30,7 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 			Name: naming.TAClientVolume(),
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: "amazon-cloudwatch-observability-agent-ta-client-cert",
+					SecretName: naming.TAClientCertSecret(),
 					Items: []corev1.KeyToPath{
 						{
 							Key:  "ca.crt",
@@ -44,7 +44,7 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 			Name: naming.TASecretVolume(),
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: "amazon-cloudwatch-observability-agent-cert",
+					SecretName: naming.TAServerCertSecret(),
 					Items: []corev1.KeyToPath{
 						{
 							Key:  "tls.crt",
@@ -9,6 +9,12 @@ func ConfigMap(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
 
+// ConfigSecret builds the name for the secret holding the configuration of the AmazonCloudWatchAgent containers when
+// it is read from a secret.
+func ConfigSecret(otelcol string) string {
+	return DNSName(Truncate("%s-config", 63, otelcol))
+}
+
 // TAConfigMap returns the name for the config map used in the TargetAllocator.
 func TAConfigMap(otelcol string) string {
 	return DNSName(Truncate("%s-target-allocator", 63, otelcol))
@@ -42,6 +48,22 @@ func TAClientVolume() string {
 	return "ta-client"
 }
 
+// TAServerCertSecret returns the name of the secret holding the serving certificate of the TargetAllocator.
+func TAServerCertSecret() string {
+	return "amazon-cloudwatch-observability-agent-cert"
+}
+
+// TAClientCertSecret returns the name of the secret holding the client certificate of the collectors along with the
+// CA bundle the TargetAllocator verifies them with.
+func TAClientCertSecret() string {
+	return "amazon-cloudwatch-observability-agent-ta-client-cert"
+}
+
+// TACASecret returns the name of the secret holding the CA issuing the certificates of the TargetAllocator.
+func TACASecret() string {
+	return "amazon-cloudwatch-observability-agent-ta-ca"
+}
+
 // PrometheusConfigMapVolume returns the name to use for the prometheus config map's volume in the pod.
 func PrometheusConfigMapVolume() string {
 	return "prometheus-config"
@@ -62,6 +84,22 @@ func Collector(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
 
+// CanaryCollector builds the name of the daemonset running the configuration being canaried.
+func CanaryCollector(otelcol string) string {
+	return DNSName(Truncate("%s-canary", 63, otelcol))
+}
+
+// CanaryConfigMap builds the name of the config map holding the configuration being canaried.
+func CanaryConfigMap(otelcol string) string {
+	return DNSName(Truncate("%s-canary", 63, otelcol))
+}
+
+// CanaryConfigSecret builds the name of the secret holding the configuration being canaried when it is read from a
+// secret.
+func CanaryConfigSecret(otelcol string) string {
+	return DNSName(Truncate("%s-canary-config", 63, otelcol))
+}
+
 // HorizontalPodAutoscaler builds the autoscaler name based on the instance.
 func HorizontalPodAutoscaler(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
@@ -102,6 +140,11 @@ func Service(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
 
+// ScaledObject builds the KEDA scaled object name based on the instance.
+func ScaledObject(otelcol string) string {
+	return DNSName(Truncate("%s", 63, otelcol))
+}
+
 // Ingress builds the ingress name based on the instance.
 func Ingress(otelcol string) string {
 	return DNSName(Truncate("%s-ingress", 63, otelcol))
@@ -112,6 +155,16 @@ func Route(otelcol string, prefix string) string {
 	return DNSName(Truncate("%s-%s-route", 63, prefix, otelcol))
 }
 
+// BackendTLSPolicy builds the Gateway API backend TLS policy name based on the instance.
+func BackendTLSPolicy(otelcol string) string {
+	return DNSName(Truncate("%s-backend-tls", 63, otelcol))
+}
+
+// TAPodDisruptionBudget returns the name to use for the TargetAllocator pod disruption budget.
+func TAPodDisruptionBudget(otelcol string) string {
+	return DNSName(Truncate("%s-target-allocator", 63, otelcol))
+}
+
 // TAService returns the name to use for the TargetAllocator service.
 func TAService(otelcol string) string {
 	return DNSName(Truncate("%s-target-allocator-service", 63, otelcol))
@@ -0,0 +1,195 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nodediscovery finds the nodes with accelerators and builds the node affinity targeting them.
+package nodediscovery
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	// nodeNameField is the node field selected when an eligible node has no instance type label.
+	nodeNameField = "metadata.name"
+)
+
+// Defaults are the resources and labels identifying the nodes of an accelerator when the NodeDiscovery leaves them
+// unset.
+type Defaults struct {
+	Resources  []corev1.ResourceName
+	NodeLabels map[string]string
+}
+
+var (
+	// DcgmExporterDefaults target the nodes with NVIDIA GPUs.
+	DcgmExporterDefaults = Defaults{
+		Resources:  []corev1.ResourceName{"nvidia.com/gpu"},
+		NodeLabels: map[string]string{"feature.node.kubernetes.io/pci-10de.present": "true"},
+	}
+	// NeuronMonitorDefaults target the nodes with AWS Neuron devices.
+	NeuronMonitorDefaults = Defaults{
+		Resources: []corev1.ResourceName{"aws.amazon.com/neuron", "aws.amazon.com/neuroncore", "aws.amazon.com/neurondevice"},
+	}
+)
+
+// Result holds the nodes found by the discovery and the node affinity targeting them.
+type Result struct {
+	NodeAffinity  *corev1.NodeAffinity
+	EligibleNodes int32
+}
+
+// Enabled reports whether the node discovery is enabled.
+func Enabled(discovery *v1alpha1.NodeDiscovery) bool {
+	return discovery != nil && discovery.Enabled
+}
+
+// Discover lists the nodes and returns those eligible for the discovery along with the node affinity targeting them.
+func Discover(ctx context.Context, c client.Client, discovery *v1alpha1.NodeDiscovery, defaults Defaults) (*Result, error) {
+	var nodes corev1.NodeList
+	if err := c.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+	resources, nodeLabels := resolve(discovery, defaults)
+	eligible := EligibleNodes(nodes.Items, resources, nodeLabels)
+	return &Result{
+		NodeAffinity:  NodeAffinity(eligible, nodeLabels),
+		EligibleNodes: int32(len(eligible)),
+	}, nil
+}
+
+func resolve(discovery *v1alpha1.NodeDiscovery, defaults Defaults) ([]corev1.ResourceName, map[string]string) {
+	resources, nodeLabels := defaults.Resources, defaults.NodeLabels
+	if discovery != nil && len(discovery.Resources) > 0 {
+		resources = discovery.Resources
+	}
+	if discovery != nil && discovery.NodeLabels != nil {
+		nodeLabels = discovery.NodeLabels
+	}
+	return resources, nodeLabels
+}
+
+// IsEligible reports whether the node has any of the allocatable resources or any of the labels.
+func IsEligible(node corev1.Node, resources []corev1.ResourceName, nodeLabels map[string]string) bool {
+	for _, resource := range resources {
+		if quantity, ok := node.Status.Allocatable[resource]; ok && !quantity.IsZero() {
+			return true
+		}
+	}
+	for key, value := range nodeLabels {
+		if node.Labels[key] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// EligibleNodes returns the nodes having any of the allocatable resources or any of the labels.
+func EligibleNodes(nodes []corev1.Node, resources []corev1.ResourceName, nodeLabels map[string]string) []corev1.Node {
+	var eligible []corev1.Node
+	for _, node := range nodes {
+		if IsEligible(node, resources, nodeLabels) {
+			eligible = append(eligible, node)
+		}
+	}
+	return eligible
+}
+
+// NodeAffinity requires the instance types of the eligible nodes or any of the labels. Targeting instance types rather
+// than nodes keeps the pod template unchanged as nodes of a known instance type come and go, so the DaemonSet isn't
+// rolled out every time the cluster scales.
+func NodeAffinity(eligible []corev1.Node, nodeLabels map[string]string) *corev1.NodeAffinity {
+	instanceTypes := map[string]bool{}
+	var nodeNames []string
+	for _, node := range eligible {
+		if instanceType, ok := node.Labels[corev1.LabelInstanceTypeStable]; ok {
+			instanceTypes[instanceType] = true
+		} else {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+
+	var terms []corev1.NodeSelectorTerm
+	if len(instanceTypes) > 0 {
+		terms = append(terms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      corev1.LabelInstanceTypeStable,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   sortedKeys(instanceTypes),
+			}},
+		})
+	}
+	if len(nodeNames) > 0 {
+		sort.Strings(nodeNames)
+		terms = append(terms, corev1.NodeSelectorTerm{
+			MatchFields: []corev1.NodeSelectorRequirement{{
+				Key:      nodeNameField,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   nodeNames,
+			}},
+		})
+	}
+	labelKeys := make(map[string]bool, len(nodeLabels))
+	for key := range nodeLabels {
+		labelKeys[key] = true
+	}
+	for _, key := range sortedKeys(labelKeys) {
+		terms = append(terms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{nodeLabels[key]},
+			}},
+		})
+	}
+	if len(terms) == 0 {
+		// every node has a hostname label, so this term keeps the DaemonSet off every node until one is eligible
+		terms = append(terms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      corev1.LabelHostname,
+				Operator: corev1.NodeSelectorOpDoesNotExist,
+			}},
+		})
+	}
+	return &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+	}
+}
+
+// Affinity returns a copy of affinity whose required node affinity is replaced by the discovered one.
+func Affinity(affinity *corev1.Affinity, result *Result) *corev1.Affinity {
+	if result == nil {
+		return affinity
+	}
+	merged := &corev1.Affinity{}
+	if affinity != nil {
+		merged = affinity.DeepCopy()
+	}
+	if merged.NodeAffinity == nil {
+		merged.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	return merged
+}
+
+// NodeChanged reports whether an update of a node may change its eligibility. Nodes are updated every few seconds
+// for their heartbeats, which must not trigger a reconciliation.
+func NodeChanged(oldNode, newNode *corev1.Node) bool {
+	return !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodediscovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func node(name, instanceType string, labels map[string]string, allocatable corev1.ResourceList) corev1.Node {
+	nodeLabels := map[string]string{}
+	if instanceType != "" {
+		nodeLabels[corev1.LabelInstanceTypeStable] = instanceType
+	}
+	for k, v := range labels {
+		nodeLabels[k] = v
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Status:     corev1.NodeStatus{Allocatable: allocatable},
+	}
+}
+
+var (
+	gpuNode      = node("gpu", "g5.xlarge", nil, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")})
+	otherGPUNode = node("other-gpu", "p4d.24xlarge", nil, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")})
+	nfdNode      = node("nfd", "g6.xlarge", map[string]string{"feature.node.kubernetes.io/pci-10de.present": "true"}, nil)
+	noTypeNode   = node("no-type", "", nil, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")})
+	zeroGPUNode  = node("zero-gpu", "m5.xlarge", nil, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("0")})
+	cpuNode      = node("cpu", "m5.xlarge", nil, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})
+	neuronNode   = node("neuron", "inf2.xlarge", nil, corev1.ResourceList{"aws.amazon.com/neuroncore": resource.MustParse("2")})
+)
+
+func TestEligibleNodes(t *testing.T) {
+	nodes := []corev1.Node{gpuNode, nfdNode, zeroGPUNode, cpuNode, neuronNode}
+	eligible := EligibleNodes(nodes, DcgmExporterDefaults.Resources, DcgmExporterDefaults.NodeLabels)
+	assert.Equal(t, []corev1.Node{gpuNode, nfdNode}, eligible)
+
+	eligible = EligibleNodes(nodes, NeuronMonitorDefaults.Resources, NeuronMonitorDefaults.NodeLabels)
+	assert.Equal(t, []corev1.Node{neuronNode}, eligible)
+}
+
+func TestNodeAffinity(t *testing.T) {
+	affinity := NodeAffinity([]corev1.Node{otherGPUNode, gpuNode, noTypeNode}, DcgmExporterDefaults.NodeLabels)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{{
+			Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"g5.xlarge", "p4d.24xlarge"},
+		}}},
+		{MatchFields: []corev1.NodeSelectorRequirement{{
+			Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"no-type"},
+		}}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{{
+			Key: "feature.node.kubernetes.io/pci-10de.present", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"},
+		}}},
+	}, affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// without eligible nodes nor labels the DaemonSet must not be scheduled anywhere
+	affinity = NodeAffinity(nil, nil)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpDoesNotExist}}},
+	}, affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+func TestAffinity(t *testing.T) {
+	result := &Result{NodeAffinity: NodeAffinity([]corev1.Node{gpuNode}, nil)}
+	preferred := []corev1.PreferredSchedulingTerm{{Weight: 1}}
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution:  &corev1.NodeSelector{},
+			PreferredDuringSchedulingIgnoredDuringExecution: preferred,
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{},
+	}
+
+	assert.Same(t, affinity, Affinity(affinity, nil))
+
+	merged := Affinity(affinity, result)
+	assert.Equal(t, result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	assert.Equal(t, preferred, merged.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	assert.NotNil(t, merged.PodAntiAffinity)
+	// the spec is left untouched
+	assert.Empty(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	merged = Affinity(nil, result)
+	assert.Equal(t, result.NodeAffinity, merged.NodeAffinity)
+}
+
+func TestDiscover(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&gpuNode, &otherGPUNode, &cpuNode, &neuronNode).Build()
+
+	result, err := Discover(context.Background(), c, &v1alpha1.NodeDiscovery{Enabled: true}, DcgmExporterDefaults)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), result.EligibleNodes)
+
+	// custom resources replace the defaults
+	result, err = Discover(context.Background(), c, &v1alpha1.NodeDiscovery{
+		Enabled:    true,
+		Resources:  []corev1.ResourceName{"aws.amazon.com/neuroncore"},
+		NodeLabels: map[string]string{},
+	}, DcgmExporterDefaults)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result.EligibleNodes)
+	assert.Equal(t, []string{"inf2.xlarge"}, result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values)
+}
+
+func TestNodeChanged(t *testing.T) {
+	updated := gpuNode.DeepCopy()
+	updated.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	assert.False(t, NodeChanged(&gpuNode, updated))
+
+	updated.Status.Allocatable = corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("0")}
+	assert.True(t, NodeChanged(&gpuNode, updated))
+
+	updated = gpuNode.DeepCopy()
+	updated.Labels["feature.node.kubernetes.io/pci-10de.present"] = "true"
+	assert.True(t, NodeChanged(&gpuNode, updated))
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package operatormetrics holds the Prometheus metrics describing the activity of the operator itself, registered in
+// the controller-runtime registry so that they are served along with its default metrics.
+package operatormetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "cloudwatch_agent_operator"
+
+	// OutcomeSuccess is the outcome of an operation which succeeded.
+	OutcomeSuccess = "success"
+	// OutcomeError is the outcome of an operation which failed.
+	OutcomeError = "error"
+
+	// ActionAnnotated is the auto-monitor action setting the injection annotations of a workload.
+	ActionAnnotated = "annotated"
+	// ActionRestarted is the auto-monitor action restarting a workload so that its pods get injected.
+	ActionRestarted = "restarted"
+)
+
+var (
+	podMutationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "pod_mutation_duration_seconds",
+		Help:      "Time taken by the pod mutators of the pod webhook, in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"mutator"})
+
+	podMutations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "pod_mutations_total",
+		Help:      "Number of pods handled by the pod mutators of the pod webhook, by outcome.",
+	}, []string{"mutator", "outcome"})
+
+	instrumentationInjections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "instrumentation_injections_total",
+		Help:      "Number of containers injected with auto-instrumentation, by language.",
+	}, []string{"language"})
+
+	instrumentationInjectionsSkipped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "instrumentation_injections_skipped_total",
+		Help:      "Number of auto-instrumentation injections skipped, by language and reason.",
+	}, []string{"language", "reason"})
+
+	instrumentationSelectionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "instrumentation_selection_errors_total",
+		Help:      "Number of pods for which no Instrumentation could be selected, by reason.",
+	}, []string{"reason"})
+
+	autoMonitorWorkloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "auto_monitor_workloads_total",
+		Help:      "Number of workloads annotated or restarted by auto-monitor and auto-annotation, by kind.",
+	}, []string{"kind", "action"})
+
+	certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "Expiry time of the certificates provisioned by the operator, in seconds since the epoch.",
+	}, []string{"namespace", "secret"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		podMutationDuration,
+		podMutations,
+		instrumentationInjections,
+		instrumentationInjectionsSkipped,
+		instrumentationSelectionErrors,
+		autoMonitorWorkloads,
+		certificateExpiry,
+	)
+}
+
+// RecordPodMutation records the duration and the outcome of a pod mutator.
+func RecordPodMutation(mutator string, duration time.Duration, err error) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	podMutationDuration.WithLabelValues(mutator).Observe(duration.Seconds())
+	podMutations.WithLabelValues(mutator, outcome).Inc()
+}
+
+// RecordInjection records a container injected with the auto-instrumentation of the language.
+func RecordInjection(language string) {
+	instrumentationInjections.WithLabelValues(language).Inc()
+}
+
+// RecordInjectionSkipped records an auto-instrumentation injection skipped for the reason.
+func RecordInjectionSkipped(language, reason string) {
+	instrumentationInjectionsSkipped.WithLabelValues(language, reason).Inc()
+}
+
+// RecordInstrumentationSelectionError records a pod for which no Instrumentation could be selected.
+func RecordInstrumentationSelectionError(reason string) {
+	instrumentationSelectionErrors.WithLabelValues(reason).Inc()
+}
+
+// RecordAutoMonitorWorkload records a workload annotated or restarted by auto-monitor.
+func RecordAutoMonitorWorkload(kind, action string) {
+	autoMonitorWorkloads.WithLabelValues(kind, action).Inc()
+}
+
+// RecordCertificateExpiry records the expiry of the certificate stored in the secret.
+func RecordCertificateExpiry(namespace, secret string, expiry time.Time) {
+	certificateExpiry.WithLabelValues(namespace, secret).Set(float64(expiry.Unix()))
+}
@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package operatormetrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordPodMutation(t *testing.T) {
+	RecordPodMutation("sidecar", time.Millisecond, nil)
+	RecordPodMutation("sidecar", time.Millisecond, errors.New("failed"))
+	RecordPodMutation("sidecar", time.Millisecond, nil)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(podMutations.WithLabelValues("sidecar", OutcomeSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(podMutations.WithLabelValues("sidecar", OutcomeError)))
+	assert.Equal(t, 1, testutil.CollectAndCount(podMutationDuration))
+}
+
+func TestRecordCertificateExpiry(t *testing.T) {
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	RecordCertificateExpiry("default", "cert", expiry)
+	assert.Equal(t, float64(expiry.Unix()), testutil.ToFloat64(certificateExpiry.WithLabelValues("default", "cert")))
+}
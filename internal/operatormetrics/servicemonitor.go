@@ -0,0 +1,136 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package operatormetrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// MetricsServiceName is the name of the Service and the ServiceMonitor exposing the metrics of the operator.
+	MetricsServiceName = "amazon-cloudwatch-agent-operator-metrics"
+	metricsPortName    = "metrics"
+
+	namespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	// defaultNamespace is the namespace config/default deploys the operator to.
+	defaultNamespace = "amazon-cloudwatch"
+)
+
+var (
+	_ manager.LeaderElectionRunnable = (*ServiceMonitor)(nil)
+
+	// defaultPodLabels select the operator pods deployed from config/manager, when the pod of the operator can't be
+	// read.
+	defaultPodLabels = map[string]string{
+		"app.kubernetes.io/name": "amazon-cloudwatch-agent-operator",
+		"control-plane":          "controller-manager",
+	}
+	metricsLabels = map[string]string{
+		"app.kubernetes.io/name":       MetricsServiceName,
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+	}
+)
+
+// ServiceMonitor is a runnable exposing the metrics of the operator to the Prometheus Operator through a Service and
+// a ServiceMonitor.
+type ServiceMonitor struct {
+	client    client.Client
+	reader    client.Reader
+	log       logr.Logger
+	namespace string
+	podName   string
+	port      int32
+}
+
+// NewServiceMonitor creates the runnable for the metrics served on the port. The labels of the operator pod, read
+// with the reader, select the pods behind the Service.
+func NewServiceMonitor(cl client.Client, reader client.Reader, log logr.Logger, port int32) *ServiceMonitor {
+	return &ServiceMonitor{
+		client:    cl,
+		reader:    reader,
+		log:       log,
+		namespace: operatorNamespace(log),
+		podName:   os.Getenv("HOSTNAME"),
+		port:      port,
+	}
+}
+
+// operatorNamespace returns the namespace of the service account of the operator, or the default namespace when the
+// operator runs without one, such as out of the cluster.
+func operatorNamespace(log logr.Logger) string {
+	namespace, err := os.ReadFile(namespaceFile)
+	if err != nil || len(strings.TrimSpace(string(namespace))) == 0 {
+		log.Info("the namespace of the operator can't be read, exposing its metrics in the default namespace", "namespace", defaultNamespace, "error", err)
+		return defaultNamespace
+	}
+	return strings.TrimSpace(string(namespace))
+}
+
+// NeedLeaderElection makes only the leader manage the objects.
+func (s *ServiceMonitor) NeedLeaderElection() bool {
+	return true
+}
+
+// Start creates or updates the Service and the ServiceMonitor. They're left in place once the operator stops, so that
+// the metrics of the next leader are scraped without a gap.
+func (s *ServiceMonitor) Start(ctx context.Context) error {
+	podLabels := s.podLabels(ctx)
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: MetricsServiceName, Namespace: s.namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, s.client, svc, func() error {
+		svc.Labels = metricsLabels
+		svc.Spec.Selector = podLabels
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:       metricsPortName,
+			Port:       s.port,
+			TargetPort: intstr.FromInt32(s.port),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to apply the Service exposing the operator metrics: %w", err)
+	}
+	sm := &monitoringv1.ServiceMonitor{ObjectMeta: metav1.ObjectMeta{Name: MetricsServiceName, Namespace: s.namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, s.client, sm, func() error {
+		sm.Labels = metricsLabels
+		sm.Spec = monitoringv1.ServiceMonitorSpec{
+			Endpoints:         []monitoringv1.Endpoint{{Port: metricsPortName}},
+			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{s.namespace}},
+			Selector:          metav1.LabelSelector{MatchLabels: metricsLabels},
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to apply the ServiceMonitor exposing the operator metrics: %w", err)
+	}
+	s.log.Info("exposing the operator metrics to the Prometheus Operator", "namespace", s.namespace, "servicemonitor", MetricsServiceName)
+	return nil
+}
+
+// podLabels returns the labels of the operator pod, without the label identifying its revision.
+func (s *ServiceMonitor) podLabels(ctx context.Context) map[string]string {
+	pod := &corev1.Pod{}
+	if err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.podName}, pod); err != nil || len(pod.Labels) == 0 {
+		s.log.V(1).Info("the operator pod can't be read, selecting the default labels", "pod", s.podName, "error", err)
+		return defaultPodLabels
+	}
+	labels := map[string]string{}
+	for k, v := range pod.Labels {
+		if k != appsv1.DefaultDeploymentUniqueLabelKey {
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package operatormetrics
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestServiceMonitor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, monitoringv1.AddToScheme(scheme))
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "operator-6d4cf56db6-x2k8p",
+		Namespace: "amazon-cloudwatch",
+		Labels:    map[string]string{"app.kubernetes.io/name": "operator", "pod-template-hash": "6d4cf56db6"},
+	}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	sm := &ServiceMonitor{
+		client:    cl,
+		reader:    cl,
+		log:       logf.Log.WithName("unit-tests"),
+		namespace: "amazon-cloudwatch",
+		podName:   pod.Name,
+		port:      8080,
+	}
+
+	key := client.ObjectKey{Namespace: "amazon-cloudwatch", Name: MetricsServiceName}
+	// the Service left by a previous operator is updated
+	require.NoError(t, cl.Create(context.Background(), &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: metricsPortName, Port: 9090}}},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, sm.Start(ctx))
+
+	svc := &corev1.Service{}
+	require.NoError(t, cl.Get(context.Background(), key, svc))
+	assert.Equal(t, map[string]string{"app.kubernetes.io/name": "operator"}, svc.Spec.Selector)
+	assert.Equal(t, int32(8080), svc.Spec.Ports[0].Port)
+	assert.Equal(t, metricsLabels, svc.Labels)
+	require.NoError(t, cl.Get(context.Background(), key, &monitoringv1.ServiceMonitor{}))
+
+	// the objects are kept once the operator stops
+	cancel()
+	assert.NoError(t, cl.Get(context.Background(), key, svc))
+	assert.NoError(t, cl.Get(context.Background(), key, &monitoringv1.ServiceMonitor{}))
+}
+
+func TestOperatorNamespaceFallback(t *testing.T) {
+	assert.Equal(t, defaultNamespace, operatorNamespace(logf.Log.WithName("unit-tests")))
+}
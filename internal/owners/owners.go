@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package owners resolves the workloads owning a pod, following the owner references of its ReplicaSet or Job, to
+// describe them in the resource attributes injected in the pod.
+package owners
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
+)
+
+const (
+	// cacheSize is the number of ReplicaSets and Jobs whose owner references are kept.
+	cacheSize = 1024
+	// cacheTTL is how long the owner references of a ReplicaSet or Job are kept. They rarely change, but a ReplicaSet
+	// can be adopted by another Deployment.
+	cacheTTL = time.Minute
+	// lookupTimeout bounds the time spent reading the owner of a ReplicaSet or Job, retries included. The resolver is
+	// called from the admission webhooks, which the API server gives up on after 10 seconds by default.
+	lookupTimeout = 2 * time.Second
+)
+
+// Owner is a workload owning a pod, directly or through its ReplicaSet or Job.
+type Owner struct {
+	Name string
+	UID  types.UID
+}
+
+// Owners are the workloads owning a pod, nil when the pod isn't owned by a workload of the kind.
+type Owners struct {
+	ReplicaSet  *Owner
+	Deployment  *Owner
+	StatefulSet *Owner
+	DaemonSet   *Owner
+	Job         *Owner
+	CronJob     *Owner
+}
+
+// Attributes returns the resource attributes naming the owners, along with their UIDs when uid is set.
+func (o Owners) Attributes(uid bool) map[attribute.Key]string {
+	attributes := map[attribute.Key]string{}
+	for _, owner := range []struct {
+		owner   *Owner
+		nameKey attribute.Key
+		uidKey  attribute.Key
+	}{
+		{o.ReplicaSet, semconv.K8SReplicaSetNameKey, semconv.K8SReplicaSetUIDKey},
+		{o.Deployment, semconv.K8SDeploymentNameKey, semconv.K8SDeploymentUIDKey},
+		{o.StatefulSet, semconv.K8SStatefulSetNameKey, semconv.K8SStatefulSetUIDKey},
+		{o.DaemonSet, semconv.K8SDaemonSetNameKey, semconv.K8SDaemonSetUIDKey},
+		{o.Job, semconv.K8SJobNameKey, semconv.K8SJobUIDKey},
+		{o.CronJob, semconv.K8SCronJobNameKey, semconv.K8SCronJobUIDKey},
+	} {
+		if owner.owner == nil {
+			continue
+		}
+		attributes[owner.nameKey] = owner.owner.Name
+		if uid {
+			attributes[owner.uidKey] = string(owner.owner.UID)
+		}
+	}
+	return attributes
+}
+
+// Resolver resolves the owners of pods. The owner references of the ReplicaSets and Jobs it reads are cached, so that
+// the pods of a workload, and the mutators of a same pod, share the lookups instead of each reading the API server.
+type Resolver struct {
+	client client.Reader
+	logger logr.Logger
+	cache  *cache.LRUExpireCache
+}
+
+// NewResolver returns a Resolver reading the ReplicaSets and Jobs with the client, meant to be an uncached reader such
+// as the API reader of the manager.
+func NewResolver(logger logr.Logger, client client.Reader) *Resolver {
+	return &Resolver{
+		client: client,
+		logger: logger,
+		cache:  cache.NewLRUExpireCache(cacheSize),
+	}
+}
+
+// Resolve returns the owners of the object with the owner references in the namespace, following the owner of its
+// ReplicaSet up to the Deployment and the owner of its Job up to the CronJob. An owner which can't be read is logged
+// and ends the chain.
+func (r *Resolver) Resolve(ctx context.Context, namespace string, references []metav1.OwnerReference) Owners {
+	ctx, span := tracing.Tracer().Start(ctx, "Resolve owners")
+	defer span.End()
+
+	owners := Owners{}
+	r.resolve(ctx, namespace, references, &owners)
+	return owners
+}
+
+func (r *Resolver) resolve(ctx context.Context, namespace string, references []metav1.OwnerReference, owners *Owners) {
+	for _, reference := range references {
+		owner := &Owner{Name: reference.Name, UID: reference.UID}
+		switch strings.ToLower(reference.Kind) {
+		case "replicaset":
+			owners.ReplicaSet = owner
+			// parent of ReplicaSet is e.g. Deployment which we are interested to know
+			r.resolve(ctx, namespace, r.ownerReferences(ctx, &appsv1.ReplicaSet{}, namespace, reference.Name), owners)
+		case "deployment":
+			owners.Deployment = owner
+		case "statefulset":
+			owners.StatefulSet = owner
+		case "daemonset":
+			owners.DaemonSet = owner
+		case "job":
+			owners.Job = owner
+			// parent of Job is e.g. CronJob which we are interested to know
+			r.resolve(ctx, namespace, r.ownerReferences(ctx, &batchv1.Job{}, namespace, reference.Name), owners)
+		case "cronjob":
+			owners.CronJob = owner
+		}
+	}
+}
+
+// ownerReferences returns the owner references of the object, from the cache when it was read recently.
+func (r *Resolver) ownerReferences(ctx context.Context, obj client.Object, namespace, name string) []metav1.OwnerReference {
+	key := fmt.Sprintf("%T/%s/%s", obj, namespace, name)
+	if references, ok := r.cache.Get(key); ok {
+		return references.([]metav1.OwnerReference)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	nsn := types.NamespacedName{Namespace: namespace, Name: name}
+	// the backoff sleeps under a second in total, so that a missing owner doesn't hold the admission of the pod
+	backOff := wait.Backoff{Duration: 10 * time.Millisecond, Factor: 2, Jitter: 0.1, Steps: 8, Cap: 500 * time.Millisecond}
+	// use a retry loop to get the owner. A single call to client.get fails occasionally, when the pod is created
+	// before the owner is visible to the reader
+	err := retry.OnError(backOff, apierrors.IsNotFound, func() error {
+		return r.client.Get(ctx, nsn, obj)
+	})
+	if err != nil {
+		r.logger.Error(err, "failed to get the owner of the pod", "owner", nsn.Name, "namespace", nsn.Namespace)
+		return nil
+	}
+	references := obj.GetOwnerReferences()
+	r.cache.Add(key, references, cacheTTL)
+	return references
+}
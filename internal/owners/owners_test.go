@@ -0,0 +1,136 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package owners
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestResolve(t *testing.T) {
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "my-deploy-rs", Namespace: "my-ns",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "my-deploy", UID: "deploy-uid"}},
+	}}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name: "my-cronjob-123", Namespace: "my-ns",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "my-cronjob", UID: "cronjob-uid"}},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(replicaSet, job).Build()
+
+	for _, tt := range []struct {
+		desc       string
+		references []metav1.OwnerReference
+		expected   Owners
+	}{
+		{
+			desc:     "no owner",
+			expected: Owners{},
+		},
+		{
+			desc:       "deployment",
+			references: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "my-deploy-rs", UID: "rs-uid"}},
+			expected: Owners{
+				ReplicaSet: &Owner{Name: "my-deploy-rs", UID: "rs-uid"},
+				Deployment: &Owner{Name: "my-deploy", UID: "deploy-uid"},
+			},
+		},
+		{
+			desc:       "cronjob",
+			references: []metav1.OwnerReference{{Kind: "Job", Name: "my-cronjob-123", UID: "job-uid"}},
+			expected: Owners{
+				Job:     &Owner{Name: "my-cronjob-123", UID: "job-uid"},
+				CronJob: &Owner{Name: "my-cronjob", UID: "cronjob-uid"},
+			},
+		},
+		{
+			desc:       "statefulset",
+			references: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "my-sts", UID: "sts-uid"}},
+			expected:   Owners{StatefulSet: &Owner{Name: "my-sts", UID: "sts-uid"}},
+		},
+		{
+			desc:       "daemonset",
+			references: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "my-ds", UID: "ds-uid"}},
+			expected:   Owners{DaemonSet: &Owner{Name: "my-ds", UID: "ds-uid"}},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			owners := NewResolver(logr.Discard(), c).Resolve(context.Background(), "my-ns", tt.references)
+			assert.Equal(t, tt.expected, owners)
+		})
+	}
+}
+
+func TestResolveCachesOwners(t *testing.T) {
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "my-deploy-rs", Namespace: "my-ns",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "my-deploy", UID: "deploy-uid"}},
+	}}
+	gets := 0
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(replicaSet).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			gets++
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	resolver := NewResolver(logr.Discard(), c)
+	references := []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "my-deploy-rs", UID: "rs-uid"}}
+
+	for i := 0; i < 3; i++ {
+		owners := resolver.Resolve(context.Background(), "my-ns", references)
+		require.NotNil(t, owners.Deployment)
+		assert.Equal(t, "my-deploy", owners.Deployment.Name)
+	}
+	assert.Equal(t, 1, gets)
+}
+
+func TestResolveBoundsTheWaitForAMissingOwner(t *testing.T) {
+	gets := 0
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			gets++
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	references := []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "my-deploy-rs", UID: "rs-uid"}}
+
+	start := time.Now()
+	owners := NewResolver(logr.Discard(), c).Resolve(context.Background(), "my-ns", references)
+	assert.Less(t, time.Since(start), lookupTimeout)
+	// the ReplicaSet is still reported, but the chain ends there
+	assert.Equal(t, Owners{ReplicaSet: &Owner{Name: "my-deploy-rs", UID: "rs-uid"}}, owners)
+	assert.Greater(t, gets, 1)
+}
+
+func TestAttributes(t *testing.T) {
+	owners := Owners{
+		ReplicaSet: &Owner{Name: "my-deploy-rs", UID: "rs-uid"},
+		Deployment: &Owner{Name: "my-deploy", UID: "deploy-uid"},
+	}
+
+	assert.Equal(t, map[attribute.Key]string{
+		semconv.K8SReplicaSetNameKey: "my-deploy-rs",
+		semconv.K8SDeploymentNameKey: "my-deploy",
+	}, owners.Attributes(false))
+	assert.Equal(t, map[attribute.Key]string{
+		semconv.K8SReplicaSetNameKey: "my-deploy-rs",
+		semconv.K8SReplicaSetUIDKey:  "rs-uid",
+		semconv.K8SDeploymentNameKey: "my-deploy",
+		semconv.K8SDeploymentUIDKey:  "deploy-uid",
+	}, owners.Attributes(true))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -32,11 +33,14 @@ func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifest
 		return ctrl.Result{}, err
 	}
 	changed := params.OtelCol.DeepCopy()
-	statusErr := UpdateCollectorStatus(ctx, params.Client, changed)
+	statusErr := UpdateCollectorStatus(ctx, params.Client, changed, params.Rollout)
 	if statusErr != nil {
 		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
 		return ctrl.Result{}, statusErr
 	}
+	for _, condition := range params.Conditions {
+		meta.SetStatusCondition(&changed.Status.Conditions, condition)
+	}
 	statusPatch := client.MergeFrom(&params.OtelCol)
 	if err := params.Client.Status().Patch(ctx, changed, statusPatch); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the AmazonCloudWatchAgent CR: %w", err)
@@ -30,7 +30,7 @@ func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifest
 		return ctrl.Result{}, err
 	}
 	changed := params.DcgmExp.DeepCopy()
-	statusErr := UpdateDcgmExporterStatus(ctx, params.Client, changed)
+	statusErr := UpdateDcgmExporterStatus(ctx, params.Client, changed, params.NodeDiscovery)
 	if statusErr != nil {
 		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
 		return ctrl.Result{}, statusErr
@@ -6,15 +6,30 @@ package neuronmonitor
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/nodediscovery"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
-func UpdateNeuronMonitorStatus(ctx context.Context, cli client.Client, changed *v1alpha1.NeuronMonitor) error {
+func UpdateNeuronMonitorStatus(ctx context.Context, cli client.Client, changed *v1alpha1.NeuronMonitor, discovery *nodediscovery.Result) error {
 	if changed.Status.Version == "" {
 		changed.Status.Version = version.NeuronMonitor()
 	}
+
+	if discovery == nil {
+		changed.Status.NodeDiscovery = nil
+		return nil
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(changed), ds); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	changed.Status.NodeDiscovery = &v1alpha1.NodeDiscoveryStatus{
+		EligibleNodes:  discovery.EligibleNodes,
+		ScheduledNodes: ds.Status.CurrentNumberScheduled,
+	}
 	return nil
 }
@@ -0,0 +1,104 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	attributeKind      = attribute.Key("k8s.object.kind")
+	attributeNamespace = attribute.Key("k8s.object.namespace")
+	attributeName      = attribute.Key("k8s.object.name")
+)
+
+var _ client.Client = (*tracedClient)(nil)
+
+// tracedClient wraps the calls of a client in spans.
+type tracedClient struct {
+	client.Client
+}
+
+// NewClient returns a client tracing the calls of the given client.
+func NewClient(c client.Client) client.Client {
+	return &tracedClient{Client: c}
+}
+
+func (c *tracedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, span := c.start(ctx, "Get", obj, key.Namespace, key.Name)
+	err := c.Client.Get(ctx, key, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	ctx, span := c.start(ctx, "List", list, listOpts.Namespace, "")
+	err := c.Client.List(ctx, list, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := c.start(ctx, "Create", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Create(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := c.start(ctx, "Update", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Update(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := c.start(ctx, "Patch", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := c.start(ctx, "Delete", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Delete(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+func (c *tracedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, span := c.start(ctx, "DeleteAllOf", obj, obj.GetNamespace(), "")
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	End(span, err)
+	return err
+}
+
+// start starts a client span named after the verb and the kind of the object, e.g. "Get Deployment".
+func (c *tracedClient) start(ctx context.Context, verb string, obj runtime.Object, namespace, name string) (context.Context, trace.Span) {
+	kind := c.kind(obj)
+	attrs := []attribute.KeyValue{attributeKind.String(kind)}
+	if namespace != "" {
+		attrs = append(attrs, attributeNamespace.String(namespace))
+	}
+	if name != "" {
+		attrs = append(attrs, attributeName.String(name))
+	}
+	return Tracer().Start(ctx, verb+" "+kind, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func (c *tracedClient) kind(obj runtime.Object) string {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil || gvk.Kind == "" {
+		return "Unknown"
+	}
+	return strings.TrimSuffix(gvk.Kind, "List")
+}
@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const attributeController = attribute.Key("controller")
+
+// tracedReconciler wraps each reconciliation in a span.
+type tracedReconciler struct {
+	name       string
+	reconciler reconcile.Reconciler
+}
+
+// NewReconciler returns a reconciler tracing the reconciliations of the named controller. The client calls made with
+// the context of the reconciliation are its child spans.
+func NewReconciler(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracedReconciler{name: name, reconciler: r}
+}
+
+func (t *tracedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Tracer().Start(ctx, "Reconcile "+t.name, trace.WithAttributes(
+		attributeController.String(t.name),
+		attributeNamespace.String(req.Namespace),
+		attributeName.String(req.Name),
+	))
+	result, err := t.reconciler.Reconcile(ctx, req)
+	End(span, err)
+	return result, err
+}
@@ -0,0 +1,104 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing instruments the reconciliations, the admission requests and the Kubernetes client calls of the
+// operator with OpenTelemetry traces, exported over OTLP.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ServiceName is the service name of the spans of the operator.
+	ServiceName = "amazon-cloudwatch-agent-operator"
+
+	instrumentationName = "github.com/aws/amazon-cloudwatch-agent-operator"
+)
+
+// Options configure the export of the traces.
+type Options struct {
+	// Endpoint is the host:port or the URL of the OTLP gRPC receiver, as in OTEL_EXPORTER_OTLP_TRACES_ENDPOINT. A URL
+	// with the http scheme disables the TLS of the connection. The traces aren't exported when it is empty.
+	Endpoint string
+	// Insecure disables the TLS of the connection to the endpoint.
+	Insecure bool
+	// SamplingRatio is the fraction of the root spans sampled.
+	SamplingRatio float64
+	// ServiceVersion is the version of the operator.
+	ServiceVersion string
+}
+
+// Setup registers the global tracer provider exporting the spans to the endpoint. The returned func flushes and
+// stops the export. Nothing is registered when the endpoint is empty, so that the spans are no-ops.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	endpoint, err := endpointOption(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exporterOpts := []otlptracegrpc.Option{endpoint}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(ServiceName), semconv.ServiceVersion(opts.ServiceVersion)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// endpointOption returns the exporter option of the endpoint, a URL when it has a scheme and host:port otherwise.
+func endpointOption(endpoint string) (otlptracegrpc.Option, error) {
+	if !strings.Contains(endpoint, "://") {
+		return otlptracegrpc.WithEndpoint(endpoint), nil
+	}
+	// the exporter silently falls back to its default endpoint when the URL is invalid
+	if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP traces endpoint %q", endpoint)
+	}
+	return otlptracegrpc.WithEndpointURL(endpoint), nil
+}
+
+// Tracer returns the tracer of the operator, backed by the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End records the error on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,105 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestEndpointOption(t *testing.T) {
+	for _, endpoint := range []string{"cloudwatch-agent.amazon-cloudwatch:4317", "http://cloudwatch-agent.amazon-cloudwatch:4317", "https://collector.example.com"} {
+		_, err := endpointOption(endpoint)
+		assert.NoError(t, err, endpoint)
+	}
+	for _, endpoint := range []string{"http://", "http://[::1"} {
+		_, err := endpointOption(endpoint)
+		assert.Error(t, err, endpoint)
+	}
+}
+
+func TestSetupWithEndpointURL(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	shutdown, err := Setup(context.Background(), Options{Endpoint: "http://localhost:4317", SamplingRatio: 1})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestClientSpans(t *testing.T) {
+	recorder := setupRecorder(t)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+	c := NewClient(fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(cm).Build())
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}))
+	require.NoError(t, c.List(context.Background(), &corev1.PodList{}, client.InNamespace("default")))
+	err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "missing"}, &corev1.Secret{})
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "Get ConfigMap", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("k8s.object.name", "config"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("k8s.object.namespace", "default"))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "List Pod", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("k8s.object.namespace", "default"))
+
+	assert.Equal(t, "Get Secret", spans[2].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestReconcilerSpans(t *testing.T) {
+	recorder := setupRecorder(t)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+	c := NewClient(fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(cm).Build())
+
+	r := NewReconciler("test", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		if err := c.Get(ctx, req.NamespacedName, &corev1.ConfigMap{}); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, errors.New("failed")
+	}))
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cm)})
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	get, rec := spans[0], spans[1]
+	assert.Equal(t, "Reconcile test", rec.Name())
+	assert.Equal(t, codes.Error, rec.Status().Code)
+	assert.Contains(t, rec.Attributes(), attribute.String("controller", "test"))
+	assert.Equal(t, rec.SpanContext().SpanID(), get.Parent().SpanID(), "the client call is a child of the reconciliation")
+}
@@ -7,15 +7,23 @@ package podmutation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"path"
+	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
 )
 
 // +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod.kb.io,sideEffects=none,admissionReviewVersions=v1
@@ -23,6 +31,7 @@ import (
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations,verbs=get;list;watch
 // +kubebuilder:rbac:groups="apps",resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch
 
 var _ WebhookHandler = (*podMutationWebhook)(nil)
 
@@ -57,6 +66,21 @@ func NewWebhookHandler(cfg config.Config, logger logr.Logger, decoder admission.
 }
 
 func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, span := tracing.Tracer().Start(ctx, "Admit Pod", trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("k8s.pod.name", req.Name),
+		attribute.String("admission.operation", string(req.Operation)),
+	))
+	res := p.handle(ctx, req)
+	var err error
+	if res.Result != nil && res.Result.Code >= http.StatusBadRequest {
+		err = errors.New(res.Result.Message)
+	}
+	tracing.End(span, err)
+	return res
+}
+
+func (p *podMutationWebhook) handle(ctx context.Context, req admission.Request) admission.Response {
 	pod := corev1.Pod{}
 	err := p.decoder.Decode(req, &pod)
 	if err != nil {
@@ -78,7 +102,12 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 	}
 
 	for _, m := range p.podMutators {
-		pod, err = m.Mutate(ctx, ns, pod)
+		name := mutatorName(m)
+		mutateCtx, span := tracing.Tracer().Start(ctx, "Mutate "+name)
+		start := time.Now()
+		pod, err = m.Mutate(mutateCtx, ns, pod)
+		operatormetrics.RecordPodMutation(name, time.Since(start), err)
+		tracing.End(span, err)
 		if err != nil {
 			res := admission.Errored(http.StatusInternalServerError, err)
 			res.Allowed = true
@@ -94,3 +123,12 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 	}
 	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
 }
+
+// mutatorName names the mutator after its package, like sidecar or instrumentation.
+func mutatorName(m PodMutator) string {
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath())
+}
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,22 +19,34 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/spf13/pflag"
 	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	k8sapiflag "k8s.io/component-base/cli/flag"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	otelv1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	otelv1alpha2 "github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha2"
 	"github.com/aws/amazon-cloudwatch-agent-operator/controllers"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/autodetect"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	kedav1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/internal/keda/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/namespacemutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
@@ -68,6 +82,7 @@ type tlsConfig struct {
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(otelv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(otelv1alpha2.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
 	utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -159,6 +174,11 @@ func main() {
 		dcgmExporterImage            string
 		neuronMonitorImage           string
 		targetAllocatorImage         string
+		manageTACertificates         bool
+		restartStaleSidecars         bool
+		otlpTracesEndpoint           string
+		otlpTracesInsecure           bool
+		tracesSamplingRatio          float64
 	)
 
 	pflag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
@@ -175,6 +195,11 @@ func main() {
 	stringFlagOrEnv(&dcgmExporterImage, "dcgm-exporter-image", "RELATED_IMAGE_DCGM_EXPORTER", fmt.Sprintf("%s:%s", dcgmExporterImageRepository, v.DcgmExporter), "The default DCGM Exporter image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&neuronMonitorImage, "neuron-monitor-image", "RELATED_IMAGE_NEURON_MONITOR", fmt.Sprintf("%s:%s", neuronMonitorImageRepository, v.NeuronMonitor), "The default Neuron monitor image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&targetAllocatorImage, "target-allocator-image", "RELATED_IMAGE_TARGET_ALLOCATOR", fmt.Sprintf("%s:%s", targetAllocatorImageRepository, v.TargetAllocator), "The default AmazonCloudWatchAgent target allocator image. This image is used when no image is specified in the CustomResource.")
+	pflag.BoolVar(&manageTACertificates, "manage-target-allocator-certificates", false, "Provision and rotate the TLS certificates of the target allocator and the collectors, instead of relying on cert-manager or Helm to create their secrets.")
+	pflag.BoolVar(&restartStaleSidecars, "restart-stale-sidecars", false, "Restart the Deployments, StatefulSets and DaemonSets whose pods run a sidecar injected from an AmazonCloudWatchAgent which was deleted or changed since, so that they are recreated without it or with the updated one.")
+	stringFlagOrEnv(&otlpTracesEndpoint, "otlp-traces-endpoint", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "", "The host:port or the URL of the OTLP gRPC endpoint receiving the traces of the operator, e.g. the agent managed by the operator at http://cloudwatch-agent.amazon-cloudwatch:4317. Tracing is disabled when empty.")
+	pflag.BoolVar(&otlpTracesInsecure, "otlp-traces-insecure", false, "Export the traces of the operator without TLS.")
+	pflag.Float64Var(&tracesSamplingRatio, "traces-sampling-ratio", 1, "The fraction of the reconciliations and admission requests traced.")
 	pflag.Parse()
 
 	// set instrumentation cpu and memory limits in environment variables to be used for default instrumentation; default values received from https://github.com/open-telemetry/opentelemetry-operator/blob/main/apis/v1alpha1/instrumentation_webhook.go
@@ -221,6 +246,34 @@ func main() {
 		"go-os", runtime.GOOS,
 	)
 
+	restConfig := ctrl.GetConfigOrDie()
+	dcl, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create the discovery client")
+		os.Exit(1)
+	}
+	gatewayAPIAvailable, err := autodetect.GatewayAPIAvailable(dcl)
+	if err != nil {
+		setupLog.Error(err, "unable to detect the Gateway API")
+		os.Exit(1)
+	}
+	if gatewayAPIAvailable {
+		utilruntime.Must(gatewayv1.Install(scheme))
+	}
+	kedaAvailable, err := autodetect.KEDAAvailable(dcl)
+	if err != nil {
+		setupLog.Error(err, "unable to detect KEDA")
+		os.Exit(1)
+	}
+	if kedaAvailable {
+		utilruntime.Must(kedav1alpha1.AddToScheme(scheme))
+	}
+	nativeSidecarSupported, err := autodetect.NativeSidecarSupported(dcl)
+	if err != nil {
+		setupLog.Error(err, "unable to detect the support of native sidecars")
+		os.Exit(1)
+	}
+
 	cfg := config.New(
 		config.WithLogger(ctrl.Log.WithName("config")),
 		config.WithVersion(v),
@@ -232,6 +285,11 @@ func main() {
 		config.WithDcgmExporterImage(dcgmExporterImage),
 		config.WithNeuronMonitorImage(neuronMonitorImage),
 		config.WithTargetAllocatorImage(targetAllocatorImage),
+		config.WithGatewayAPIAvailable(gatewayAPIAvailable),
+		config.WithKEDAAvailable(kedaAvailable),
+		config.WithNativeSidecarSupported(nativeSidecarSupported),
+		config.WithManageTargetAllocatorCertificates(manageTACertificates),
+		config.WithRestartStaleSidecars(restartStaleSidecars),
 	)
 
 	watchNamespace, found := os.LookupEnv("WATCH_NAMESPACE")
@@ -252,6 +310,11 @@ func main() {
 		}
 	}
 
+	injectedPods, err := labels.NewRequirement(sidecar.InjectedLabel, selection.Exists, nil)
+	if err != nil {
+		setupLog.Error(err, "unable to select the pods with an injected sidecar")
+		os.Exit(1)
+	}
 	mgrOptions := ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -265,10 +328,17 @@ func main() {
 		}),
 		Cache: cache.Options{
 			DefaultNamespaces: namespaces,
+			ByObject: map[client.Object]cache.ByObject{
+				// only the Secrets managed by the operator are cached, the ones referenced by the instances are read
+				// on demand
+				&corev1.Secret{}: {Label: labels.SelectorFromSet(labels.Set{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"})},
+				// only the pods with an injected sidecar are cached, the pods of the collectors are read on demand
+				&corev1.Pod{}: {Label: labels.NewSelector().Add(*injectedPods)},
+			},
 		},
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
+	mgr, err := ctrl.NewManager(restConfig, mgrOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -276,19 +346,33 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Endpoint:       otlpTracesEndpoint,
+		Insecure:       otlpTracesInsecure,
+		SamplingRatio:  tracesSamplingRatio,
+		ServiceVersion: v.Operator,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	// the client calls of the reconcilers and the webhooks are traced
+	tracedClient := tracing.NewClient(mgr.GetClient())
+
 	if err = controllers.NewReconciler(controllers.Params{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("AmazonCloudWatchAgent"),
-		Scheme:   mgr.GetScheme(),
-		Config:   cfg,
-		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"), //nolint:staticcheck // TODO: migrate to events.EventRecorder
+		Client:    tracedClient,
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("AmazonCloudWatchAgent"),
+		Scheme:    mgr.GetScheme(),
+		Config:    cfg,
+		Recorder:  mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"), //nolint:staticcheck // TODO: migrate to events.EventRecorder
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AmazonCloudWatchAgent")
 		os.Exit(1)
 	}
 
 	if err = controllers.NewDcgmExporterReconciler(controllers.Params{
-		Client:   mgr.GetClient(),
+		Client:   tracedClient,
 		Log:      ctrl.Log.WithName("controllers").WithName("DcgmExporter"),
 		Scheme:   mgr.GetScheme(),
 		Config:   cfg,
@@ -299,7 +383,7 @@ func main() {
 	}
 
 	if err = controllers.NewNeuronMonitorReconciler(controllers.Params{
-		Client:   mgr.GetClient(),
+		Client:   tracedClient,
 		Log:      ctrl.Log.WithName("controllers").WithName("NeuronMonitor"),
 		Scheme:   mgr.GetScheme(),
 		Config:   cfg,
@@ -309,9 +393,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = controllers.NewSidecarReconciler(controllers.Params{
+		Client:    tracedClient,
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("Sidecar"),
+		Scheme:    mgr.GetScheme(),
+		Config:    cfg,
+		Recorder:  mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"), //nolint:staticcheck // TODO: migrate to events.EventRecorder
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Sidecar")
+		os.Exit(1)
+	}
+
 	decoder := admission.NewDecoder(mgr.GetScheme())
 
-	instrumentationAnnotator := auto.CreateInstrumentationAnnotator(autoMonitorConfigStr, autoAnnotationConfigStr, ctx, mgr.GetClient(), mgr.GetAPIReader(), setupLog)
+	instrumentationAnnotator := auto.CreateInstrumentationAnnotator(autoMonitorConfigStr, autoAnnotationConfigStr, ctx, tracedClient, mgr.GetAPIReader(), setupLog)
 
 	if instrumentationAnnotator != nil {
 		mgr.GetWebhookServer().Register("/mutate-v1-workload", &webhook.Admission{
@@ -335,6 +431,8 @@ func main() {
 	}
 
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		// v1alpha1 is the hub of the conversion of v1alpha2, the webhooks of its kinds register the conversion
+		// webhook at /convert
 		if err = otelv1alpha1.SetupCollectorWebhook(mgr, cfg); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AmazonCloudWatchAgent")
 			os.Exit(1)
@@ -343,16 +441,26 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Instrumentation")
 			os.Exit(1)
 		}
+		// the mutators share the owners read for the pods of a same workload. They're read from the API server, so
+		// that the webhook doesn't start informers on every ReplicaSet and Job of the cluster
+		ownerResolver := owners.NewResolver(ctrl.Log.WithName("owners"), mgr.GetAPIReader())
+		recorder := mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator") //nolint:staticcheck // TODO: migrate to events.EventRecorder
 		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
-			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(),
+			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, tracedClient,
 				[]podmutation.PodMutator{
-					sidecar.NewMutator(logger, cfg, mgr.GetClient()),
-					instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")), //nolint:staticcheck // TODO: migrate to events.EventRecorder
+					sidecar.NewMutator(logger, cfg, tracedClient, ownerResolver, recorder),
+					instrumentation.NewMutator(logger, tracedClient, recorder, ownerResolver),
 				}),
 		})
 	} else {
 		ctrl.Log.Info("Webhooks are disabled, operator is running an unsupported mode", "ENABLE_WEBHOOKS", "false")
 	}
+	if featuregate.PrometheusOperatorIsAvailable.IsEnabled() {
+		if err = addOperatorServiceMonitor(mgr, metricsAddr); err != nil {
+			setupLog.Error(err, "unable to expose the operator metrics to the Prometheus Operator")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -369,6 +477,28 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "failed to flush the traces")
+	}
+}
+
+// addOperatorServiceMonitor adds the runnable creating the ServiceMonitor of the metrics served at metricsAddr, unless
+// the metrics are disabled.
+func addOperatorServiceMonitor(mgr ctrl.Manager, metricsAddr string) error {
+	if metricsAddr == "0" {
+		return nil
+	}
+	_, rawPort, err := net.SplitHostPort(metricsAddr)
+	if err != nil {
+		return fmt.Errorf("invalid metrics address %q: %w", metricsAddr, err)
+	}
+	port, err := strconv.ParseInt(rawPort, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid metrics port %q: %w", rawPort, err)
+	}
+	return mgr.Add(operatormetrics.NewServiceMonitor(mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("operator-metrics"), int32(port)))
 }
 
 func waitForWebhookServerStart(ctx context.Context, checker healthz.Checker, callback func(context.Context)) {
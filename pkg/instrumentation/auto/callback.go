@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
 )
 
@@ -95,6 +96,34 @@ func patchFunc(m InstrumentationAnnotator, ctx context.Context, callback objectC
 	}
 }
 
+// recordWorkloadFunc returns a func recording the workloads acted on by the previous callbacks of the chain. Only the
+// workloads with mutated annotations are recorded as annotated.
+func recordWorkloadFunc(action string) objectCallbackFunc {
+	return func(obj client.Object, previousResult any) (any, bool) {
+		mutatedAnnotations, ok := previousResult.(map[string]string)
+		if action == operatormetrics.ActionRestarted || (ok && len(mutatedAnnotations) > 0) {
+			operatormetrics.RecordAutoMonitorWorkload(workloadKind(obj), action)
+		}
+		return previousResult, true
+	}
+}
+
+// workloadKind returns the kind of the workload, used as a metric label.
+func workloadKind(obj client.Object) string {
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		return "Deployment"
+	case *appsv1.DaemonSet:
+		return "DaemonSet"
+	case *appsv1.StatefulSet:
+		return "StatefulSet"
+	case *corev1.Namespace:
+		return "Namespace"
+	default:
+		return fmt.Sprintf("%T", obj)
+	}
+}
+
 func restartNamespaceFunc(m InstrumentationAnnotator, ctx context.Context, shouldRestartNamespace bool) objectCallbackFunc {
 	return func(obj client.Object, previousResult any) (any, bool) {
 		if !shouldRestartNamespace {
@@ -160,7 +189,7 @@ func shouldRestartResource(namespaceMutatedAnnotations map[string]string, obj me
 
 // RestartNamespace sets the restartedAtAnnotation for each of the namespace's supported resources and patches them.
 func RestartNamespace(m InstrumentationAnnotator, ctx context.Context, namespace *corev1.Namespace, mutatedAnnotations map[string]string) {
-	callbackFunc := patchFunc(m, ctx, setRestartAnnotation)
+	callbackFunc := chainCallbacks(patchFunc(m, ctx, setRestartAnnotation), recordWorkloadFunc(operatormetrics.ActionRestarted))
 	rangeObjectList(m, ctx, &appsv1.DeploymentList{}, client.InNamespace(namespace.Name), chainCallbacks(shouldRestartFunc(m, mutatedAnnotations), callbackFunc))
 	rangeObjectList(m, ctx, &appsv1.DaemonSetList{}, client.InNamespace(namespace.Name), chainCallbacks(shouldRestartFunc(m, mutatedAnnotations), callbackFunc))
 	rangeObjectList(m, ctx, &appsv1.StatefulSetList{}, client.InNamespace(namespace.Name), chainCallbacks(shouldRestartFunc(m, mutatedAnnotations), callbackFunc))
@@ -169,7 +198,7 @@ func RestartNamespace(m InstrumentationAnnotator, ctx context.Context, namespace
 // MutateAndPatchWorkloads runs the mutators for all workloads and patches them with the updated injection annotations
 func MutateAndPatchWorkloads(m InstrumentationAnnotator, ctx context.Context) {
 	f := getMutateObjectFunc(m)
-	callbackFunc := patchFunc(m, ctx, f)
+	callbackFunc := chainCallbacks(patchFunc(m, ctx, f), recordWorkloadFunc(operatormetrics.ActionAnnotated))
 	rangeObjectList(m, ctx, &appsv1.DeploymentList{}, &client.ListOptions{}, callbackFunc)
 	rangeObjectList(m, ctx, &appsv1.DaemonSetList{}, &client.ListOptions{}, callbackFunc)
 	rangeObjectList(m, ctx, &appsv1.StatefulSetList{}, &client.ListOptions{}, callbackFunc)
@@ -178,7 +207,7 @@ func MutateAndPatchWorkloads(m InstrumentationAnnotator, ctx context.Context) {
 // MutateAndPatchNamespaces runs the mutators for all namespaces.
 // If restartNamespace is true, RestartNamespace will be called for each affected namespace.
 func MutateAndPatchNamespaces(m InstrumentationAnnotator, ctx context.Context, restartNamespace bool) {
-	rangeObjectList(m, ctx, &corev1.NamespaceList{}, &client.ListOptions{}, chainCallbacks(patchFunc(m, ctx, getMutateObjectFunc(m)), restartNamespaceFunc(m, ctx, restartNamespace)))
+	rangeObjectList(m, ctx, &corev1.NamespaceList{}, &client.ListOptions{}, chainCallbacks(patchFunc(m, ctx, getMutateObjectFunc(m)), recordWorkloadFunc(operatormetrics.ActionAnnotated), restartNamespaceFunc(m, ctx, restartNamespace)))
 }
 
 func getMutateObjectFunc(m InstrumentationAnnotator) objectCallbackFunc {
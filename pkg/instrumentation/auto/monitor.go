@@ -22,6 +22,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
 )
 
@@ -321,6 +322,8 @@ func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Ser
 		deployment, err := m.k8sInterface.AppsV1().Deployments(resource.GetNamespace()).Patch(m.ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
 		if err != nil {
 			m.logger.Error(err, "failed to update deployment", "deployment", resource.Name)
+		} else {
+			operatormetrics.RecordAutoMonitorWorkload(workloadKind(&resource), operatormetrics.ActionAnnotated)
 		}
 		m.logger.V(1).Info("Updated deployment", "deployment", deployment)
 	}
@@ -337,6 +340,8 @@ func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Ser
 		_, err = m.k8sInterface.AppsV1().StatefulSets(resource.GetNamespace()).Patch(m.ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
 		if err != nil {
 			m.logger.Error(err, "failed to update statefulset", "statefulset", resource.Name)
+		} else {
+			operatormetrics.RecordAutoMonitorWorkload(workloadKind(&resource), operatormetrics.ActionAnnotated)
 		}
 	}
 	for _, resource := range m.listServiceDaemonSets(oldService, service) {
@@ -351,6 +356,8 @@ func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Ser
 		_, err = m.k8sInterface.AppsV1().DaemonSets(resource.GetNamespace()).Patch(m.ctx, resource.Name, types.JSONPatchType, data, metav1.PatchOptions{})
 		if err != nil {
 			m.logger.Error(err, "failed to update daemonset", "daemonset", resource.Name)
+		} else {
+			operatormetrics.RecordAutoMonitorWorkload(workloadKind(&resource), operatormetrics.ActionAnnotated)
 		}
 	}
 }
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/tracing"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
@@ -173,6 +174,9 @@ func isApplicationSignalsExplicitlyDisabled(envs []corev1.EnvVar) bool {
 // resolveEnvFrom fetches ConfigMap data referenced by envFrom and returns as EnvVar slice
 // Uses caches to avoid redundant API calls when multiple containers reference the same ConfigMap
 func resolveEnvFrom(ctx context.Context, k8sClient client.Client, envFromSources []corev1.EnvFromSource, namespace string, logger logr.Logger, configMapCache map[string]*corev1.ConfigMap) []corev1.EnvVar {
+	ctx, span := tracing.Tracer().Start(ctx, "Resolve envFrom")
+	defer span.End()
+
 	var resolvedEnvs []corev1.EnvVar
 
 	for _, envFromSource := range envFromSources {
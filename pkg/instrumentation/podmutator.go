@@ -11,12 +11,17 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/configsource"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/jmx"
@@ -192,13 +197,14 @@ func (langInsts *languageInstrumentations) setInstrumentationLanguageContainers(
 
 var _ podmutation.PodMutator = (*instPodMutator)(nil)
 
-func NewMutator(logger logr.Logger, client client.Client, recorder record.EventRecorder) *instPodMutator {
+func NewMutator(logger logr.Logger, client client.Client, recorder record.EventRecorder, ownerResolver *owners.Resolver) *instPodMutator {
 	return &instPodMutator{
 		Logger: logger,
 		Client: client,
 		sdkInjector: &sdkInjector{
 			logger: logger,
 			client: client,
+			owners: ownerResolver,
 		},
 		Recorder: recorder,
 	}
@@ -210,6 +216,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	// We check if Pod is already instrumented.
 	if isAutoInstrumentationInjected(pod) {
 		logger.Info("Skipping pod instrumentation - already instrumented")
+		operatormetrics.RecordInjectionSkipped(languageAll, skipReasonAlreadyInstrumented)
 		return pod, nil
 	}
 
@@ -230,6 +237,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Java auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Java auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(string(TypeJava), skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNodeJS); err != nil {
@@ -242,6 +250,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for NodeJS auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for NodeJS auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(string(TypeNodeJS), skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPython); err != nil {
@@ -254,6 +263,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Python auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Python auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(string(TypePython), skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectDotNet); err != nil {
@@ -267,6 +277,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for .NET auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for .NET auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(string(TypeDotNet), skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectGo); err != nil {
@@ -279,6 +290,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(err, "support for Go auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Go auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(string(TypeGo), skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectApacheHttpd); err != nil {
@@ -291,6 +303,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Apache HTTPD auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Apache HTTPD auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(languageApacheHttpd, skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNginx); err != nil {
@@ -303,6 +316,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	} else {
 		logger.Error(nil, "support for Nginx auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Nginx auto instrumentation is not enabled")
+		operatormetrics.RecordInjectionSkipped(languageNginx, skipReasonLanguageDisabled)
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectSdk); err != nil {
@@ -337,6 +351,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		ok, msg := insts.areContainerNamesConfiguredForMultipleInstrumentations()
 		if !ok {
 			logger.V(1).Error(msg, "skipping instrumentation injection")
+			operatormetrics.RecordInjectionSkipped(languageAll, skipReasonInvalidContainerNames)
 			return pod, nil
 		}
 	} else {
@@ -348,6 +363,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 			insts.setInstrumentationLanguageContainers(generalContainerNames)
 		} else {
 			logger.V(1).Error(fmt.Errorf("multiple injection annotations present"), "skipping instrumentation injection")
+			operatormetrics.RecordInjectionSkipped(languageAll, skipReasonMultipleAnnotations)
 			return pod, nil
 		}
 
@@ -380,7 +396,11 @@ func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns cor
 	}
 
 	if strings.EqualFold(instValue, "true") {
-		return pm.selectInstrumentationInstanceFromNamespace(ctx, ns, additionalEnvs, isWindowsPod(pod))
+		otelInst, err := pm.selectInstrumentationInstanceFromNamespace(ctx, ns, additionalEnvs, isWindowsPod(pod))
+		if err != nil {
+			operatormetrics.RecordInstrumentationSelectionError(selectionErrorReason(err))
+		}
+		return otelInst, err
 	}
 
 	var instNamespacedName types.NamespacedName
@@ -393,12 +413,25 @@ func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns cor
 	otelInst := &v1alpha1.Instrumentation{}
 	err := pm.Client.Get(ctx, instNamespacedName, otelInst)
 	if err != nil {
+		operatormetrics.RecordInstrumentationSelectionError(selectionErrorReason(err))
 		return nil, err
 	}
 
 	return otelInst, nil
 }
 
+// selectionErrorReason returns the reason an Instrumentation couldn't be selected, used as a metric label.
+func selectionErrorReason(err error) string {
+	switch {
+	case errors.Is(err, errMultipleInstancesPossible):
+		return "multiple_instances"
+	case apierrors.IsNotFound(err):
+		return "not_found"
+	default:
+		return "error"
+	}
+}
+
 func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context.Context, ns corev1.Namespace, additionalEnvs map[Type]map[string]string, isWindowsPod bool) (*v1alpha1.Instrumentation, error) {
 	var otelInsts v1alpha1.InstrumentationList
 	if err := pm.Client.List(ctx, &otelInsts, client.InNamespace(ns.Name)); err != nil {
@@ -425,10 +458,14 @@ func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context
 func GetAmazonCloudWatchAgentResource(ctx context.Context, c client.Client, name string) v1alpha1.AmazonCloudWatchAgent {
 	cr := &v1alpha1.AmazonCloudWatchAgent{}
 
-	_ = c.Get(ctx, client.ObjectKey{
+	err := c.Get(ctx, client.ObjectKey{
 		Namespace: amazonCloudWatchNamespace,
 		Name:      name,
 	}, cr)
+	if err == nil {
+		// the generated configuration Secret uses the default entries
+		_ = configsource.ResolveGenerated(ctx, c, config.New(), cr)
+	}
 
 	return *cr
 }
@@ -8,23 +8,18 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"time"
 	"unsafe"
 
 	"github.com/go-logr/logr"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/operatormetrics"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
@@ -41,11 +36,28 @@ var vendorCollectorImageMatcher = []string{
 	"dot-collector",
 }
 
+// The languages without a Type and the reasons the injections are skipped, used as metric labels.
+const (
+	languageAll         = "all"
+	languageApacheHttpd = "apache-httpd"
+	languageNginx       = "nginx"
+	languageSdk         = "sdk"
+
+	skipReasonAlreadyInstrumented   = "already_instrumented"
+	skipReasonCollectorPresent      = "collector_present"
+	skipReasonLanguageDisabled      = "language_disabled"
+	skipReasonInvalidContainerNames = "invalid_container_names"
+	skipReasonMultipleAnnotations   = "multiple_annotations"
+	skipReasonInjectionError        = "injection_error"
+	skipReasonMissingTargetExe      = "missing_target_executable"
+)
+
 // inject a new sidecar container to the given pod, based on the given AmazonCloudWatchAgent.
 
 type sdkInjector struct {
 	client client.Client
 	logger logr.Logger
+	owners *owners.Resolver
 }
 
 func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations, ns corev1.Namespace, pod corev1.Pod) corev1.Pod {
@@ -58,6 +70,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	// as a sidecar is not a officially supported configuration pattern within the operator.
 	if otcContainerExistsIn(pod) {
 		i.logger.V(3).Info("An otel collector container already exists, skipping injection")
+		operatormetrics.RecordInjectionSkipped(languageAll, skipReasonCollectorPresent)
 		return pod
 	}
 
@@ -96,7 +109,9 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectJavaagent(otelinst.Spec.Java, pod, index, envs)
 			if err != nil {
 				i.logger.Info("Skipping javaagent injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				operatormetrics.RecordInjectionSkipped(string(TypeJava), skipReasonInjectionError)
 			} else {
+				operatormetrics.RecordInjection(string(TypeJava))
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				//disable setting security context in init container due to issue with runAsNonRoot conflict
@@ -123,7 +138,9 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index, envs)
 			if err != nil {
 				i.logger.Info("Skipping NodeJS SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				operatormetrics.RecordInjectionSkipped(string(TypeNodeJS), skipReasonInjectionError)
 			} else {
+				operatormetrics.RecordInjection(string(TypeNodeJS))
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, nodejsInitContainerName)
@@ -148,7 +165,9 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectPythonSDK(otelinst.Spec.Python, pod, index, envs)
 			if err != nil {
 				i.logger.Info("Skipping Python SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				operatormetrics.RecordInjectionSkipped(string(TypePython), skipReasonInjectionError)
 			} else {
+				operatormetrics.RecordInjection(string(TypePython))
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, pythonInitContainerName)
@@ -173,7 +192,9 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectDotNetSDK(otelinst.Spec.DotNet, pod, index, insts.DotNet.AdditionalAnnotations[annotationDotNetRuntime], envs)
 			if err != nil {
 				i.logger.Info("Skipping DotNet SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				operatormetrics.RecordInjectionSkipped(string(TypeDotNet), skipReasonInjectionError)
 			} else {
+				operatormetrics.RecordInjection(string(TypeDotNet))
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, dotnetInitContainerName)
@@ -193,6 +214,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		pod, err = injectGoSDK(otelinst.Spec.Go, pod)
 		if err != nil {
 			i.logger.Info("Skipping Go SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+			operatormetrics.RecordInjectionSkipped(string(TypeGo), skipReasonInjectionError)
 		} else {
 			// Common env vars and config need to be applied to the agent contain.
 			pod = i.injectCommonEnvVar(otelinst, pod, len(pod.Spec.Containers)-1)
@@ -202,7 +224,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			idx := getIndexOfEnv(pod.Spec.Containers[len(pod.Spec.Containers)-1].Env, envOtelTargetExe)
 			if idx == -1 {
 				i.logger.Info("Skipping Go SDK injection", "reason", "OTEL_GO_AUTO_TARGET_EXE not set", "container", pod.Spec.Containers[index].Name)
+				operatormetrics.RecordInjectionSkipped(string(TypeGo), skipReasonMissingTargetExe)
 				pod = origPod
+			} else {
+				operatormetrics.RecordInjection(string(TypeGo))
 			}
 		}
 	}
@@ -222,6 +247,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 			pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, apacheAgentInitContainerName)
 			pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, apacheAgentCloneContainerName)
+			operatormetrics.RecordInjection(languageApacheHttpd)
 		}
 	}
 
@@ -239,6 +265,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod = injectNginxSDK(i.logger, otelinst.Spec.Nginx, pod, index, otelinst.Spec.Endpoint, resMap)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+			operatormetrics.RecordInjection(languageNginx)
 		}
 	}
 
@@ -252,6 +279,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			index := getContainerIndex(container, pod)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+			operatormetrics.RecordInjection(languageSdk)
 		}
 	}
 
@@ -535,7 +563,9 @@ func (i *sdkInjector) createResourceMap(ctx context.Context, otelinst v1alpha1.I
 	k8sResources[semconv.K8SPodUIDKey] = string(pod.UID)
 	k8sResources[semconv.K8SNodeNameKey] = pod.Spec.NodeName
 	k8sResources[semconv.ServiceInstanceIDKey] = createServiceInstanceId(ns.Name, pod.Name, pod.Spec.Containers[index].Name)
-	i.addParentResourceLabels(ctx, otelinst.Spec.Resource.AddK8sUIDAttributes, ns, pod.ObjectMeta, k8sResources)
+	for k, v := range i.owners.Resolve(ctx, ns.Name, pod.OwnerReferences).Attributes(otelinst.Spec.Resource.AddK8sUIDAttributes) {
+		k8sResources[k] = v
+	}
 	for k, v := range k8sResources {
 		if !existingRes[string(k)] && v != "" {
 			res[string(k)] = v
@@ -544,62 +574,6 @@ func (i *sdkInjector) createResourceMap(ctx context.Context, otelinst v1alpha1.I
 	return res, existingRes
 }
 
-func (i *sdkInjector) addParentResourceLabels(ctx context.Context, uid bool, ns corev1.Namespace, objectMeta metav1.ObjectMeta, resources map[attribute.Key]string) {
-	for _, owner := range objectMeta.OwnerReferences {
-		switch strings.ToLower(owner.Kind) {
-		case "replicaset":
-			resources[semconv.K8SReplicaSetNameKey] = owner.Name
-			if uid {
-				resources[semconv.K8SReplicaSetUIDKey] = string(owner.UID)
-			}
-			// parent of ReplicaSet is e.g. Deployment which we are interested to know
-			rs := appsv1.ReplicaSet{}
-			nsn := types.NamespacedName{Namespace: ns.Name, Name: owner.Name}
-			backOff := wait.Backoff{Duration: 10 * time.Millisecond, Factor: 1.5, Jitter: 0.1, Steps: 20, Cap: 2 * time.Second}
-
-			checkError := func(err error) bool {
-				return apierrors.IsNotFound(err)
-			}
-
-			getReplicaSet := func() error {
-				return i.client.Get(ctx, nsn, &rs)
-			}
-
-			// use a retry loop to get the Deployment. A single call to client.get fails occasionally
-			err := retry.OnError(backOff, checkError, getReplicaSet)
-			if err != nil {
-				i.logger.Error(err, "failed to get replicaset", "replicaset", nsn.Name, "namespace", nsn.Namespace)
-			}
-			i.addParentResourceLabels(ctx, uid, ns, rs.ObjectMeta, resources)
-		case "deployment":
-			resources[semconv.K8SDeploymentNameKey] = owner.Name
-			if uid {
-				resources[semconv.K8SDeploymentUIDKey] = string(owner.UID)
-			}
-		case "statefulset":
-			resources[semconv.K8SStatefulSetNameKey] = owner.Name
-			if uid {
-				resources[semconv.K8SStatefulSetUIDKey] = string(owner.UID)
-			}
-		case "daemonset":
-			resources[semconv.K8SDaemonSetNameKey] = owner.Name
-			if uid {
-				resources[semconv.K8SDaemonSetUIDKey] = string(owner.UID)
-			}
-		case "job":
-			resources[semconv.K8SJobNameKey] = owner.Name
-			if uid {
-				resources[semconv.K8SJobUIDKey] = string(owner.UID)
-			}
-		case "cronjob":
-			resources[semconv.K8SCronJobNameKey] = owner.Name
-			if uid {
-				resources[semconv.K8SCronJobUIDKey] = string(owner.UID)
-			}
-		}
-	}
-}
-
 func resourceMapToStr(res map[string]string) string {
 	keys := make([]string, 0, len(res))
 	for k := range res {
@@ -19,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
 )
 
 var defaultVolumeLimitSize = resource.MustParse("200Mi")
@@ -469,6 +470,7 @@ func TestSDKInjection(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			inj := sdkInjector{
 				client: k8sClient,
+				owners: owners.NewResolver(logr.Discard(), k8sClient),
 			}
 			pod := inj.injectCommonSDKConfig(context.Background(), test.inst, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: test.pod.Namespace}}, test.pod, 0, 0)
 			_, err = json.MarshalIndent(pod, "", "  ")
@@ -2120,6 +2122,7 @@ func TestInjectWithEnvFrom(t *testing.T) {
 	inj := sdkInjector{
 		logger: logr.Discard(),
 		client: k8sClient,
+		owners: owners.NewResolver(logr.Discard(), k8sClient),
 	}
 
 	tests := []struct {
@@ -2265,6 +2268,7 @@ func TestSkipInjection(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			inj := sdkInjector{
 				client: k8sClient,
+				owners: owners.NewResolver(logr.Discard(), k8sClient),
 			}
 			pod := inj.inject(context.Background(), insts, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: test.pod.Namespace}}, test.pod)
 			_, err := json.MarshalIndent(pod, "", "  ")
@@ -11,22 +11,17 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
 const resourceAttributesEnvName = "OTEL_RESOURCE_ATTRIBUTES"
 
-type podReferences struct {
-	replicaset *appsv1.ReplicaSet
-	deployment *appsv1.Deployment
-}
-
 // getResourceAttributesEnv returns a list of environment variables. The list contains OTEL_RESOURCE_ATTRIBUTES and additional environment variables that use Kubernetes downward API to read pod specification.
 // see: https://kubernetes.io/docs/tasks/inject-data-application/environment-variable-expose-pod-information/
-func getResourceAttributesEnv(ns corev1.Namespace, podReferences podReferences) []corev1.EnvVar {
+func getResourceAttributesEnv(ns corev1.Namespace, podOwners owners.Owners) []corev1.EnvVar {
 
 	var envvars []corev1.EnvVar
 
@@ -37,14 +32,8 @@ func getResourceAttributesEnv(ns corev1.Namespace, podReferences podReferences)
 		semconv.K8SNamespaceNameKey: ns.Name,
 	}
 
-	if podReferences.deployment != nil {
-		attributes[semconv.K8SDeploymentUIDKey] = string(podReferences.deployment.UID)
-		attributes[semconv.K8SDeploymentNameKey] = string(podReferences.deployment.Name)
-	}
-
-	if podReferences.replicaset != nil {
-		attributes[semconv.K8SReplicaSetUIDKey] = string(podReferences.replicaset.UID)
-		attributes[semconv.K8SReplicaSetNameKey] = string(podReferences.replicaset.Name)
+	for k, v := range podOwners.Attributes(true) {
+		attributes[k] = v
 	}
 
 	envvars = append(envvars, corev1.EnvVar{
@@ -9,10 +9,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
-	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
@@ -22,7 +22,7 @@ func TestGetAttributesEnvNoPodReferences(t *testing.T) {
 			Name: "my-ns",
 		},
 	}
-	references := podReferences{}
+	references := owners.Owners{}
 	envs := getResourceAttributesEnv(ns, references)
 
 	expectedEnv := []corev1.EnvVar{
@@ -73,18 +73,14 @@ func TestGetAttributesEnvWithPodReferences(t *testing.T) {
 			Name: "my-ns",
 		},
 	}
-	references := podReferences{
-		deployment: &appv1.Deployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "my-deployment",
-				UID:  "uuid-dep",
-			},
+	references := owners.Owners{
+		Deployment: &owners.Owner{
+			Name: "my-deployment",
+			UID:  "uuid-dep",
 		},
-		replicaset: &appv1.ReplicaSet{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "my-replicaset",
-				UID:  "uuid-replicaset",
-			},
+		ReplicaSet: &owners.Owner{
+			Name: "my-replicaset",
+			UID:  "uuid-replicaset",
 		},
 	}
 	envs := getResourceAttributesEnv(ns, references)
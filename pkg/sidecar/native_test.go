@@ -0,0 +1,152 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sidecar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func nativeSidecarAgent() v1alpha1.AmazonCloudWatchAgent {
+	return v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "some-app"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:           v1alpha1.ModeSidecar,
+			NativeSidecar:  true,
+			InitContainers: []corev1.Container{{Name: "agent-init"}},
+			Ports:          []corev1.ServicePort{{Name: "otlp-grpc", Port: 4317, Protocol: corev1.ProtocolTCP}},
+			Config:         `{}`,
+		},
+	}
+}
+
+func TestAddNativeSidecar(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "my-init"}},
+			Containers:     []corev1.Container{{Name: "my-app"}},
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"), config.WithNativeSidecarSupported(true))
+
+	changed, err := add(cfg, logf.Log, nativeSidecarAgent(), pod, nil)
+	require.NoError(t, err)
+
+	require.Len(t, changed.Spec.Containers, 1)
+	require.Len(t, changed.Spec.InitContainers, 3)
+	assert.Equal(t, "my-init", changed.Spec.InitContainers[0].Name)
+	assert.Equal(t, "agent-init", changed.Spec.InitContainers[1].Name)
+	sidecar := changed.Spec.InitContainers[2]
+	assert.Equal(t, naming.Container(), sidecar.Name)
+	require.NotNil(t, sidecar.RestartPolicy)
+	assert.Equal(t, corev1.ContainerRestartPolicyAlways, *sidecar.RestartPolicy)
+	require.NotNil(t, sidecar.StartupProbe)
+	assert.Equal(t, &corev1.TCPSocketAction{Port: intstr.FromInt32(4317)}, sidecar.StartupProbe.TCPSocket)
+
+	assert.True(t, ExistsIn(changed))
+}
+
+func TestAddNativeSidecarStartupProbeTCPPort(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}}}
+	cfg := config.New(config.WithCollectorImage("some-default-image"), config.WithNativeSidecarSupported(true))
+
+	for _, tc := range []struct {
+		name     string
+		ports    []corev1.ServicePort
+		config   string
+		expected *corev1.TCPSocketAction
+	}{
+		{
+			name:   "statsd only",
+			config: `{"metrics":{"metrics_collected":{"statsd":{}}}}`,
+		},
+		{
+			name: "first TCP port",
+			ports: []corev1.ServicePort{
+				{Name: "statsd", Port: 8125, Protocol: corev1.ProtocolUDP},
+				{Name: "otlp-grpc", Port: 4317, Protocol: corev1.ProtocolTCP},
+			},
+			config:   `{}`,
+			expected: &corev1.TCPSocketAction{Port: intstr.FromInt32(4317)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			agent := nativeSidecarAgent()
+			agent.Spec.Ports = tc.ports
+			agent.Spec.Config = tc.config
+
+			changed, err := add(cfg, logf.Log, agent, pod, nil)
+			require.NoError(t, err)
+
+			sidecar := changed.Spec.InitContainers[len(changed.Spec.InitContainers)-1]
+			require.NotEmpty(t, sidecar.Ports)
+			if tc.expected == nil {
+				assert.Nil(t, sidecar.StartupProbe)
+				return
+			}
+			require.NotNil(t, sidecar.StartupProbe)
+			assert.Equal(t, tc.expected, sidecar.StartupProbe.TCPSocket)
+		})
+	}
+}
+
+func TestAddNativeSidecarNotSupported(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}}}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	changed, err := add(cfg, logf.Log, nativeSidecarAgent(), pod, nil)
+	require.NoError(t, err)
+
+	require.Len(t, changed.Spec.Containers, 2)
+	sidecar := changed.Spec.Containers[1]
+	assert.Equal(t, naming.Container(), sidecar.Name)
+	assert.Nil(t, sidecar.RestartPolicy)
+	assert.Nil(t, sidecar.StartupProbe)
+	require.Len(t, changed.Spec.InitContainers, 1)
+	assert.Equal(t, "agent-init", changed.Spec.InitContainers[0].Name)
+}
+
+func TestRemoveNativeSidecar(t *testing.T) {
+	policy := corev1.ContainerRestartPolicyAlways
+	for _, tt := range []struct {
+		desc string
+		pod  corev1.Pod
+	}{
+		{
+			desc: "container",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "my-init"}},
+				Containers:     []corev1.Container{{Name: "my-app"}, {Name: naming.Container()}},
+			}},
+		},
+		{
+			desc: "native sidecar",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "my-init"}, {Name: naming.Container(), RestartPolicy: &policy}},
+				Containers:     []corev1.Container{{Name: "my-app"}},
+			}},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.True(t, ExistsIn(tt.pod))
+
+			changed, err := remove(tt.pod)
+			require.NoError(t, err)
+
+			assert.False(t, ExistsIn(changed))
+			assert.Equal(t, []corev1.Container{{Name: "my-init"}}, changed.Spec.InitContainers)
+			assert.Equal(t, []corev1.Container{{Name: "my-app"}}, changed.Spec.Containers)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sidecar
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+const (
+	// ResourcesAnnotation holds the JSON resource requirements of the sidecar, the limits and requests it sets
+	// replacing the ones of the AmazonCloudWatchAgent.
+	ResourcesAnnotation = "sidecar.cloudwatch.aws.amazon.com/resources"
+	// ConfigPatchAnnotation holds a JSON merge patch applied to the agent configuration of the sidecar.
+	ConfigPatchAnnotation = "sidecar.cloudwatch.aws.amazon.com/config-patch"
+	// EnvAnnotation holds a JSON object of the environment variables of the sidecar, replacing the ones of the
+	// AmazonCloudWatchAgent with the same name.
+	EnvAnnotation = "sidecar.cloudwatch.aws.amazon.com/env"
+)
+
+// applyOverrides applies the overrides annotated on the pod to the AmazonCloudWatchAgent the sidecar is injected from.
+// An annotation which is invalid is left out, and its error returned.
+func applyOverrides(pod corev1.Pod, otelcol *v1alpha1.AmazonCloudWatchAgent) []error {
+	var errs []error
+	if value, ok := pod.Annotations[ResourcesAnnotation]; ok {
+		if err := overrideResources(value, otelcol); err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %s: %w", ResourcesAnnotation, err))
+		}
+	}
+	if value, ok := pod.Annotations[ConfigPatchAnnotation]; ok {
+		if err := overrideConfig(value, otelcol); err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %s: %w", ConfigPatchAnnotation, err))
+		}
+	}
+	if value, ok := pod.Annotations[EnvAnnotation]; ok {
+		if err := overrideEnv(value, otelcol); err != nil {
+			errs = append(errs, fmt.Errorf("invalid annotation %s: %w", EnvAnnotation, err))
+		}
+	}
+	return errs
+}
+
+func overrideResources(value string, otelcol *v1alpha1.AmazonCloudWatchAgent) error {
+	var resources corev1.ResourceRequirements
+	if err := json.Unmarshal([]byte(value), &resources); err != nil {
+		return err
+	}
+	merged := otelcol.Spec.Resources.DeepCopy()
+	merged.Limits = mergeResourceList(merged.Limits, resources.Limits)
+	merged.Requests = mergeResourceList(merged.Requests, resources.Requests)
+	for name, request := range merged.Requests {
+		if limit, ok := merged.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("the %s request %s is greater than the limit %s", name, request.String(), limit.String())
+		}
+	}
+	otelcol.Spec.Resources = *merged
+	return nil
+}
+
+func mergeResourceList(dst, src corev1.ResourceList) corev1.ResourceList {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = corev1.ResourceList{}
+	}
+	for name, quantity := range src {
+		dst[name] = quantity
+	}
+	return dst
+}
+
+func overrideConfig(value string, otelcol *v1alpha1.AmazonCloudWatchAgent) error {
+	var patch map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &patch); err != nil {
+		return fmt.Errorf("the merge patch isn't a JSON object: %w", err)
+	}
+	config := otelcol.Spec.Config
+	if config == "" {
+		config = "{}"
+	}
+	patched, err := jsonpatch.MergePatch([]byte(config), []byte(value))
+	if err != nil {
+		return err
+	}
+	if _, err := adapters.ValidateConfigSchema(string(patched)); err != nil {
+		return err
+	}
+	otelcol.Spec.Config = string(patched)
+	return nil
+}
+
+func overrideEnv(value string, otelcol *v1alpha1.AmazonCloudWatchAgent) error {
+	var env map[string]string
+	if err := json.Unmarshal([]byte(value), &env); err != nil {
+		return fmt.Errorf("the environment variables aren't a JSON object of strings: %w", err)
+	}
+	names := make([]string, 0, len(env))
+	for name := range env {
+		if name == confEnvVar {
+			return fmt.Errorf("the environment variable %s is set by the operator", confEnvVar)
+		}
+		if msgs := validation.IsEnvVarName(name); len(msgs) > 0 {
+			return fmt.Errorf("invalid environment variable name %q: %s", name, msgs[0])
+		}
+		names = append(names, name)
+	}
+	// sorted, so that the pods of a workload get the same sidecar
+	sort.Strings(names)
+
+	merged := make([]corev1.EnvVar, 0, len(otelcol.Spec.Env)+len(env))
+	for _, envVar := range otelcol.Spec.Env {
+		if _, ok := env[envVar.Name]; !ok {
+			merged = append(merged, envVar)
+		}
+	}
+	for _, name := range names {
+		merged = append(merged, corev1.EnvVar{Name: name, Value: env[name]})
+	}
+	otelcol.Spec.Env = merged
+	return nil
+}
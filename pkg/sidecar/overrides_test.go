@@ -0,0 +1,181 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sidecar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
+)
+
+func overridesAgent() v1alpha1.AmazonCloudWatchAgent {
+	return v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:   v1alpha1.ModeSidecar,
+			Config: `{"agent":{"region":"us-west-2"},"traces":{"traces_collected":{"xray":{}}}}`,
+			Env:    []corev1.EnvVar{{Name: "FOO", Value: "foo"}, {Name: "BAR", Value: "bar"}},
+			Resources: corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+			},
+		},
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		ResourcesAnnotation:   `{"limits":{"memory":"512Mi"},"requests":{"cpu":"200m"}}`,
+		ConfigPatchAnnotation: `{"agent":{"debug":true},"traces":null}`,
+		EnvAnnotation:         `{"FOO":"overridden","BAZ":"baz"}`,
+	}}}
+	otelcol := overridesAgent()
+
+	errs := applyOverrides(pod, &otelcol)
+	require.Empty(t, errs)
+
+	assert.Equal(t, corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+	}, otelcol.Spec.Resources)
+	assert.JSONEq(t, `{"agent":{"region":"us-west-2","debug":true}}`, otelcol.Spec.Config)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "BAR", Value: "bar"},
+		{Name: "BAZ", Value: "baz"},
+		{Name: "FOO", Value: "overridden"},
+	}, otelcol.Spec.Env)
+}
+
+func TestApplyOverridesInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		desc        string
+		annotations map[string]string
+		expectedErr string
+	}{
+		{
+			desc:        "resources not json",
+			annotations: map[string]string{ResourcesAnnotation: `cpu=1`},
+			expectedErr: "invalid annotation sidecar.cloudwatch.aws.amazon.com/resources",
+		},
+		{
+			desc:        "request greater than limit",
+			annotations: map[string]string{ResourcesAnnotation: `{"requests":{"cpu":"1"}}`},
+			expectedErr: "invalid annotation sidecar.cloudwatch.aws.amazon.com/resources: the cpu request 1 is greater than the limit 500m",
+		},
+		{
+			desc:        "config patch not an object",
+			annotations: map[string]string{ConfigPatchAnnotation: `["agent"]`},
+			expectedErr: "invalid annotation sidecar.cloudwatch.aws.amazon.com/config-patch: the merge patch isn't a JSON object",
+		},
+		{
+			desc:        "config patch against the schema",
+			annotations: map[string]string{ConfigPatchAnnotation: `{"agent":{"debug":"yes"}}`},
+			expectedErr: "invalid annotation sidecar.cloudwatch.aws.amazon.com/config-patch: agent.debug: expected boolean, but got string",
+		},
+		{
+			desc:        "env not an object of strings",
+			annotations: map[string]string{EnvAnnotation: `{"FOO":1}`},
+			expectedErr: "invalid annotation sidecar.cloudwatch.aws.amazon.com/env: the environment variables aren't a JSON object of strings",
+		},
+		{
+			desc:        "env invalid name",
+			annotations: map[string]string{EnvAnnotation: `{"1FOO":"foo"}`},
+			expectedErr: `invalid annotation sidecar.cloudwatch.aws.amazon.com/env: invalid environment variable name "1FOO"`,
+		},
+		{
+			desc:        "env overriding the config",
+			annotations: map[string]string{EnvAnnotation: `{"OTEL_CONFIG":"{}"}`},
+			expectedErr: "invalid annotation sidecar.cloudwatch.aws.amazon.com/env: the environment variable OTEL_CONFIG is set by the operator",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			otelcol := overridesAgent()
+
+			errs := applyOverrides(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}, &otelcol)
+			require.Len(t, errs, 1)
+			assert.ErrorContains(t, errs[0], tt.expectedErr)
+			// the invalid override is left out
+			assert.Equal(t, overridesAgent(), otelcol)
+		})
+	}
+}
+
+func TestMutateRecordsRejectedOverrides(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	otelcol := overridesAgent()
+	otelcol.ObjectMeta = metav1.ObjectMeta{Name: "agent", Namespace: "my-ns"}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "my-app-5d8f", Namespace: "my-ns", UID: "rs-uid",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "my-app", UID: "deploy-uid", Controller: ptr.To(true)}},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&otelcol, replicaSet).Build()
+
+	for _, tt := range []struct {
+		desc     string
+		pod      metav1.ObjectMeta
+		expected string
+	}{
+		{
+			desc: "pod of a workload",
+			// the pods of a workload only have a generated name at admission
+			pod: metav1.ObjectMeta{GenerateName: "my-app-5d8f-", OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "my-app-5d8f", UID: "rs-uid", Controller: ptr.To(true),
+			}}},
+			expected: "involvedObject{kind=Deployment,apiVersion=apps/v1}",
+		},
+		{
+			desc:     "named pod",
+			pod:      metav1.ObjectMeta{Name: "my-app"},
+			expected: "involvedObject{kind=Pod,apiVersion=v1}",
+		},
+		{
+			desc: "pod with a generated name only",
+			pod:  metav1.ObjectMeta{GenerateName: "my-app-"},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			recorder.IncludeObject = true
+			mutator := NewMutator(logr.Discard(), config.New(), c, owners.NewResolver(logr.Discard(), c), recorder)
+
+			pod := corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: tt.pod,
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}},
+			}
+			pod.Annotations = map[string]string{Annotation: "agent", EnvAnnotation: `{"1FOO":"foo"}`}
+			changed, err := mutator.Mutate(context.Background(), corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns"}}, pod)
+			require.NoError(t, err)
+
+			// the sidecar is still injected, without the invalid override
+			require.Len(t, changed.Spec.Containers, 2)
+			assert.Equal(t, naming.Container(), changed.Spec.Containers[1].Name)
+			if tt.expected == "" {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			event := <-recorder.Events
+			assert.Contains(t, event, "Warning SidecarOverrideRejected invalid annotation sidecar.cloudwatch.aws.amazon.com/env")
+			assert.Contains(t, event, tt.expected)
+		})
+	}
+}
@@ -5,10 +5,14 @@
 package sidecar
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
@@ -17,8 +21,16 @@ import (
 )
 
 const (
-	injectedLabel = "sidecar.opentelemetry.io/injected"
-	confEnvVar    = "OTEL_CONFIG"
+	// InjectedLabel labels the pods with an injected sidecar with the namespace and name of the AmazonCloudWatchAgent it
+	// was injected from, truncated to fit a label value.
+	InjectedLabel = "sidecar.opentelemetry.io/injected"
+	// SourceAnnotation is the namespaced name of the AmazonCloudWatchAgent the sidecar of the pod was injected from.
+	SourceAnnotation = "sidecar.cloudwatch.aws.amazon.com/source"
+	// SourceHashAnnotation is the hash of the sidecar injected from the AmazonCloudWatchAgent, before the overrides of
+	// the pod, as returned by Hash.
+	SourceHashAnnotation = "sidecar.cloudwatch.aws.amazon.com/source-hash"
+
+	confEnvVar = "OTEL_CONFIG"
 )
 
 // add a new sidecar container to the given pod, based on the given AmazonCloudWatchAgent.
@@ -36,39 +48,116 @@ func add(cfg config.Config, logger logr.Logger, otelcol v1alpha1.AmazonCloudWatc
 		container.Env = append(container.Env, attributes...)
 	}
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, otelcol.Spec.InitContainers...)
-	pod.Spec.Containers = append(pod.Spec.Containers, container)
+	if otelcol.Spec.NativeSidecar && cfg.NativeSidecarSupported() {
+		// a native sidecar is started before the containers of the pod, which only start once its startup probe
+		// succeeds, and it is stopped after them
+		policy := corev1.ContainerRestartPolicyAlways
+		container.RestartPolicy = &policy
+		container.StartupProbe = startupProbe(container)
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, container)
+	} else {
+		if otelcol.Spec.NativeSidecar {
+			logger.Info("the API server doesn't support native sidecars, injecting the sidecar as a container")
+		}
+		pod.Spec.Containers = append(pod.Spec.Containers, container)
+	}
 	pod.Spec.Volumes = append(pod.Spec.Volumes, otelcol.Spec.Volumes...)
 
 	if pod.Labels == nil {
 		pod.Labels = map[string]string{}
 	}
-	pod.Labels[injectedLabel] = naming.Truncate("%s.%s", 63, otelcol.Namespace, otelcol.Name)
+	pod.Labels[InjectedLabel] = InjectedLabelValue(otelcol)
+	// the source of the sidecar tells the stale sidecars apart, once the AmazonCloudWatchAgent is deleted or changed
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[SourceAnnotation] = client.ObjectKeyFromObject(&otelcol).String()
 
 	return pod, nil
 }
 
-// remove the sidecar container from the given pod.
+// remove the sidecar container from the given pod, injected either as a container or as a native sidecar.
 func remove(pod corev1.Pod) (corev1.Pod, error) {
-	if !existsIn(pod) {
+	if !ExistsIn(pod) {
 		return pod, nil
 	}
 
-	var containers []corev1.Container
-	for _, container := range pod.Spec.Containers {
+	pod.Spec.Containers = withoutSidecar(pod.Spec.Containers)
+	pod.Spec.InitContainers = withoutSidecar(pod.Spec.InitContainers)
+	return removeInjectedMetadata(pod), nil
+}
+
+// removeInjectedMetadata removes the label and annotations describing the sidecar from the given pod.
+func removeInjectedMetadata(pod corev1.Pod) corev1.Pod {
+	delete(pod.Labels, InjectedLabel)
+	delete(pod.Annotations, SourceAnnotation)
+	delete(pod.Annotations, SourceHashAnnotation)
+	return pod
+}
+
+// Hash returns the hash of the containers and volumes the AmazonCloudWatchAgent injects in a pod, so that only the
+// changes of its spec affecting the sidecar make the injected sidecars stale.
+func Hash(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) (string, error) {
+	injected, err := add(cfg, logr.Discard(), otelcol, corev1.Pod{}, nil)
+	if err != nil {
+		return "", err
+	}
+	spec, err := json.Marshal(injected.Spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(spec)), nil
+}
+
+// InjectedLabelValue returns the value of the InjectedLabel of the pods with a sidecar injected from the given
+// AmazonCloudWatchAgent.
+func InjectedLabelValue(otelcol v1alpha1.AmazonCloudWatchAgent) string {
+	return naming.Truncate("%s.%s", 63, otelcol.Namespace, otelcol.Name)
+}
+
+func withoutSidecar(containers []corev1.Container) []corev1.Container {
+	var filtered []corev1.Container
+	for _, container := range containers {
 		if container.Name != naming.Container() {
-			containers = append(containers, container)
+			filtered = append(filtered, container)
 		}
 	}
-	pod.Spec.Containers = containers
-	return pod, nil
+	return filtered
 }
 
-// existsIn checks whether a sidecar container exists in the given pod.
-func existsIn(pod corev1.Pod) bool {
+// ExistsIn checks whether a sidecar container exists in the given pod, either as a container or as a native sidecar.
+func ExistsIn(pod corev1.Pod) bool {
 	for _, container := range pod.Spec.Containers {
 		if container.Name == naming.Container() {
 			return true
 		}
 	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == naming.Container() {
+			return true
+		}
+	}
 	return false
 }
+
+// startupProbe returns the probe telling when the native sidecar is started: its liveness probe when the agent
+// exposes a health check, or else a check that it listens on its first TCP port. There is none when the agent only
+// listens on UDP ports, such as for statsd, which a TCP check would never reach.
+func startupProbe(container corev1.Container) *corev1.Probe {
+	if container.LivenessProbe != nil {
+		return container.LivenessProbe.DeepCopy()
+	}
+	for _, port := range container.Ports {
+		if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(port.ContainerPort)},
+			},
+			PeriodSeconds:    1,
+			FailureThreshold: 30,
+		}
+	}
+	return nil
+}
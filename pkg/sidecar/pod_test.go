@@ -103,7 +103,7 @@ processors:
 }
 
 // this situation should never happen in the current code path, but it should not fail
-// if it's asked to add a new sidecar. The caller is expected to have called existsIn before.
+// if it's asked to add a new sidecar. The caller is expected to have called ExistsIn before.
 func TestAddSidecarWhenOneExistsAlready(t *testing.T) {
 	// prepare
 	pod := corev1.Pod{
@@ -189,7 +189,7 @@ func TestExistsIn(t *testing.T) {
 			false},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
-			assert.Equal(t, tt.expected, existsIn(tt.pod))
+			assert.Equal(t, tt.expected, ExistsIn(tt.pod))
 		})
 	}
 }
@@ -10,13 +10,19 @@ import (
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/configsource"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 )
 
@@ -27,18 +33,22 @@ var (
 )
 
 type sidecarPodMutator struct {
-	client client.Client
-	logger logr.Logger
-	config config.Config
+	client   client.Client
+	logger   logr.Logger
+	config   config.Config
+	owners   *owners.Resolver
+	recorder record.EventRecorder
 }
 
 var _ podmutation.PodMutator = (*sidecarPodMutator)(nil)
 
-func NewMutator(logger logr.Logger, config config.Config, client client.Client) *sidecarPodMutator {
+func NewMutator(logger logr.Logger, config config.Config, client client.Client, ownerResolver *owners.Resolver, recorder record.EventRecorder) *sidecarPodMutator {
 	return &sidecarPodMutator{
-		config: config,
-		logger: logger,
-		client: client,
+		config:   config,
+		logger:   logger,
+		client:   client,
+		owners:   ownerResolver,
+		recorder: recorder,
 	}
 }
 
@@ -48,6 +58,11 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 	// if no annotations are found at all, just return the same pod
 	annValue := annotationValue(ns, pod)
 	if len(annValue) == 0 {
+		// a pod created from the template of an injected pod carries its label, without the sidecar
+		if _, ok := pod.Labels[InjectedLabel]; ok && !ExistsIn(pod) {
+			logger.V(1).Info("annotation not present in deployment, removing the stale sidecar label")
+			return removeInjectedMetadata(pod), nil
+		}
 		logger.V(1).Info("annotation not present in deployment, skipping sidecar injection")
 		return pod, nil
 	}
@@ -60,7 +75,7 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 
 	// from this point and on, a sidecar is wanted
 	// check whether there's a sidecar already -- return the same pod if that's the case.
-	if existsIn(pod) {
+	if ExistsIn(pod) {
 		logger.V(1).Info("pod already has sidecar in it, skipping injection")
 		return pod, nil
 	}
@@ -77,16 +92,73 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 		// something else happened, better fail here
 		return pod, err
 	}
+	// the sidecar receives the configuration inline, so the one read through configFrom is resolved first
+	if err := configsource.ResolveGenerated(ctx, p.client, p.config, &otelcol); err != nil {
+		return pod, err
+	}
+	// the sidecar of the AmazonCloudWatchAgent is hashed before the overrides, which only apply to this pod
+	hash, err := Hash(p.config, otelcol)
+	if err != nil {
+		return pod, err
+	}
+	// the overrides annotated on the pod tune the sidecar of this pod only, an invalid one is left out
+	rejected := applyOverrides(pod, &otelcol)
+
+	// getting the workloads owning the pod, if any
+	podOwners := p.owners.Resolve(ctx, ns.Name, pod.OwnerReferences)
 
-	// getting pod references, if any
-	references := p.podReferences(ctx, pod.OwnerReferences, ns)
-	attributes := getResourceAttributesEnv(ns, references)
+	// the pod of a workload has no name yet, the rejected overrides are reported on the workload instead
+	eventTarget := overridesEventTarget(ns.Name, pod, podOwners)
+	for _, err := range rejected {
+		logger.Info("ignoring sidecar override", "reason", err.Error())
+		if eventTarget != nil {
+			p.recorder.Event(eventTarget, corev1.EventTypeWarning, "SidecarOverrideRejected", err.Error())
+		}
+	}
+	attributes := getResourceAttributesEnv(ns, podOwners)
 
 	// once it's been determined that a sidecar is desired, none exists yet, and we know which instance it should talk to,
 	// we should add the sidecar.
 	logger.V(1).Info("injecting sidecar into pod", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
 
-	return add(p.config, p.logger, otelcol, pod, attributes)
+	pod, err = add(p.config, p.logger, otelcol, pod, attributes)
+	if err != nil {
+		return pod, err
+	}
+	pod.Annotations[SourceHashAnnotation] = hash
+	return pod, nil
+}
+
+// overridesEventTarget returns the object the rejected overrides of the pod are reported on: the workload owning the
+// pod, or else the pod when it is named. Nil is returned for a pod only having a generated name, which isn't known at
+// admission.
+func overridesEventTarget(namespace string, pod corev1.Pod, podOwners owners.Owners) client.Object {
+	for _, owner := range []struct {
+		owner *owners.Owner
+		gvk   schema.GroupVersionKind
+	}{
+		{podOwners.Deployment, appsv1.SchemeGroupVersion.WithKind("Deployment")},
+		{podOwners.StatefulSet, appsv1.SchemeGroupVersion.WithKind("StatefulSet")},
+		{podOwners.DaemonSet, appsv1.SchemeGroupVersion.WithKind("DaemonSet")},
+		{podOwners.CronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")},
+		{podOwners.Job, batchv1.SchemeGroupVersion.WithKind("Job")},
+		{podOwners.ReplicaSet, appsv1.SchemeGroupVersion.WithKind("ReplicaSet")},
+	} {
+		if owner.owner == nil {
+			continue
+		}
+		obj := &metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: owner.owner.Name, UID: owner.owner.UID},
+		}
+		obj.SetGroupVersionKind(owner.gvk)
+		return obj
+	}
+	if pod.Name == "" {
+		return nil
+	}
+	target := pod.DeepCopy()
+	target.Namespace = namespace
+	return target
 }
 
 func (p *sidecarPodMutator) getCollectorInstance(ctx context.Context, ns corev1.Namespace, ann string) (v1alpha1.AmazonCloudWatchAgent, error) {
@@ -103,6 +175,10 @@ func (p *sidecarPodMutator) getCollectorInstance(ctx context.Context, ns corev1.
 		nsnOtelcol = types.NamespacedName{Name: ann, Namespace: ns.Name}
 	}
 	err := p.client.Get(ctx, nsnOtelcol, &otelcol)
+	if apierrors.IsNotFound(err) {
+		// the instance was deleted, the pods restarted to remove its sidecars are created without one
+		return v1alpha1.AmazonCloudWatchAgent{}, errNoInstancesAvailable
+	}
 	if err != nil {
 		return otelcol, err
 	}
@@ -140,49 +216,3 @@ func (p *sidecarPodMutator) selectCollectorInstance(ctx context.Context, ns core
 		return sidecars[0], nil
 	}
 }
-
-func (p *sidecarPodMutator) podReferences(ctx context.Context, ownerReferences []metav1.OwnerReference, ns corev1.Namespace) podReferences {
-	references := &podReferences{}
-	replicaSet := p.getReplicaSetReference(ctx, ownerReferences, ns)
-	if replicaSet != nil {
-		references.replicaset = replicaSet
-		deployment := p.getDeploymentReference(ctx, replicaSet)
-		if deployment != nil {
-			references.deployment = deployment
-		}
-	}
-	return *references
-}
-
-func (p *sidecarPodMutator) getReplicaSetReference(ctx context.Context, ownerReferences []metav1.OwnerReference, ns corev1.Namespace) *appsv1.ReplicaSet {
-	replicaSetName := findOwnerReferenceKind(ownerReferences, "ReplicaSet")
-	if replicaSetName != "" {
-		replicaSet := &appsv1.ReplicaSet{}
-		err := p.client.Get(ctx, types.NamespacedName{Name: replicaSetName, Namespace: ns.Name}, replicaSet)
-		if err == nil {
-			return replicaSet
-		}
-	}
-	return nil
-}
-
-func (p *sidecarPodMutator) getDeploymentReference(ctx context.Context, replicaSet *appsv1.ReplicaSet) *appsv1.Deployment {
-	deploymentName := findOwnerReferenceKind(replicaSet.OwnerReferences, "Deployment")
-	if deploymentName != "" {
-		deployment := &appsv1.Deployment{}
-		err := p.client.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: replicaSet.Namespace}, deployment)
-		if err == nil {
-			return deployment
-		}
-	}
-	return nil
-}
-
-func findOwnerReferenceKind(references []metav1.OwnerReference, kind string) string {
-	for _, reference := range references {
-		if reference.Kind == kind {
-			return reference.Name
-		}
-	}
-	return ""
-}
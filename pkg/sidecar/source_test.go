@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sidecar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/owners"
+)
+
+func newSourceMutator(t *testing.T) *sidecarPodMutator {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	return NewMutator(logr.Discard(), config.New(), c, owners.NewResolver(logr.Discard(), c), record.NewFakeRecorder(10))
+}
+
+func TestAddRecordsSource(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "agents", Generation: 3},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeSidecar, Config: `{}`},
+	}
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}}}
+
+	changed, err := add(config.New(config.WithCollectorImage("some-default-image")), logf.Log, otelcol, pod, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{InjectedLabel: "agents.agent"}, changed.Labels)
+	assert.Equal(t, map[string]string{SourceAnnotation: "agents/agent"}, changed.Annotations)
+
+	removed, err := remove(changed)
+	require.NoError(t, err)
+	assert.Empty(t, removed.Labels)
+	assert.Empty(t, removed.Annotations)
+}
+
+func TestMutateRecordsSourceHash(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "my-ns", Generation: 3},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeSidecar, Config: `{}`},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&otelcol).Build()
+	mutator := NewMutator(logr.Discard(), config.New(), c, owners.NewResolver(logr.Discard(), c), record.NewFakeRecorder(10))
+	expected, err := Hash(config.New(), otelcol)
+	require.NoError(t, err)
+
+	// the overrides of the pod don't change the hash of the sidecar of the AmazonCloudWatchAgent
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Annotations: map[string]string{Annotation: "agent", EnvAnnotation: `{"FOO":"foo"}`}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}},
+	}
+	changed, err := mutator.Mutate(context.Background(), corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns"}}, pod)
+	require.NoError(t, err)
+	assert.Equal(t, expected, changed.Annotations[SourceHashAnnotation])
+
+	// changes of the spec outside the sidecar don't either, unlike the ones of the sidecar
+	otelcol.Spec.Autoscaler = &v1alpha1.AutoscalerSpec{MaxReplicas: ptr.To(int32(3))}
+	unchanged, err := Hash(config.New(), otelcol)
+	require.NoError(t, err)
+	assert.Equal(t, expected, unchanged)
+	otelcol.Spec.Env = []corev1.EnvVar{{Name: "FOO", Value: "foo"}}
+	changedHash, err := Hash(config.New(), otelcol)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected, changedHash)
+}
+
+func TestMutateRemovesStaleLabel(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{InjectedLabel: "agents.agent", "app": "my-app"},
+			Annotations: map[string]string{SourceAnnotation: "agents/agent", SourceHashAnnotation: "3f2a"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}},
+	}
+
+	changed, err := newSourceMutator(t).Mutate(context.Background(), corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns"}}, pod)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"app": "my-app"}, changed.Labels)
+	assert.Empty(t, changed.Annotations)
+}
+
+func TestMutateDeletedInstance(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Annotations: map[string]string{Annotation: "agent"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "my-app"}}},
+	}
+
+	changed, err := newSourceMutator(t).Mutate(context.Background(), corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns"}}, pod)
+	require.NoError(t, err)
+
+	// the pod is admitted without a sidecar
+	assert.False(t, ExistsIn(changed))
+	assert.NotContains(t, changed.Spec.Containers, corev1.Container{Name: naming.Container()})
+}